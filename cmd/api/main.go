@@ -2,6 +2,7 @@ package main
 
 import (
 	"customer-api/internal/config"
+	"customer-api/internal/handler"
 	"customer-api/routes"
 
 	_ "customer-api/cmd/api/docs"
@@ -24,6 +25,11 @@ func main() {
 
 	// DB
 	config.ConnectDatabase()
+	handler.MoveLegacyDocumentFiles()
+
+	// Background jobs
+	handler.StartReminderDigestScheduler()
+	handler.StartHealthScoreScheduler()
 
 	// Register all routes
 	routes.RegisterRoutes(r)
@@ -0,0 +1,260 @@
+// Package access menentukan customer mana yang boleh dilihat dan diubah oleh user
+// berdasarkan AccessPolicy milik role-nya.
+package access
+
+import (
+	"strings"
+
+	"customer-api/internal/config"
+	"customer-api/internal/entity"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Scope visibilitas customer
+const (
+	ScopeAll       = "all"
+	ScopeTeam      = "team"
+	ScopePortfolio = "portfolio"
+)
+
+const contextKey = "access_context"
+
+// Context adalah hak akses user yang sudah di-resolve
+type Context struct {
+	UserID           string   `json:"user_id"`
+	Username         string   `json:"username"`
+	RoleID           string   `json:"role_id"`
+	RoleName         string   `json:"role_name"`
+	IsAdmin          bool     `json:"is_admin"`
+	Scope            string   `json:"scope"`
+	ReadOnly         bool     `json:"read_only"`
+	PolicyID         string   `json:"policy_id,omitempty"`
+	TeamIDs          []string `json:"team_ids"`
+	AccountManagerID string   `json:"account_manager_id,omitempty"`
+}
+
+// Resolve memuat role, policy, tim dan account manager milik user
+func Resolve(db *gorm.DB, userID string) (*Context, error) {
+	ctx := &Context{UserID: userID, Scope: ScopeAll, TeamIDs: []string{}}
+
+	var user entity.User
+	if err := db.Preload("Role").Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// user sudah dihapus: tidak melihat apapun
+			ctx.Scope = ScopePortfolio
+			ctx.ReadOnly = true
+			return ctx, nil
+		}
+		return nil, err
+	}
+	ctx.Username = user.Username
+	ctx.RoleID = user.RoleID
+	ctx.RoleName = user.Role.RoleName
+	// admin ditentukan oleh flag is_admin hasil seed, bukan nama role yang bisa diubah
+	if user.Role.IsAdmin {
+		ctx.IsAdmin = true
+		return ctx, nil
+	}
+
+	var policy entity.AccessPolicy
+	if err := db.Where("role_id = ?", user.RoleID).First(&policy).Error; err == nil {
+		ctx.PolicyID = policy.ID
+		ctx.Scope = policy.Scope
+		ctx.ReadOnly = policy.ReadOnly
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if ctx.Scope == ScopeAll {
+		return ctx, nil
+	}
+
+	var accountManager entity.AccountManager
+	if err := db.Select("id").Where("user_id = ?", userID).First(&accountManager).Error; err == nil {
+		ctx.AccountManagerID = accountManager.ID
+	}
+	if ctx.Scope == ScopeTeam {
+		err := db.Raw(`SELECT teams_id FROM teams_details WHERE user_id = ? AND is_active = ? AND deleted_at IS NULL
+			UNION SELECT id FROM teams WHERE team_lead = ? AND deleted_at IS NULL`, userID, true, userID).
+			Scan(&ctx.TeamIDs).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// FromContext mengembalikan hak akses user yang sedang login, di-cache per request
+func FromContext(c *gin.Context) (*Context, error) {
+	if cached, ok := c.Get(contextKey); ok {
+		return cached.(*Context), nil
+	}
+	userID := ""
+	if value, ok := c.Get("user_id"); ok {
+		userID, _ = value.(string)
+	}
+	ctx, err := Resolve(config.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(contextKey, ctx)
+	return ctx, nil
+}
+
+// Restricted bernilai true jika user tidak boleh melihat semua customer
+func (a *Context) Restricted() bool {
+	return !a.IsAdmin && a.Scope != ScopeAll
+}
+
+// customerCondition mengembalikan kondisi SQL atas tabel customers untuk scope user
+func (a *Context) customerCondition() (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	if a.AccountManagerID != "" {
+		conditions = append(conditions, "account_manager_id = ?")
+		args = append(args, a.AccountManagerID)
+	}
+	if a.Scope == ScopeTeam && len(a.TeamIDs) > 0 {
+		conditions = append(conditions, "team_id IN ?")
+		args = append(args, a.TeamIDs)
+	}
+	if len(conditions) == 0 {
+		return "1 = 0", nil
+	}
+	return strings.Join(conditions, " OR "), args
+}
+
+// Filter membatasi query ke customer yang terlihat. column adalah kolom berisi customer ID,
+// misalnya "id" untuk tabel customers atau "customer_id" untuk tabel turunannya.
+func (a *Context) Filter(db *gorm.DB, column string) *gorm.DB {
+	if !a.Restricted() {
+		return db
+	}
+	condition, args := a.customerCondition()
+	return db.Where(column+" IN (SELECT id FROM customers WHERE deleted_at IS NULL AND ("+condition+"))", args...)
+}
+
+// CanSee memeriksa apakah customer terlihat dan mengembalikan alasannya
+func (a *Context) CanSee(customer entity.Customer) (bool, string) {
+	if a.IsAdmin {
+		return true, "Role " + a.RoleName + " adalah admin dan melihat semua customer"
+	}
+	if a.Scope == ScopeAll {
+		if a.PolicyID == "" {
+			return true, "Role " + a.RoleName + " tidak memiliki access policy sehingga melihat semua customer"
+		}
+		return true, "Policy role " + a.RoleName + " mengizinkan semua customer"
+	}
+
+	if a.AccountManagerID != "" && customer.AccountManagerID != nil && *customer.AccountManagerID == a.AccountManagerID {
+		return true, "Customer berada di portfolio account manager milik user"
+	}
+	if a.Scope == ScopeTeam && customer.TeamID != nil {
+		for _, teamID := range a.TeamIDs {
+			if teamID == *customer.TeamID {
+				return true, "Customer dimiliki team tempat user menjadi anggota atau ketua"
+			}
+		}
+	}
+
+	reasons := []string{"Policy role " + a.RoleName + " membatasi ke scope " + a.Scope}
+	if a.AccountManagerID == "" {
+		reasons = append(reasons, "user tidak ditautkan ke account manager")
+	} else {
+		reasons = append(reasons, "customer tidak berada di portfolio account manager user")
+	}
+	if a.Scope == ScopeTeam {
+		switch {
+		case len(a.TeamIDs) == 0:
+			reasons = append(reasons, "user bukan anggota team manapun")
+		case customer.TeamID == nil:
+			reasons = append(reasons, "customer belum dimiliki team")
+		default:
+			reasons = append(reasons, "customer dimiliki team lain")
+		}
+	}
+	return false, strings.Join(reasons, "; ")
+}
+
+// CanWrite memeriksa apakah user boleh mengubah data milik customer
+func (a *Context) CanWrite(customer entity.Customer) bool {
+	if a.ReadOnly && !a.IsAdmin {
+		return false
+	}
+	visible, _ := a.CanSee(customer)
+	return visible
+}
+
+// resourceQueries berisi query untuk mencari customer pemilik sebuah record turunan
+var resourceQueries = map[string]string{
+	"address":         "SELECT customer_id FROM addresses WHERE id = ?",
+	"contact":         "SELECT customer_id FROM contacts WHERE id = ?",
+	"contact-date":    "SELECT contacts.customer_id FROM contact_dates JOIN contacts ON contacts.id = contact_dates.contact_id WHERE contact_dates.id = ?",
+	"structure":       "SELECT customer_id FROM structures WHERE id = ?",
+	"other":           "SELECT customer_id FROM others WHERE id = ?",
+	"activity":        "SELECT customer_id FROM activities WHERE id = ?",
+	"event":           "SELECT customer_id FROM events WHERE id = ?",
+	"document":        "SELECT customer_id FROM documents WHERE id = ?",
+	"sosmed":          "SELECT customer_id FROM sosmeds WHERE id = ?",
+	"sosmed-snapshot": "SELECT sosmeds.customer_id FROM sosmed_snapshots JOIN sosmeds ON sosmeds.id = sosmed_snapshots.sosmed_id WHERE sosmed_snapshots.id = ?",
+	"relationship":    "SELECT from_customer_id FROM customer_relationships WHERE id = ?",
+	"opportunity":     "SELECT customer_id FROM opportunities WHERE id = ?",
+	"project":         "SELECT customer_id FROM projects WHERE id = ? AND customer_id <> ''",
+	"time-entry":      "SELECT projects.customer_id FROM time_entries JOIN projects ON projects.id = time_entries.project_id WHERE time_entries.id = ? AND projects.customer_id <> ''",
+	"timesheet":       "SELECT projects.customer_id FROM timesheets JOIN projects ON projects.id = timesheets.project_id WHERE timesheets.id = ? AND projects.customer_id <> ''",
+}
+
+// KnownResource bernilai true jika resource dapat ditelusuri ke customer pemiliknya
+func KnownResource(resource string) bool {
+	_, known := resourceQueries[resource]
+	return resource == "customer" || known
+}
+
+// CustomerOf mengembalikan ID customer pemilik record. ok bernilai false jika resource
+// tidak dikenal atau record tidak ditemukan.
+func CustomerOf(db *gorm.DB, resource, id string) (string, bool) {
+	if resource == "customer" {
+		return id, true
+	}
+	query, known := resourceQueries[resource]
+	if !known {
+		return "", false
+	}
+	var customerIDs []string
+	if err := db.Raw(query, id).Scan(&customerIDs).Error; err != nil || len(customerIDs) == 0 {
+		return "", false
+	}
+	return customerIDs[0], true
+}
+
+// routeResources memetakan prefix route ke resource yang ID-nya ada di parameter :id
+var routeResources = map[string]string{
+	"/api/customers/:id":        "customer",
+	"/api/addresses/:id":        "address",
+	"/api/contacts/:id":         "contact",
+	"/api/contact-dates/:id":    "contact-date",
+	"/api/structures/:id":       "structure",
+	"/api/others/:id":           "other",
+	"/api/activities/:id":       "activity",
+	"/api/events/:id":           "event",
+	"/api/documents/:id":        "document",
+	"/api/sosmeds/:id":          "sosmed",
+	"/api/sosmed-snapshots/:id": "sosmed-snapshot",
+	"/api/relationships/:id":    "relationship",
+	"/api/opportunities/:id":    "opportunity",
+	"/api/projects/:id":         "project",
+	"/api/time-entries/:id":     "time-entry",
+	"/api/timesheets/:id":       "timesheet",
+}
+
+// ResourceForRoute mengembalikan resource untuk route gin (c.FullPath()), atau "" jika route
+// tidak mengacu ke satu record customer
+func ResourceForRoute(fullPath string) string {
+	for prefix, resource := range routeResources {
+		if fullPath == prefix || strings.HasPrefix(fullPath, prefix+"/") {
+			return resource
+		}
+	}
+	return ""
+}
@@ -85,6 +85,27 @@ func ConnectDatabase() {
 			&entity.WorkflowsDetail{},
 			&entity.GroupConfig{},
 			&entity.GroupConfigDetail{},
+			&entity.ContactDate{},
+			&entity.SosmedSnapshot{},
+			&entity.CustomerOwnership{},
+			&entity.Teams{},
+			&entity.TeamsDetail{},
+			&entity.Territory{},
+			&entity.AccessPolicy{},
+			&entity.OthersConfig{},
+			&entity.OthersConfigDetail{},
+			&entity.GroupType{},
+			&entity.CustomerRelationship{},
+			&entity.HealthSignal{},
+			&entity.CustomerHealthScore{},
+			&entity.CustomerAssessment{},
+			&entity.Opportunity{},
+			&entity.OpportunityStageHistory{},
+			&entity.ProjectMember{},
+			&entity.ProjectMilestone{},
+			&entity.TimeEntry{},
+			&entity.Timesheet{},
+			&entity.InvoiceLine{},
 		}
 		for _, t := range tables {
 			_ = DB.Migrator().DropTable(t)
@@ -112,6 +133,9 @@ func ConnectDatabase() {
 		}
 	}
 
+	// Kolom ID aktivitas dulu bertipe integer, sekarang ULID string seperti tabel lain
+	migrateActivityIDColumns()
+
 	// Auto migrate the schema - akan membuat tabel sesuai model Go
 	err = DB.AutoMigrate(
 		&entity.Activity{},
@@ -145,16 +169,44 @@ func ConnectDatabase() {
 		&entity.WorkflowsDetail{},
 		&entity.GroupConfig{},
 		&entity.GroupConfigDetail{},
+		&entity.ContactDate{},
+		&entity.SosmedSnapshot{},
+		&entity.CustomerOwnership{},
+		&entity.Teams{},
+		&entity.TeamsDetail{},
+		&entity.Territory{},
+		&entity.AccessPolicy{},
+		&entity.OthersConfig{},
+		&entity.OthersConfigDetail{},
+		&entity.GroupType{},
+		&entity.CustomerRelationship{},
+		&entity.HealthSignal{},
+		&entity.CustomerHealthScore{},
+		&entity.CustomerAssessment{},
+		&entity.Opportunity{},
+		&entity.OpportunityStageHistory{},
+		&entity.ProjectMember{},
+		&entity.ProjectMilestone{},
+		&entity.TimeEntry{},
+		&entity.Timesheet{},
+		&entity.InvoiceLine{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// Dokumen yang dibuat sebelum ada versioning menjadi root dari riwayat versinya sendiri
+	DB.Exec("UPDATE documents SET root_id = id WHERE root_id IS NULL OR root_id = ''")
+
+	// Role Admin lama belum punya flag is_admin: tandai sekali selama belum ada role admin
+	DB.Exec(`UPDATE roles SET is_admin = true WHERE role_name = 'Admin' AND deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM roles WHERE is_admin AND deleted_at IS NULL)`)
+
 	// Insert default roles if they don't exist
 	var adminRole entity.Role
-	result := DB.Where("id = ?", "1").First(&adminRole)
+	result := DB.Where("is_admin = ?", true).First(&adminRole)
 	if result.RowsAffected == 0 {
-		DB.Create(&entity.Role{ID: "1", RoleName: "Admin"})
+		DB.Create(&entity.Role{ID: "1", RoleName: "Admin", IsAdmin: true})
 		fmt.Println("Created Admin role with ID 1")
 	}
 
@@ -165,6 +217,40 @@ func ConnectDatabase() {
 		fmt.Println("Created User role with ID 2")
 	}
 
+	// Insert default group types if they don't exist
+	defaultGroupTypes := []entity.GroupType{
+		{Code: "industry", Name: "Industry", Exclusive: true, Active: true},
+		{Code: "business_group", Name: "Business Group", Exclusive: true, Active: true},
+		{Code: "holding", Name: "Holding", Exclusive: true, Active: true},
+		{Code: "region", Name: "Region", Active: true},
+	}
+	for _, groupType := range defaultGroupTypes {
+		var existing entity.GroupType
+		result = DB.Where("code = ?", groupType.Code).First(&existing)
+		if result.RowsAffected == 0 {
+			DB.Create(&groupType)
+			fmt.Println("Created group type " + groupType.Code)
+		}
+	}
+
+	// Insert default health score signals if they don't exist
+	defaultHealthSignals := []entity.HealthSignal{
+		{Code: "activity_recency", Name: "Days since last activity", Weight: 30, Threshold: 90, Active: true},
+		{Code: "event_attendance", Name: "Event attendance", Weight: 15, Threshold: 4, WindowDays: 180, Active: true},
+		{Code: "overdue_balance", Name: "Overdue invoice balance", Weight: 20, Threshold: 50000000, Active: true},
+		{Code: "assessment_score", Name: "Latest assessment score", Weight: 15, Active: true},
+		{Code: "status_history", Name: "Status history", Weight: 10, Threshold: 3, WindowDays: 365, Active: true},
+		{Code: "contact_coverage", Name: "Main contact and address", Weight: 10, Active: true},
+	}
+	for _, signal := range defaultHealthSignals {
+		var existing entity.HealthSignal
+		result = DB.Where("code = ?", signal.Code).First(&existing)
+		if result.RowsAffected == 0 {
+			DB.Create(&signal)
+			fmt.Println("Created health signal " + signal.Code)
+		}
+	}
+
 	// Insert default account managers if they don't exist
 	var defaultManager entity.AccountManager
 	result = DB.Where("manager_name = ?", "Default Manager").First(&defaultManager)
@@ -182,3 +268,29 @@ func ConnectDatabase() {
 
 	fmt.Println("Database connected and migrated successfully!")
 }
+
+// migrateActivityIDColumns mengubah kolom ID aktivitas yang masih integer menjadi varchar(26)
+// sebelum AutoMigrate, dengan konversi nilai lama ke teks
+func migrateActivityIDColumns() {
+	columns := []struct{ table, column string }{
+		{"activities", "customer_id"},
+		{"activities", "created_by"},
+		{"activity_attendees", "activity_id"},
+		{"activity_attendees", "user_id"},
+		{"activity_checkins", "activity_id"},
+		{"activity_checkins", "user_id"},
+	}
+	for _, c := range columns {
+		var dataType string
+		DB.Raw("SELECT data_type FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?",
+			c.table, c.column).Scan(&dataType)
+		if dataType != "integer" && dataType != "bigint" && dataType != "smallint" {
+			continue
+		}
+		err := DB.Exec("ALTER TABLE " + c.table + " ALTER COLUMN " + c.column + " TYPE varchar(26) USING " + c.column + "::text").Error
+		if err != nil {
+			log.Fatal("Failed to migrate "+c.table+"."+c.column+":", err)
+		}
+		fmt.Println("Migrated " + c.table + "." + c.column + " to varchar(26)")
+	}
+}
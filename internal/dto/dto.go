@@ -49,19 +49,55 @@ type CustomersResponse struct {
 
 // CustomerListResponse represents simplified customer response for lists
 type CustomerListResponse struct {
-	ID          string  `json:"id" example:"01HXYZ123456789ABCDEF"`
-	Name        string  `json:"name" example:"PT Teknologi Maju"`
-	BrandName   string  `json:"brand_name" example:"TechMaju"`
-	Code        string  `json:"code" example:"TM001"`
-	Logo        string  `json:"logo" example:"uploads/logos/logo_1.png"`
-	Status      string  `json:"status" example:"Active"`
-	Category    string  `json:"category" example:"Technology"`
-	Rating      float64 `json:"rating" example:"4.5"`
-	AverageCost float64 `json:"average_cost" example:"50000000"`
-	LogoSmall   string  `json:"logo_small" example:"uploads/logos_small/logo_small_1.png"`
-	CreatedAt   string  `json:"created_at" example:"2024-01-15T08:00:00Z"`
-	UpdatedAt   string  `json:"updated_at" example:"2024-01-15T08:00:00Z"`
-	ManagerName *string `json:"manager_name" example:"John Doe"`
+	ID          string   `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Name        string   `json:"name" example:"PT Teknologi Maju"`
+	BrandName   string   `json:"brand_name" example:"TechMaju"`
+	Code        string   `json:"code" example:"TM001"`
+	Logo        string   `json:"logo" example:"uploads/logos/logo_1.png"`
+	Status      string   `json:"status" example:"Active"`
+	Category    string   `json:"category" example:"Technology"`
+	Rating      float64  `json:"rating" example:"4.5"`
+	AverageCost float64  `json:"average_cost" example:"50000000"`
+	HealthScore *float64 `json:"health_score" example:"72.5"`
+	LogoSmall   string   `json:"logo_small" example:"uploads/logos_small/logo_small_1.png"`
+	CreatedAt   string   `json:"created_at" example:"2024-01-15T08:00:00Z"`
+	UpdatedAt   string   `json:"updated_at" example:"2024-01-15T08:00:00Z"`
+	ManagerName *string  `json:"manager_name" example:"John Doe"`
+}
+
+// HierarchyMetrics adalah ringkasan aktivitas dan invoice satu customer atau seluruh subtree-nya
+type HierarchyMetrics struct {
+	Customers          int64      `json:"customers" example:"5"` // jumlah perusahaan yang dihitung
+	Activities         int64      `json:"activities" example:"42"`
+	UpcomingActivities int64      `json:"upcoming_activities" example:"3"`
+	LastActivityAt     *time.Time `json:"last_activity_at" example:"2024-05-01T09:00:00Z"`
+	Invoices           int64      `json:"invoices" example:"12"`
+	InvoicedAmount     float64    `json:"invoiced_amount" example:"250000000"`
+	PaidAmount         float64    `json:"paid_amount" example:"200000000"`
+	OutstandingBalance float64    `json:"outstanding_balance" example:"50000000"`
+	OverdueBalance     float64    `json:"overdue_balance" example:"15000000"`
+}
+
+// CustomerHierarchyNode adalah satu customer dalam pohon induk/anak perusahaan.
+// Customer yang tidak boleh dilihat user tetap muncul sebagai node Restricted agar pohon tidak terputus.
+type CustomerHierarchyNode struct {
+	ID         string                  `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Name       string                  `json:"name,omitempty" example:"PT Anak Perusahaan"`
+	Code       string                  `json:"code,omitempty" example:"AP001"`
+	Status     string                  `json:"status,omitempty" example:"Active"`
+	ParentID   *string                 `json:"parent_id" example:"01HXYZ123456789ABCDEF"`
+	Depth      int                     `json:"depth" example:"1"`
+	Restricted bool                    `json:"restricted,omitempty"`
+	Own        HierarchyMetrics        `json:"own"`    // customer ini saja
+	Rollup     HierarchyMetrics        `json:"rollup"` // customer ini dan seluruh anak perusahaannya
+	Children   []CustomerHierarchyNode `json:"children"`
+}
+
+// CustomerHierarchyResponse adalah pohon perusahaan lengkap beserta posisi customer yang diminta
+type CustomerHierarchyResponse struct {
+	CustomerID string                `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	Path       []string              `json:"path"` // id dari akar sampai customer yang diminta
+	Tree       CustomerHierarchyNode `json:"tree"`
 }
 
 // Stats represents customer statistics
@@ -108,6 +144,7 @@ type CustomerResponse struct {
 	Code             string                `json:"code" example:"TM001"`
 	AccountManagerID *string               `json:"account_manager_id" example:"AM001"`
 	AccountManager   *AccountManagerDetail `json:"account_manager,omitempty"`
+	TeamID           *string               `json:"team_id,omitempty" example:"01HXYZ123456789ABCDEF"`
 	/* Email            string            `json:"email" example:"info@teknologimaju.com"`
 	Phone            string            `json:"phone" example:"021-12345678"`
 	Website          string            `json:"website" example:"https://teknologimaju.com"`
@@ -161,38 +198,44 @@ type AccountManagerDetail struct {
 // CreateAccountManagerRequest represents account manager creation request
 type CreateAccountManagerRequest struct {
 	ManagerName string `json:"manager_name" binding:"required" example:"John Doe"`
-	Email       string `json:"email" binding:"required,email" example:"john@example.com"`
+	UserID      string `json:"user_id" example:"01HXYZ123456789ABCDEF"`                    // akun login yang ditautkan
+	Email       string `json:"email" binding:"omitempty,email" example:"john@example.com"` // wajib jika user_id kosong
 	Phone       string `json:"phone" example:"+62812345678"`
+	IsActive    *bool  `json:"is_active" example:"true"`
 }
 
 // UpdateAccountManagerRequest represents account manager update request
 type UpdateAccountManagerRequest struct {
 	ManagerName *string `json:"manager_name" example:"Updated Name"`
-	Email       *string `json:"email" example:"updated@example.com"`
+	UserID      *string `json:"user_id" example:"01HXYZ123456789ABCDEF"` // "" untuk melepas tautan user
+	Email       *string `json:"email" binding:"omitempty,email" example:"updated@example.com"`
 	Phone       *string `json:"phone" example:"+62812345679"`
 	IsActive    *bool   `json:"is_active" example:"true"`
 }
 
 // AccountManagerResponse represents account manager response
 type AccountManagerResponse struct {
-	ID          string `json:"id" example:"AM001"`
-	ManagerName string `json:"manager_name" example:"John Doe"`
-	Email       string `json:"email" example:"john@example.com"`
-	Phone       string `json:"phone" example:"+62812345678"`
-	IsActive    bool   `json:"is_active" example:"true"`
-	CreatedAt   string `json:"created_at" example:"2024-01-15T08:00:00Z"`
-	UpdatedAt   string `json:"updated_at" example:"2024-01-15T08:00:00Z"`
+	ID          string  `json:"id" example:"AM001"`
+	ManagerName string  `json:"manager_name" example:"John Doe"`
+	UserID      *string `json:"user_id" example:"01HXYZ123456789ABCDEF"`
+	Username    string  `json:"username,omitempty" example:"johndoe"`
+	Email       string  `json:"email" example:"john@example.com"`
+	Phone       string  `json:"phone" example:"+62812345678"`
+	IsActive    bool    `json:"is_active" example:"true"`
+	CreatedAt   string  `json:"created_at" example:"2024-01-15T08:00:00Z"`
+	UpdatedAt   string  `json:"updated_at" example:"2024-01-15T08:00:00Z"`
 }
 
 // CreateCustomerRequest represents comprehensive customer creation request
 type CreateCustomerRequest struct {
 	ID               string                   `json:"id"`
-	ParentID         string                   `json:"parent_id"` // <- UBAH dari *uint ke *string
+	ParentID         string                   `json:"parent_id"` // induk perusahaan, kosong = tidak punya induk
 	Name             *string                  `json:"name" binding:"required"`
 	BrandName        *string                  `json:"brandName"`
 	Code             *string                  `json:"code"`
 	AccountManagerID *string                  `json:"account_manager_id"`
 	ManagerName      *string                  `json:"manager_name"` // Field baru untuk pemilihan berdasarkan nama
+	TeamID           *string                  `json:"team_id"`      // kosong = di-assign otomatis lewat territory
 	Logo             string                   `json:"logo"`
 	LogoSmall        string                   `json:"logoSmall"`
 	StatusName       string                   `json:"status_name"`
@@ -207,23 +250,35 @@ type CreateCustomerRequest struct {
 // CreateAddressRequest represents address creation in customer request
 type CreateAddressRequest struct {
 	// CustomerID uint   `json:"customer_id" binding:"required"` // Hapus field ini
-	Name    string `json:"name" binding:"required" example:"Head Office"`
-	Address string `json:"address" binding:"required" example:"Jl. Sudirman No. 123, Jakarta Selatan"`
-	IsMain  bool   `json:"isMain" example:"true"`
-	Active  bool   `json:"active" example:"true"`
+	CustomerID   string   `json:"customer_id" example:"01HXYZ123456789ABCDEF"` // dipakai oleh POST /addresses
+	Name         string   `json:"name" binding:"required" example:"Head Office"`
+	Address      string   `json:"address" binding:"required" example:"Jl. Sudirman No. 123, Jakarta Selatan"`
+	Street       string   `json:"street" example:"Jl. Sudirman No. 123"`
+	ProvinceCode string   `json:"province_code" example:"31"`
+	RegencyCode  string   `json:"regency_code" example:"31.71"`
+	DistrictCode string   `json:"district_code" example:"31.71.02"`
+	VillageCode  string   `json:"village_code" example:"31.71.02.1002"`
+	PostalCode   string   `json:"postal_code" example:"12920"`
+	Latitude     *float64 `json:"latitude" example:"-6.2088"`
+	Longitude    *float64 `json:"longitude" example:"106.8456"`
+	IsMain       bool     `json:"isMain" example:"true"`
+	Active       bool     `json:"active" example:"true"`
 }
 
 // CreateSocialRequest represents social media creation in customer request
 type CreateSocialRequest struct {
-	// Name     string `json:"name" binding:"required" example:"Instagram"` // Hapus field ini karena duplikat dengan Platform
-	Platform string `json:"platform" binding:"required" example:"Instagram"`
-	Handle   string `json:"handle" binding:"required" example:"@digiinno_id"`
-	Active   bool   `json:"active" example:"true"`
+	Name      string `json:"name" example:"Instagram Official"` // kosong = "<platform> <handle>"
+	Platform  string `json:"platform" example:"Instagram"`      // boleh kosong jika handle/url berupa URL profil
+	Handle    string `json:"handle" example:"@digiinno_id"`     // @handle, handle polos atau URL profil
+	URL       string `json:"url" example:"https://www.instagram.com/digiinno_id/"`
+	Followers int    `json:"followers" binding:"min=0" example:"15230"`
+	Active    bool   `json:"active" example:"true"`
 }
 
 // CreateContactRequest represents contact creation in customer request
 type CreateContactRequest struct {
 	// CustomerID  uint   `json:"customer_id" binding:"required"` // Hapus field ini
+	CustomerID  string `json:"customer_id" example:"01HXYZ123456789ABCDEF"` // dipakai oleh POST /contacts
 	Name        string `json:"name" binding:"required" example:"Budi Santoso"`
 	Birthdate   string `json:"birthdate" example:"1985-03-15"`
 	JobPosition string `json:"jobPosition" example:"CEO"`
@@ -239,10 +294,13 @@ type CreateStructureRequest struct {
 	// CustomerID uint    `json:"customer_id" binding:"required"` // Hapus field ini
 	TempKey   string  `json:"tempKey" example:"1"`
 	ParentKey *string `json:"parentKey" example:"null"`
-	Name      string  `json:"name" binding:"required" example:"Board of Directors"`
-	Level     int     `json:"level" binding:"required" example:"1"`
-	Address   string  `json:"address" example:"Jakarta"`
-	Active    bool    `json:"active" example:"true"`
+	// CustomerID dan ParentID hanya dipakai oleh POST /structures, level dihitung dari parent
+	CustomerID string  `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	ParentID   *string `json:"parent_id" example:"01HXYZ123456789ABCDEF"`
+	Name       string  `json:"name" binding:"required" example:"Board of Directors"`
+	Level      int     `json:"level" example:"1"`
+	Address    string  `json:"address" example:"Jakarta"`
+	Active     bool    `json:"active" example:"true"`
 }
 
 // CreateGroupsRequest represents groups assignment in customer request
@@ -253,19 +311,58 @@ type CreateGroupsRequest struct {
 	ParentGroupActive bool   `json:"parentGroupActive" example:"true"`
 }
 
+// GroupTypeRequest represents group type create/update request
+type GroupTypeRequest struct {
+	Code      string `json:"code" binding:"required" example:"industry"`
+	Name      string `json:"name" binding:"required" example:"Industry"`
+	Exclusive bool   `json:"exclusive" example:"true"` // customer maksimal satu group per jenis ini
+	Active    *bool  `json:"active" example:"true"`
+}
+
+// GroupRequest represents group create/update request
+type GroupRequest struct {
+	NameGroup string  `json:"name_group" binding:"required" example:"Manufacturing"`
+	Value     string  `json:"value" example:"MFG"`
+	TypeID    *string `json:"type_id" example:"01HXYZ123456789ABCDEF"`
+	ParentID  *string `json:"parent_id" example:"01HXYZ123456789ABCDEF"` // kosong = group akar
+	Active    *bool   `json:"active" example:"true"`
+}
+
+// GroupNode adalah satu group dalam pohon beserta rollup customer seluruh subtree-nya
+type GroupNode struct {
+	ID              string      `json:"id" example:"01HXYZ123456789ABCDEF"`
+	NameGroup       string      `json:"name_group" example:"Manufacturing"`
+	Value           string      `json:"value" example:"MFG"`
+	TypeID          *string     `json:"type_id" example:"01HXYZ123456789ABCDEF"`
+	TypeCode        string      `json:"type_code,omitempty" example:"industry"`
+	ParentID        *string     `json:"parent_id" example:"01HXYZ123456789ABCDEF"`
+	Active          bool        `json:"active" example:"true"`
+	DirectCustomers int64       `json:"direct_customers" example:"4"` // customer yang langsung menjadi anggota
+	TotalCustomers  int64       `json:"total_customers" example:"12"` // customer unik di seluruh subtree
+	Revenue         float64     `json:"revenue" example:"350000000"`  // total AverageCost customer unik di subtree
+	Children        []GroupNode `json:"children"`
+}
+
 // CreateOtherRequest represents other attributes in customer request
 type CreateOtherRequest struct {
-	// CustomerID uint    `json:"customer_id" binding:"required"` // Hapus field ini
-	Key    string  `json:"key" binding:"required" example:"company_size"`
-	Value  *string `json:"value" example:"50-100 employees"`
-	Active bool    `json:"active" example:"true"`
+	CustomerID string  `json:"customer_id" example:"01HXYZ123456789ABCDEF"` // wajib untuk POST /others, diabaikan saat create customer
+	Key        string  `json:"key" binding:"required" example:"company_size"`
+	Value      *string `json:"value" example:"50-100 employees"`
+	Active     bool    `json:"active" example:"true"`
 }
 
 // CreateGroupConfigRequest represents group config creation request
 type CreateGroupConfigRequest struct {
-	Name   string `json:"name" binding:"required" example:"Sales Activity"`
-	Field  string `json:"field" binding:"required" example:"id"`
-	Active bool   `json:"active" example:"true"`
+	Name   string `json:"name" binding:"required" example:"Customer Size"`
+	Field  string `json:"field" binding:"required" example:"other:company_size"` // kolom customer atau other:<key>
+	Active *bool  `json:"active" example:"true"`
+}
+
+// UpdateGroupConfigRequest represents group config update request
+type UpdateGroupConfigRequest struct {
+	Name   *string `json:"name" example:"Customer Size"`
+	Field  *string `json:"field" example:"category"`
+	Active *bool   `json:"active" example:"true"`
 }
 
 // GroupConfigResponse represents group config response without is_deleted field
@@ -280,8 +377,12 @@ type GroupConfigResponse struct {
 
 // CreateGroupConfigDetailRequest represents group config detail creation request
 type CreateGroupConfigDetailRequest struct {
-	Name string `json:"name" binding:"required" example:"Regular Meeting"`
-	Icon string `json:"icon" binding:"required" example:"bla.icon"`
+	Name      string   `json:"name" binding:"required" example:"Enterprise"`
+	Icon      string   `json:"icon" binding:"required" example:"bla.icon"`
+	Values    []string `json:"values" example:"Enterprise,Corporate"`
+	MinValue  *float64 `json:"min_value" example:"1000000"`
+	MaxValue  *float64 `json:"max_value" example:"5000000"`
+	SortOrder int      `json:"sort_order" example:"1"`
 }
 
 // GroupConfigDetailResponse represents group config detail response with minimal fields
@@ -291,9 +392,32 @@ type GroupConfigDetailResponse struct {
 	Icon string `json:"icon"`
 }
 
+// SegmentBucket adalah jumlah customer dan total AverageCost dalam satu bucket segmentasi
+type SegmentBucket struct {
+	DetailID         string  `json:"detail_id,omitempty" example:"01HXYZ123456789ABCDEF"`
+	Name             string  `json:"name" example:"Enterprise"`
+	Icon             string  `json:"icon" example:"bla.icon"`
+	Count            int64   `json:"count" example:"42"`
+	TotalAverageCost float64 `json:"total_average_cost" example:"125000000"`
+	AverageCost      float64 `json:"average_cost" example:"2976190.48"`
+}
+
+// SegmentReport adalah hasil segmentasi customer untuk satu GroupConfig
+type SegmentReport struct {
+	GroupConfigID    string          `json:"group_config_id" example:"01HXYZ123456789ABCDEF"`
+	Name             string          `json:"name" example:"Customer Size"`
+	Field            string          `json:"field" example:"other:company_size"`
+	Total            int64           `json:"total" example:"120"`
+	TotalAverageCost float64         `json:"total_average_cost" example:"350000000"`
+	Buckets          []SegmentBucket `json:"buckets"`
+	Unmatched        SegmentBucket   `json:"unmatched"` // customer yang tidak masuk bucket manapun
+}
+
 // CreateActivityRequest represents activity creation request
 type CreateActivityRequest struct {
-	CustomerID   uint   `json:"customer_id" binding:"required" example:"1"`
+	CustomerID   string `json:"customer_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	AddressID    string `json:"address_id" example:"01HXYZ123456789ABCDEG"`
+	ProjectID    string `json:"project_id" example:"01HXYZ123456789ABCDEH"`
 	Title        string `json:"title" binding:"required" example:"Client Meeting"`
 	Type         string `json:"type" binding:"required" example:"Meeting"`
 	Agenda       string `json:"agenda" example:"Discuss project requirements"`
@@ -306,6 +430,7 @@ type CreateActivityRequest struct {
 
 // UpdateActivityRequest represents activity update request
 type UpdateActivityRequest struct {
+	ProjectID    *string `json:"project_id" example:"01HXYZ123456789ABCDEH"` // string kosong melepas project
 	Title        *string `json:"title" example:"Updated Meeting"`
 	Type         *string `json:"type" example:"Meeting"`
 	Agenda       *string `json:"agenda" example:"Updated agenda"`
@@ -317,18 +442,20 @@ type UpdateActivityRequest struct {
 
 // ActivityResponse represents activity response
 type ActivityResponse struct {
-	ID           string `json:"id"`
-	CustomerID   uint   `json:"customer_id" example:"1"`
-	Title        string `json:"title" example:"Client Meeting"`
-	Type         string `json:"type" example:"Meeting"`
-	Agenda       string `json:"agenda" example:"Discuss project requirements"`
-	StartTime    string `json:"start_time" example:"2024-01-15T10:00:00Z"`
-	EndTime      string `json:"end_time" example:"2024-01-15T12:00:00Z"`
-	LocationName string `json:"location_name" example:"Conference Room A"`
-	Status       string `json:"status" example:"Scheduled"`
-	CreatedBy    uint   `json:"created_by" example:"1"`
-	CreatedAt    string `json:"created_at" example:"2024-01-15T08:00:00Z"`
-	UpdatedAt    string `json:"updated_at" example:"2024-01-15T08:00:00Z"`
+	ID           string  `json:"id"`
+	CustomerID   string  `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	AddressID    *string `json:"address_id,omitempty" example:"01HXYZ123456789ABCDEG"`
+	ProjectID    *string `json:"project_id,omitempty" example:"01HXYZ123456789ABCDEH"`
+	Title        string  `json:"title" example:"Client Meeting"`
+	Type         string  `json:"type" example:"Meeting"`
+	Agenda       string  `json:"agenda" example:"Discuss project requirements"`
+	StartTime    string  `json:"start_time" example:"2024-01-15T10:00:00Z"`
+	EndTime      string  `json:"end_time" example:"2024-01-15T12:00:00Z"`
+	LocationName string  `json:"location_name" example:"Conference Room A"`
+	Status       string  `json:"status" example:"Scheduled"`
+	CreatedBy    string  `json:"created_by" example:"01HXYZ123456789ABCDEH"`
+	CreatedAt    string  `json:"created_at" example:"2024-01-15T08:00:00Z"`
+	UpdatedAt    string  `json:"updated_at" example:"2024-01-15T08:00:00Z"`
 }
 
 // ActivitiesResponse represents activities list response
@@ -339,7 +466,7 @@ type ActivitiesResponse struct {
 
 // ActivityAttendeeRequest represents activity attendee request
 type ActivityAttendeeRequest struct {
-	UserIDs []uint `json:"user_ids" binding:"required" example:"[1,2,3]"`
+	UserIDs []string `json:"user_ids" binding:"required"`
 }
 
 // ActivityCheckinRequest represents activity check-in request
@@ -351,23 +478,24 @@ type ActivityCheckinRequest struct {
 
 // Invoice DTOs
 type InvoiceResponse struct {
-	ID            string            `json:"id" example:"01HXYZ123456789ABCDEF"`
-	CustomerID    uint              `json:"customer_id" example:"1"`
-	ProjectID     string            `json:"project_id" example:"01HXYZ123456789ABCDEF"`
-	InvoiceNumber string            `json:"invoice_number" example:"INV-2024-001"`
-	Amount        float64           `json:"amount" example:"1000000"`
-	IssuedDate    time.Time         `json:"issued_date" example:"2024-01-15T00:00:00Z"`
-	DueDate       time.Time         `json:"due_date" example:"2024-02-15T00:00:00Z"`
-	PaidAmount    float64           `json:"paid_amount" example:"500000"`
-	Balance       float64           `json:"balance" example:"500000"`
-	Status        string            `json:"status" example:"partial"`
-	Customer      *CustomerResponse `json:"customer,omitempty"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
+	ID            string                `json:"id" example:"01HXYZ123456789ABCDEF"`
+	CustomerID    string                `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	ProjectID     string                `json:"project_id" example:"01HXYZ123456789ABCDEF"`
+	InvoiceNumber string                `json:"invoice_number" example:"INV-2024-001"`
+	Amount        float64               `json:"amount" example:"1000000"`
+	IssuedDate    time.Time             `json:"issued_date" example:"2024-01-15T00:00:00Z"`
+	DueDate       time.Time             `json:"due_date" example:"2024-02-15T00:00:00Z"`
+	PaidAmount    float64               `json:"paid_amount" example:"500000"`
+	Balance       float64               `json:"balance" example:"500000"`
+	Status        string                `json:"status" example:"partial"`
+	Customer      *CustomerResponse     `json:"customer,omitempty"`
+	Lines         []InvoiceLineResponse `json:"lines,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
 }
 
 type CreateInvoiceRequest struct {
-	CustomerID    uint      `json:"customer_id" binding:"required" example:"1"`
+	CustomerID    string    `json:"customer_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
 	ProjectID     string    `json:"project_id" example:"01HXYZ123456789ABCDEF"`
 	InvoiceNumber string    `json:"invoice_number" binding:"required" example:"INV-2024-001"`
 	Amount        float64   `json:"amount" binding:"required" example:"1000000"`
@@ -376,7 +504,7 @@ type CreateInvoiceRequest struct {
 }
 
 type UpdateInvoiceRequest struct {
-	CustomerID    *uint      `json:"customer_id" example:"1"`
+	CustomerID    *string    `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
 	ProjectID     *string    `json:"project_id" example:"01HXYZ123456789ABCDEF"`
 	InvoiceNumber *string    `json:"invoice_number" example:"INV-2024-001"`
 	Amount        *float64   `json:"amount" example:"1000000"`
@@ -388,7 +516,7 @@ type UpdateInvoiceRequest struct {
 // Payment DTOs
 type PaymentResponse struct {
 	ID        string           `json:"id" example:"01HXYZ123456789ABCDEF"`
-	InvoiceID uint             `json:"invoice_id" example:"1"`
+	InvoiceID string           `json:"invoice_id" example:"01HXYZ123456789ABCDEF"`
 	Amount    float64          `json:"amount" example:"500000"`
 	PaidAt    time.Time        `json:"paid_at" example:"2024-01-20T10:00:00Z"`
 	Invoice   *InvoiceResponse `json:"invoice,omitempty"`
@@ -397,13 +525,13 @@ type PaymentResponse struct {
 }
 
 type CreatePaymentRequest struct {
-	InvoiceID uint      `json:"invoice_id" binding:"required" example:"1"`
+	InvoiceID string    `json:"invoice_id" example:"01HXYZ123456789ABCDEF"` // wajib untuk POST /payments, diambil dari URL pada POST /invoices/:id/payments
 	Amount    float64   `json:"amount" binding:"required" example:"500000"`
 	PaidAt    time.Time `json:"paid_at" binding:"required" example:"2024-01-20T10:00:00Z"`
 }
 
 type UpdatePaymentRequest struct {
-	InvoiceID *uint      `json:"invoice_id" example:"1"`
+	InvoiceID *string    `json:"invoice_id" example:"01HXYZ123456789ABCDEF"`
 	Amount    *float64   `json:"amount" example:"500000"`
 	PaidAt    *time.Time `json:"paid_at" example:"2024-01-20T10:00:00Z"`
 }
@@ -435,10 +563,14 @@ type AssessmentResponse struct {
 
 // AccountManagerListResponse represents simplified account manager response for lists
 type AccountManagerListResponse struct {
-	ID          string `json:"id" example:"AM001"`
-	ManagerName string `json:"manager_name" example:"John Doe"`
-	CreatedAt   string `json:"created_at" example:"2024-01-15T08:00:00Z"`
-	UpdatedAt   string `json:"updated_at" example:"2024-01-15T08:00:00Z"`
+	ID          string  `json:"id" example:"AM001"`
+	ManagerName string  `json:"manager_name" example:"John Doe"`
+	UserID      *string `json:"user_id" example:"01HXYZ123456789ABCDEF"`
+	Email       string  `json:"email" example:"john@example.com"`
+	Phone       string  `json:"phone" example:"+62812345678"`
+	IsActive    bool    `json:"is_active" example:"true"`
+	CreatedAt   string  `json:"created_at" example:"2024-01-15T08:00:00Z"`
+	UpdatedAt   string  `json:"updated_at" example:"2024-01-15T08:00:00Z"`
 }
 
 // Event DTOs
@@ -525,3 +657,771 @@ type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
 }
+
+// Document DTOs
+type DocumentResponse struct {
+	ID         string     `json:"id" example:"01HXYZ123456789ABCDEF"`
+	CustomerID string     `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	Category   string     `json:"category" example:"contract"`
+	Type       string     `json:"type" example:"Upload"`
+	Notes      string     `json:"notes" example:"Kontrak kerja sama 2024"`
+	URLFile    string     `json:"url_file" example:"/api/documents/01HXYZ123456789ABCDEF/download"`
+	FileName   string     `json:"file_name" example:"kontrak.pdf"`
+	MimeType   string     `json:"mime_type" example:"application/pdf"`
+	Size       int64      `json:"size" example:"204800"`
+	RootID     string     `json:"root_id" example:"01HXYZ123456789ABCDEF"`
+	Version    int        `json:"version" example:"2"`
+	IsLatest   bool       `json:"is_latest" example:"true"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"2025-01-15T00:00:00Z"`
+	UserID     string     `json:"user_id" example:"01HXYZ123456789ABCDEF"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type UpdateDocumentRequest struct {
+	Category  *string `json:"category" example:"nda"`
+	Notes     *string `json:"notes" example:"Updated notes"`
+	ExpiresAt *string `json:"expires_at" example:"2025-12-31"`
+}
+
+// Structure tree DTOs
+type StructureTreeNode struct {
+	ID         string              `json:"id" example:"01HXYZ123456789ABCDEF"`
+	CustomerID string              `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	ParentID   *string             `json:"parent_id" example:"01HXYZ123456789ABCDEF"`
+	Name       string              `json:"name" example:"Board of Directors"`
+	Level      int                 `json:"level" example:"1"`
+	Position   int                 `json:"position" example:"0"`
+	Address    string              `json:"address" example:"Jakarta"`
+	Active     bool                `json:"active" example:"true"`
+	Children   []StructureTreeNode `json:"children"`
+}
+
+type MoveStructureRequest struct {
+	ParentID *string `json:"parent_id" example:"01HXYZ123456789ABCDEF"` // null = jadikan root
+	Position *int    `json:"position" example:"0"`                      // kosong = taruh di akhir
+}
+
+type ReorderStructuresRequest struct {
+	ParentID     *string  `json:"parent_id" example:"01HXYZ123456789ABCDEF"`
+	StructureIDs []string `json:"structure_ids" binding:"required" example:"01HXYZ123456789ABCDEF,01HXYZ123456789ABCDEG"`
+}
+
+// Contact reminder DTOs
+type ContactDateRequest struct {
+	Type      string `json:"type" binding:"required" example:"work_anniversary"`
+	Label     string `json:"label" example:"Bergabung di PT Digi Inno"`
+	Date      string `json:"date" binding:"required" example:"2018-07-01"`
+	Recurring *bool  `json:"recurring" example:"true"`
+	Notes     string `json:"notes" example:"Kirim parcel"`
+}
+
+type UpcomingReminder struct {
+	ContactID          string    `json:"contact_id" example:"01HXYZ123456789ABCDEF"`
+	ContactName        string    `json:"contact_name" example:"Budi Santoso"`
+	JobPosition        string    `json:"job_position" example:"CEO"`
+	CustomerID         string    `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName       string    `json:"customer_name" example:"PT Digi Inno"`
+	AccountManagerID   *string   `json:"account_manager_id" example:"AB12C"`
+	AccountManagerName string    `json:"account_manager_name" example:"John Doe"`
+	ContactDateID      string    `json:"contact_date_id,omitempty" example:"01HXYZ123456789ABCDEF"`
+	Type               string    `json:"type" example:"birthday"`
+	Label              string    `json:"label" example:"Birthday"`
+	Date               time.Time `json:"date" example:"1985-03-15T00:00:00Z"`
+	NextDate           string    `json:"next_date" example:"2025-03-15"`
+	DaysUntil          int       `json:"days_until" example:"3"`
+	Years              int       `json:"years" example:"40"` // umur / tahun ke-berapa pada next_date
+}
+
+type ReminderDigest struct {
+	AccountManagerID    string             `json:"account_manager_id" example:"AB12C"`
+	AccountManagerName  string             `json:"account_manager_name" example:"John Doe"`
+	AccountManagerEmail string             `json:"account_manager_email,omitempty" example:"john@example.com"`
+	From                string             `json:"from" example:"2025-03-12"`
+	To                  string             `json:"to" example:"2025-03-19"`
+	Reminders           []UpcomingReminder `json:"reminders"`
+}
+
+// Org chart DTOs
+type ContactPlacementRequest struct {
+	StructureID *string `json:"structure_id" example:"01HXYZ123456789ABCDEF"`  // null = lepas dari unit
+	ReportsToID *string `json:"reports_to_id" example:"01HXYZ123456789ABCDEG"` // null = tidak punya atasan
+}
+
+type OrgChartPerson struct {
+	ID            string           `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Name          string           `json:"name" example:"Budi Santoso"`
+	JobPosition   string           `json:"job_position" example:"CFO"`
+	Position      string           `json:"position" example:"Decision Maker"`
+	Department    string           `json:"department" example:"Finance"`
+	Email         string           `json:"email" example:"budi@digiinno.com"`
+	Phone         string           `json:"phone" example:"021-5551234"`
+	Mobile        string           `json:"mobile" example:"0812-3456-7890"`
+	Main          bool             `json:"main" example:"true"`
+	StructureID   *string          `json:"structure_id" example:"01HXYZ123456789ABCDEF"`
+	StructureName string           `json:"structure_name,omitempty" example:"Finance Division"`
+	ReportsToID   *string          `json:"reports_to_id" example:"01HXYZ123456789ABCDEG"`
+	DirectReports []OrgChartPerson `json:"direct_reports,omitempty"`
+}
+
+type OrgChartNode struct {
+	ID       string           `json:"id" example:"01HXYZ123456789ABCDEF"`
+	ParentID *string          `json:"parent_id" example:"01HXYZ123456789ABCDEF"`
+	Name     string           `json:"name" example:"Board of Directors"`
+	Level    int              `json:"level" example:"1"`
+	Position int              `json:"position" example:"0"`
+	Active   bool             `json:"active" example:"true"`
+	People   []OrgChartPerson `json:"people"`
+	Children []OrgChartNode   `json:"children"`
+}
+
+type OrgChartResponse struct {
+	CustomerID     string           `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	Units          []OrgChartNode   `json:"units"`
+	Unassigned     []OrgChartPerson `json:"unassigned"`      // contact yang belum ditempatkan di unit
+	ReportingLines []OrgChartPerson `json:"reporting_lines"` // pohon atasan-bawahan, root = contact tanpa atasan
+}
+
+// Sosmed snapshot & growth DTOs
+type SosmedSnapshotRequest struct {
+	Followers  *int   `json:"followers" binding:"required,min=0" example:"15230"`
+	RecordedAt string `json:"recorded_at" example:"2025-01-31"` // kosong = sekarang
+	Note       string `json:"note" example:"Data dari Meta Business Suite"`
+}
+
+type SosmedGrowth struct {
+	SosmedID       string     `json:"sosmed_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerID     string     `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName   string     `json:"customer_name" example:"PT Digi Inno"`
+	Platform       string     `json:"platform" example:"Instagram"`
+	Handle         string     `json:"handle" example:"@digiinno_id"`
+	StartFollowers int        `json:"start_followers" example:"12000"`
+	EndFollowers   int        `json:"end_followers" example:"15230"`
+	Change         int        `json:"change" example:"3230"`
+	PercentChange  *float64   `json:"percent_change" example:"26.92"` // null jika start_followers = 0
+	StartAt        *time.Time `json:"start_at"`
+	EndAt          *time.Time `json:"end_at"`
+}
+
+type CustomerSosmedGrowth struct {
+	CustomerID     string   `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName   string   `json:"customer_name" example:"PT Digi Inno"`
+	Accounts       int      `json:"accounts" example:"3"`
+	StartFollowers int      `json:"start_followers" example:"12000"`
+	EndFollowers   int      `json:"end_followers" example:"15230"`
+	Change         int      `json:"change" example:"3230"`
+	PercentChange  *float64 `json:"percent_change" example:"26.92"`
+}
+
+type SosmedSnapshotImportResult struct {
+	Imported int      `json:"imported" example:"120"`
+	Updated  int      `json:"updated" example:"4"`
+	Errors   []string `json:"errors"`
+}
+
+// AddressCoordinatesRequest mengisi atau menghapus (null) koordinat alamat
+type AddressCoordinatesRequest struct {
+	Latitude  *float64 `json:"latitude" example:"-6.2088"`
+	Longitude *float64 `json:"longitude" example:"106.8456"`
+}
+
+// AddressCoordinatesImportResult adalah ringkasan import koordinat alamat dari CSV
+type AddressCoordinatesImportResult struct {
+	Updated int      `json:"updated" example:"85"`
+	Errors  []string `json:"errors"`
+}
+
+// NearbyCustomer adalah customer beserta alamat terdekatnya dari titik pencarian
+type NearbyCustomer struct {
+	CustomerID         string  `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName       string  `json:"customer_name" example:"PT Maju Jaya"`
+	CustomerCode       string  `json:"customer_code" example:"CUST001"`
+	Status             string  `json:"status" example:"Active"`
+	AccountManagerID   *string `json:"account_manager_id,omitempty" example:"AM001"`
+	AccountManagerName *string `json:"account_manager_name,omitempty" example:"Budi"`
+	AddressID          string  `json:"address_id" example:"01HXYZ123456789ABCDEG"`
+	AddressName        string  `json:"address_name" example:"Head Office"`
+	Address            string  `json:"address" example:"Jl. Sudirman No. 123"`
+	City               string  `json:"city" example:"KOTA ADM. JAKARTA SELATAN"`
+	Latitude           float64 `json:"latitude" example:"-6.2088"`
+	Longitude          float64 `json:"longitude" example:"106.8456"`
+	DistanceKm         float64 `json:"distance_km" example:"1.24"`
+}
+
+// RoutePlanRequest meminta urutan kunjungan dari titik awal ke sejumlah customer
+// atau ke activity yang terjadwal pada satu hari
+type RoutePlanRequest struct {
+	StartLatitude  *float64 `json:"start_latitude" binding:"required" example:"-6.2088"`
+	StartLongitude *float64 `json:"start_longitude" binding:"required" example:"106.8456"`
+	CustomerIDs    []string `json:"customer_ids"`                       // mode customer
+	Date           string   `json:"date" example:"2024-01-15"`          // mode activity: activity pada tanggal ini
+	UserID         string   `json:"user_id" example:"01HXYZ123456789A"` // mode activity: default user yang login, user lain hanya untuk admin
+	ReturnToStart  bool     `json:"return_to_start" example:"false"`
+
+	// Penjadwalan (opsional, hanya mode customer)
+	CreateActivities bool    `json:"create_activities" example:"false"`
+	StartTime        string  `json:"start_time" example:"2024-01-15T09:00:00+07:00"`
+	VisitMinutes     int     `json:"visit_minutes" example:"60"`
+	TravelSpeedKmh   float64 `json:"travel_speed_kmh" example:"30"`
+	ActivityType     string  `json:"activity_type" example:"Visit"`
+	Title            string  `json:"title" example:"Customer visit"`
+}
+
+// RouteStop adalah satu titik kunjungan pada rencana rute
+type RouteStop struct {
+	Sequence      int     `json:"sequence" example:"1"`
+	CustomerID    string  `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName  string  `json:"customer_name" example:"PT Maju Jaya"`
+	ActivityID    string  `json:"activity_id,omitempty" example:"01HXYZ123456789ABCDEJ"`
+	AddressID     string  `json:"address_id" example:"01HXYZ123456789ABCDEG"`
+	AddressName   string  `json:"address_name" example:"Head Office"`
+	Address       string  `json:"address" example:"Jl. Sudirman No. 123"`
+	Latitude      float64 `json:"latitude" example:"-6.2088"`
+	Longitude     float64 `json:"longitude" example:"106.8456"`
+	LegDistanceKm float64 `json:"leg_distance_km" example:"3.2"`
+	CumulativeKm  float64 `json:"cumulative_km" example:"7.9"`
+	PlannedStart  string  `json:"planned_start,omitempty" example:"2024-01-15T09:15:00+07:00"`
+	PlannedEnd    string  `json:"planned_end,omitempty" example:"2024-01-15T10:15:00+07:00"`
+}
+
+// RouteSkipped adalah customer/activity yang tidak bisa dimasukkan ke rute
+type RouteSkipped struct {
+	CustomerID string `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	ActivityID string `json:"activity_id,omitempty" example:"01HXYZ123456789ABCDEJ"`
+	Reason     string `json:"reason" example:"no address with coordinates"`
+}
+
+// RoutePlanResponse adalah hasil perencanaan rute kunjungan
+type RoutePlanResponse struct {
+	Stops             []RouteStop        `json:"stops"`
+	Skipped           []RouteSkipped     `json:"skipped"`
+	TotalDistanceKm   float64            `json:"total_distance_km" example:"18.4"`
+	ReturnDistanceKm  float64            `json:"return_distance_km,omitempty" example:"4.1"`
+	CreatedActivities []ActivityResponse `json:"created_activities,omitempty"`
+}
+
+// ReassignCustomersRequest memindahkan customer dari satu account manager ke account manager lain.
+// Tanpa customer_ids dan filter, semua customer milik account manager asal dipindahkan.
+type ReassignCustomersRequest struct {
+	ToAccountManagerID string   `json:"to_account_manager_id" binding:"required" example:"AB12C"`
+	CustomerIDs        []string `json:"customer_ids"`
+	Status             string   `json:"status" example:"Active"`
+	Category           string   `json:"category" example:"Enterprise"`
+	GroupID            string   `json:"group_id" example:"01HXYZ123456789ABCDEF"`
+	Reason             string   `json:"reason" example:"AM resigned"`
+	DryRun             bool     `json:"dry_run" example:"false"`
+}
+
+// ReassignCustomersResult adalah hasil reassign massal
+type ReassignCustomersResult struct {
+	BatchID              string   `json:"batch_id,omitempty" example:"01HXYZ123456789ABCDEK"`
+	FromAccountManagerID string   `json:"from_account_manager_id" example:"XY34Z"`
+	ToAccountManagerID   string   `json:"to_account_manager_id" example:"AB12C"`
+	Reassigned           int      `json:"reassigned" example:"42"`
+	CustomerIDs          []string `json:"customer_ids"`
+	DryRun               bool     `json:"dry_run" example:"false"`
+}
+
+// PortfolioStatusBreakdown adalah jumlah customer dan total AverageCost per status
+type PortfolioStatusBreakdown struct {
+	Status           string  `json:"status" example:"Active"`
+	CustomerCount    int64   `json:"customer_count" example:"30"`
+	TotalAverageCost float64 `json:"total_average_cost" example:"150000000"`
+}
+
+// AccountManagerPortfolio adalah ringkasan portofolio customer seorang account manager
+type AccountManagerPortfolio struct {
+	AccountManagerID   *string                    `json:"account_manager_id" example:"AB12C"` // null = customer tanpa account manager
+	AccountManagerName string                     `json:"account_manager_name" example:"John Doe"`
+	CustomerCount      int64                      `json:"customer_count" example:"42"`
+	TotalAverageCost   float64                    `json:"total_average_cost" example:"250000000"`
+	StatusBreakdown    []PortfolioStatusBreakdown `json:"status_breakdown"`
+}
+
+// TeamMemberRequest menambahkan anggota tim yang ditautkan ke akun user
+type TeamMemberRequest struct {
+	UserID       string `json:"user_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	JobPosition  string `json:"job_position" binding:"required" example:"Sales Executive"`
+	EmployeeName string `json:"employee_name" example:"Budi Santoso"` // default username
+	PhoneNumber  string `json:"phone_number" example:"+62812345678"`
+}
+
+// CustomerTeamRequest memindahkan customer ke tim lain (null untuk melepas tim)
+type CustomerTeamRequest struct {
+	TeamID *string `json:"team_id" example:"01HXYZ123456789ABCDEF"`
+}
+
+// AssignTeamCustomersRequest meng-assign banyak customer ke satu tim secara manual
+type AssignTeamCustomersRequest struct {
+	CustomerIDs []string `json:"customer_ids" binding:"required"`
+}
+
+// TerritoryRequest membuat atau mengubah territory; minimal salah satu dari region_code atau group_id diisi
+type TerritoryRequest struct {
+	TeamID     string  `json:"team_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	Name       string  `json:"name" binding:"required" example:"Jakarta Selatan"`
+	RegionCode string  `json:"region_code" example:"31.71"`
+	GroupID    *string `json:"group_id" example:"01HXYZ123456789ABCDEG"`
+	Priority   *int    `json:"priority" example:"100"`
+	Active     *bool   `json:"active" example:"true"`
+}
+
+// TeamAutoAssignRequest menjalankan aturan territory. Tanpa customer_ids semua customer diproses;
+// customer yang di-assign manual hanya ikut jika overwrite = true.
+type TeamAutoAssignRequest struct {
+	CustomerIDs []string `json:"customer_ids"`
+	Overwrite   bool     `json:"overwrite" example:"false"`
+	DryRun      bool     `json:"dry_run" example:"false"`
+}
+
+// TeamAssignmentChange adalah satu perubahan tim hasil auto-assign
+type TeamAssignmentChange struct {
+	CustomerID    string  `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName  string  `json:"customer_name" example:"PT Maju Jaya"`
+	FromTeamID    *string `json:"from_team_id" example:"01HXYZ123456789ABCDEG"`
+	ToTeamID      *string `json:"to_team_id" example:"01HXYZ123456789ABCDEH"`
+	TerritoryID   string  `json:"territory_id,omitempty" example:"01HXYZ123456789ABCDEJ"`
+	TerritoryName string  `json:"territory_name,omitempty" example:"Jakarta Selatan"`
+}
+
+// TeamAutoAssignResult adalah ringkasan auto-assign tim
+type TeamAutoAssignResult struct {
+	Processed int                    `json:"processed" example:"120"`
+	Changed   int                    `json:"changed" example:"14"`
+	Unmatched int                    `json:"unmatched" example:"9"`
+	Changes   []TeamAssignmentChange `json:"changes"`
+	DryRun    bool                   `json:"dry_run" example:"false"`
+}
+
+// AccessPolicyRequest mengatur visibilitas customer untuk sebuah role
+type AccessPolicyRequest struct {
+	Scope    string `json:"scope" binding:"required" example:"team"` // all, team, portfolio
+	ReadOnly bool   `json:"read_only" example:"false"`
+}
+
+// AccessExplanation menjelaskan apakah sebuah record terlihat oleh user dan alasannya
+type AccessExplanation struct {
+	UserID           string   `json:"user_id" example:"01HXYZ123456789ABCDEF"`
+	Username         string   `json:"username" example:"budi"`
+	RoleName         string   `json:"role_name" example:"Sales"`
+	Scope            string   `json:"scope" example:"team"`
+	ReadOnly         bool     `json:"read_only" example:"false"`
+	TeamIDs          []string `json:"team_ids"`
+	AccountManagerID string   `json:"account_manager_id,omitempty" example:"01HXYZ123456789ABCDEG"`
+	Resource         string   `json:"resource" example:"contact"`
+	ResourceID       string   `json:"resource_id" example:"01HXYZ123456789ABCDEH"`
+	CustomerID       string   `json:"customer_id" example:"01HXYZ123456789ABCDEI"`
+	CustomerName     string   `json:"customer_name" example:"PT Maju Jaya"`
+	CustomerTeamID   *string  `json:"customer_team_id"`
+	CustomerAMID     *string  `json:"customer_account_manager_id"`
+	Visible          bool     `json:"visible" example:"false"`
+	Writable         bool     `json:"writable" example:"false"`
+	Reason           string   `json:"reason" example:"Policy role Sales membatasi ke scope team; customer dimiliki team lain"`
+}
+
+// OthersConfigOptionRequest adalah satu pilihan custom field bertipe enum
+type OthersConfigOptionRequest struct {
+	Value     string `json:"value" binding:"required" example:"enterprise"`
+	Label     string `json:"label" example:"Enterprise"`
+	Icon      string `json:"icon" example:"building"`
+	SortOrder int    `json:"sort_order" example:"1"`
+}
+
+// OthersConfigRequest mendefinisikan custom field customer
+type OthersConfigRequest struct {
+	Key       string                      `json:"key" binding:"required" example:"company_size"`
+	Label     string                      `json:"label" binding:"required" example:"Company Size"`
+	DataType  string                      `json:"data_type" binding:"required" example:"enum"` // text, number, date, enum, boolean, url
+	Required  bool                        `json:"required" example:"false"`
+	Icon      string                      `json:"icon" example:"users"`
+	SortOrder int                         `json:"sort_order" example:"1"`
+	IsActive  *bool                       `json:"is_active" example:"true"`
+	Options   []OthersConfigOptionRequest `json:"options,omitempty"`
+}
+
+// UpdateOthersConfigRequest mengubah custom field; key tidak dapat diubah. options (jika dikirim) menggantikan semua pilihan.
+type UpdateOthersConfigRequest struct {
+	Label     *string                     `json:"label" example:"Company Size"`
+	DataType  *string                     `json:"data_type" example:"enum"`
+	Required  *bool                       `json:"required" example:"true"`
+	Icon      *string                     `json:"icon" example:"users"`
+	SortOrder *int                        `json:"sort_order" example:"1"`
+	IsActive  *bool                       `json:"is_active" example:"true"`
+	Options   []OthersConfigOptionRequest `json:"options,omitempty"`
+}
+
+// CustomFieldValueConflict adalah nilai custom field tersimpan yang tidak valid untuk tipe/pilihan baru
+type CustomFieldValueConflict struct {
+	OtherID    string `json:"other_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerID string `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	Value      string `json:"value" example:"sekitar 50"`
+	Error      string `json:"error" example:"Employee Count must be a number"`
+}
+
+// CustomerRelationshipRequest represents customer relationship create/update request.
+// Dibaca "from <type> to", mis. from referred-by to = from direferensikan oleh to.
+type CustomerRelationshipRequest struct {
+	FromCustomerID string  `json:"from_customer_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	ToCustomerID   string  `json:"to_customer_id" binding:"required" example:"01HXYZ123456789ABCDEG"`
+	Type           string  `json:"type" binding:"required" example:"referred-by"` // partner, reseller, competitor, supplier-of, referred-by
+	StartDate      *string `json:"start_date" example:"2024-01-01"`
+	EndDate        *string `json:"end_date" example:"2025-12-31"`
+	Note           string  `json:"note" example:"Referensi dari acara expo"`
+}
+
+// RelatedCustomer adalah customer di sisi lain sebuah relasi
+type RelatedCustomer struct {
+	ID         string `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Name       string `json:"name,omitempty" example:"PT Mitra Jaya"`
+	Code       string `json:"code,omitempty" example:"MJ001"`
+	Status     string `json:"status,omitempty" example:"Active"`
+	Restricted bool   `json:"restricted,omitempty"` // customer di luar akses user
+}
+
+// CustomerRelationshipView adalah relasi dilihat dari sisi satu customer
+type CustomerRelationshipView struct {
+	ID        string          `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Type      string          `json:"type" example:"referred-by"`
+	Direction string          `json:"direction" example:"incoming"` // outgoing: customer ini sisi "from"
+	Label     string          `json:"label" example:"referred"`     // arti relasi dari sisi customer ini
+	Customer  RelatedCustomer `json:"customer"`
+	StartDate *time.Time      `json:"start_date" example:"2024-01-01T00:00:00Z"`
+	EndDate   *time.Time      `json:"end_date" example:"2025-12-31T00:00:00Z"`
+	Active    bool            `json:"active" example:"true"`
+	Note      string          `json:"note,omitempty" example:"Referensi dari acara expo"`
+}
+
+// RelationshipGraphNode adalah satu customer dalam graf relasi
+type RelationshipGraphNode struct {
+	RelatedCustomer
+	Hops int `json:"hops" example:"1"` // jarak dari customer pusat
+}
+
+// RelationshipGraphEdge adalah satu relasi dalam graf
+type RelationshipGraphEdge struct {
+	ID        string     `json:"id" example:"01HXYZ123456789ABCDEF"`
+	From      string     `json:"from" example:"01HXYZ123456789ABCDEF"`
+	To        string     `json:"to" example:"01HXYZ123456789ABCDEG"`
+	Type      string     `json:"type" example:"partner"`
+	Directed  bool       `json:"directed" example:"false"`
+	StartDate *time.Time `json:"start_date" example:"2024-01-01T00:00:00Z"`
+	EndDate   *time.Time `json:"end_date" example:"2025-12-31T00:00:00Z"`
+	Active    bool       `json:"active" example:"true"`
+}
+
+// RelationshipGraph adalah lingkungan N-hop sebuah customer untuk visualisasi
+type RelationshipGraph struct {
+	CustomerID string                  `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	Depth      int                     `json:"depth" example:"2"`
+	Nodes      []RelationshipGraphNode `json:"nodes"`
+	Edges      []RelationshipGraphEdge `json:"edges"`
+	Truncated  bool                    `json:"truncated"` // true jika batas jumlah node tercapai
+}
+
+// CustomerAnalyticsGrowth adalah pertumbuhan (%) terhadap bulan sebelumnya
+type CustomerAnalyticsGrowth struct {
+	Total   float64 `json:"total" example:"2.5"`
+	New     float64 `json:"new" example:"-10"`
+	Active  float64 `json:"active" example:"1.2"`
+	Blocked float64 `json:"blocked" example:"0"`
+	Churned float64 `json:"churned" example:"50"`
+}
+
+// CustomerAnalyticsPoint adalah angka customer untuk satu bulan
+type CustomerAnalyticsPoint struct {
+	Month     string                  `json:"month" example:"2024-05"`
+	Total     int64                   `json:"total" example:"420"`      // customer yang sudah terdaftar di akhir bulan
+	New       int64                   `json:"new" example:"12"`         // customer yang dibuat pada bulan ini
+	Active    int64                   `json:"active" example:"310"`     // tidak diblokir dan ada aktivitas/invoice dalam churn window
+	Blocked   int64                   `json:"blocked" example:"8"`      // status blocked di akhir bulan menurut riwayat status
+	Inactive  int64                   `json:"inactive" example:"102"`   // tidak diblokir tetapi tanpa aktivitas/invoice dalam churn window
+	Churned   int64                   `json:"churned" example:"5"`      // aktif di akhir bulan sebelumnya, tidak aktif di akhir bulan ini
+	ChurnRate float64                 `json:"churn_rate" example:"1.6"` // churned / aktif bulan sebelumnya (%)
+	Growth    CustomerAnalyticsGrowth `json:"growth"`
+}
+
+// CustomerAnalyticsSummary merangkum seluruh periode yang diminta
+type CustomerAnalyticsSummary struct {
+	New          int64   `json:"new" example:"120"`
+	Churned      int64   `json:"churned" example:"40"`
+	ActiveStart  int64   `json:"active_start" example:"280"` // aktif di akhir bulan sebelum periode
+	ActiveEnd    int64   `json:"active_end" example:"310"`
+	ActiveGrowth float64 `json:"active_growth" example:"10.7"`
+	AvgChurnRate float64 `json:"avg_churn_rate" example:"1.2"`
+	BlockedEnd   int64   `json:"blocked_end" example:"8"`
+	TotalEnd     int64   `json:"total_end" example:"420"`
+}
+
+// CustomerAnalyticsResponse adalah deret waktu bulanan customer beserta ringkasannya
+type CustomerAnalyticsResponse struct {
+	From        string                   `json:"from" example:"2024-01"`
+	To          string                   `json:"to" example:"2024-12"`
+	ChurnMonths int                      `json:"churn_months" example:"3"`
+	Series      []CustomerAnalyticsPoint `json:"series"`
+	Summary     CustomerAnalyticsSummary `json:"summary"`
+}
+
+// HealthSignalRequest mengubah bobot dan parameter satu sinyal health score
+type HealthSignalRequest struct {
+	Name       *string  `json:"name" example:"Days since last activity"`
+	Weight     *float64 `json:"weight" binding:"omitempty,min=0" example:"30"`
+	Threshold  *float64 `json:"threshold" binding:"omitempty,min=0" example:"90"`
+	WindowDays *int     `json:"window_days" binding:"omitempty,min=0" example:"180"`
+	Active     *bool    `json:"active" example:"true"`
+}
+
+// CustomerAssessmentRequest mencatat hasil assessment untuk customer
+type CustomerAssessmentRequest struct {
+	AssessmentID string   `json:"assessment_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	Score        *float64 `json:"score" binding:"required,min=0,max=100" example:"82.5"`
+	Note         string   `json:"note" example:"Quarterly review"`
+	AssessedAt   *string  `json:"assessed_at" example:"2024-05-01"` // default: sekarang
+}
+
+// HealthScorePoint adalah satu titik riwayat health score
+type HealthScorePoint struct {
+	Score     float64   `json:"score" example:"72.5"`
+	Trigger   string    `json:"trigger" example:"nightly"`
+	CreatedAt time.Time `json:"created_at" example:"2024-05-01T01:00:00Z"`
+}
+
+// CreateOpportunityRequest membuat opportunity baru; tanpa stage_id dimulai di stage aktif pertama
+type CreateOpportunityRequest struct {
+	Name              string   `json:"name" binding:"required" example:"ERP rollout 2025"`
+	CustomerID        string   `json:"customer_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	ProjectID         *string  `json:"project_id" example:"01HXYZ123456789ABCDEF"`
+	StageID           *string  `json:"stage_id" example:"01HXYZ123456789ABCDEF"`
+	OwnerID           *string  `json:"owner_id" example:"01HXYZ123456789ABCDEF"` // default: user yang login
+	Value             float64  `json:"value" binding:"min=0" example:"150000000"`
+	Probability       *float64 `json:"probability" binding:"omitempty,min=0,max=100" example:"40"` // default: probability stage
+	ExpectedCloseDate *string  `json:"expected_close_date" example:"2025-03-31"`
+	Description       string   `json:"description" example:"Phase 1 finance and procurement"`
+}
+
+// UpdateOpportunityRequest mengubah data opportunity; stage dan status diubah lewat endpoint stage/close
+type UpdateOpportunityRequest struct {
+	Name              *string  `json:"name" example:"ERP rollout 2025"`
+	ProjectID         *string  `json:"project_id" example:"01HXYZ123456789ABCDEF"` // string kosong melepas project
+	OwnerID           *string  `json:"owner_id" example:"01HXYZ123456789ABCDEF"`
+	Value             *float64 `json:"value" binding:"omitempty,min=0" example:"150000000"`
+	Probability       *float64 `json:"probability" binding:"omitempty,min=0,max=100" example:"40"`
+	ExpectedCloseDate *string  `json:"expected_close_date" example:"2025-03-31"` // string kosong menghapus tanggal
+	Description       *string  `json:"description" example:"Phase 1 finance and procurement"`
+}
+
+// OpportunityStageRequest memindahkan opportunity ke stage lain
+type OpportunityStageRequest struct {
+	StageID     string   `json:"stage_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	Probability *float64 `json:"probability" binding:"omitempty,min=0,max=100" example:"60"` // default: probability stage tujuan
+	Reason      string   `json:"reason" example:"Proposal sent"`
+}
+
+// CloseOpportunityRequest menutup opportunity sebagai won atau lost
+type CloseOpportunityRequest struct {
+	Outcome  string  `json:"outcome" binding:"required,oneof=won lost" example:"lost"`
+	Reason   string  `json:"reason" binding:"required" example:"Lost on price"`
+	ClosedAt *string `json:"closed_at" example:"2025-03-28"` // default: sekarang
+}
+
+// OpportunityCard adalah ringkasan opportunity untuk satu kartu kanban
+type OpportunityCard struct {
+	ID                string     `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Name              string     `json:"name" example:"ERP rollout 2025"`
+	CustomerID        string     `json:"customer_id" example:"01HXYZ123456789ABCDEF"`
+	CustomerName      string     `json:"customer_name" example:"PT Teknologi Maju"`
+	OwnerID           string     `json:"owner_id" example:"01HXYZ123456789ABCDEF"`
+	OwnerName         string     `json:"owner_name" example:"jdoe"`
+	Value             float64    `json:"value" example:"150000000"`
+	Probability       float64    `json:"probability" example:"40"`
+	WeightedValue     float64    `json:"weighted_value" example:"60000000"`
+	ExpectedCloseDate *time.Time `json:"expected_close_date" example:"2025-03-31T00:00:00Z"`
+	DaysInStage       int        `json:"days_in_stage" example:"12"`
+}
+
+// KanbanColumn adalah satu kolom stage di papan pipeline
+type KanbanColumn struct {
+	StageID       string            `json:"stage_id" example:"01HXYZ123456789ABCDEF"`
+	StageName     string            `json:"stage_name" example:"Proposal"`
+	Sequence      int               `json:"sequence" example:"2"`
+	Probability   float64           `json:"probability" example:"40"`
+	Count         int               `json:"count" example:"8"`
+	TotalValue    float64           `json:"total_value" example:"900000000"`
+	WeightedValue float64           `json:"weighted_value" example:"360000000"`
+	Opportunities []OpportunityCard `json:"opportunities"`
+}
+
+// ForecastRow adalah forecast satu owner pada satu bulan (berdasarkan expected close date)
+type ForecastRow struct {
+	Month         string  `json:"month" example:"2025-03"`
+	OwnerID       string  `json:"owner_id" example:"01HXYZ123456789ABCDEF"`
+	OwnerName     string  `json:"owner_name" example:"jdoe"`
+	OpenCount     int64   `json:"open_count" example:"4"`
+	PipelineValue float64 `json:"pipeline_value" example:"400000000"`
+	WeightedValue float64 `json:"weighted_value" example:"180000000"`
+	WonCount      int64   `json:"won_count" example:"1"`
+	WonValue      float64 `json:"won_value" example:"120000000"` // nilai yang sudah won dengan closed_at di bulan ini
+}
+
+// ForecastTotal adalah total forecast satu bulan untuk semua owner
+type ForecastTotal struct {
+	Month         string  `json:"month" example:"2025-03"`
+	OpenCount     int64   `json:"open_count" example:"12"`
+	PipelineValue float64 `json:"pipeline_value" example:"1200000000"`
+	WeightedValue float64 `json:"weighted_value" example:"500000000"`
+	WonCount      int64   `json:"won_count" example:"3"`
+	WonValue      float64 `json:"won_value" example:"320000000"`
+}
+
+// ForecastResponse adalah weighted forecast per bulan dan per owner
+type ForecastResponse struct {
+	From        string          `json:"from" example:"2025-01"`
+	To          string          `json:"to" example:"2025-06"`
+	Rows        []ForecastRow   `json:"rows"`
+	Months      []ForecastTotal `json:"months"`
+	Unscheduled ForecastTotal   `json:"unscheduled"` // opportunity open tanpa expected close date
+}
+
+// CreateProjectRequest adalah payload pembuatan project milik customer
+type CreateProjectRequest struct {
+	Name        string  `json:"name" binding:"required" example:"ERP Rollout"`
+	Description string  `json:"description" example:"Implementasi ERP fase 1"`
+	CustomerID  string  `json:"customer_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	OwnerID     *string `json:"owner_id" example:"01HXYZ123456789ABCDEG"` // default: user yang membuat
+	Budget      float64 `json:"budget" binding:"min=0" example:"500000000"`
+	HourlyRate  float64 `json:"hourly_rate" binding:"min=0" example:"750000"` // tarif jam kerja billable
+	StartDate   *string `json:"start_date" example:"2025-01-01"`
+	EndDate     *string `json:"end_date" example:"2025-06-30"`
+	Status      string  `json:"status" binding:"omitempty,oneof=planned active on_hold completed cancelled" example:"planned"`
+}
+
+// UpdateProjectRequest adalah payload perubahan project, field kosong tidak diubah
+type UpdateProjectRequest struct {
+	Name        *string  `json:"name" example:"ERP Rollout"`
+	Description *string  `json:"description" example:"Implementasi ERP fase 1"`
+	OwnerID     *string  `json:"owner_id" example:"01HXYZ123456789ABCDEG"` // string kosong melepas owner
+	Budget      *float64 `json:"budget" binding:"omitempty,min=0" example:"500000000"`
+	HourlyRate  *float64 `json:"hourly_rate" binding:"omitempty,min=0" example:"750000"`
+	StartDate   *string  `json:"start_date" example:"2025-01-01"` // string kosong mengosongkan tanggal
+	EndDate     *string  `json:"end_date" example:"2025-06-30"`   // string kosong mengosongkan tanggal
+	Status      *string  `json:"status" binding:"omitempty,oneof=planned active on_hold completed cancelled" example:"active"`
+	IsActive    *bool    `json:"is_active" example:"true"`
+}
+
+// ProjectMemberRequest adalah payload penambahan anggota project
+type ProjectMemberRequest struct {
+	UserID string `json:"user_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	Role   string `json:"role" binding:"omitempty,oneof=manager member viewer" example:"member"` // default: member
+}
+
+// UpdateProjectMemberRequest adalah payload perubahan peran anggota project
+type UpdateProjectMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=manager member viewer" example:"manager"`
+}
+
+// ProjectMilestoneRequest adalah payload pembuatan milestone project
+type ProjectMilestoneRequest struct {
+	Name        string  `json:"name" binding:"required" example:"Go-live"`
+	Description string  `json:"description" example:"Sistem berjalan di production"`
+	DueDate     *string `json:"due_date" example:"2025-05-31"`
+	Amount      float64 `json:"amount" binding:"min=0" example:"150000000"`
+}
+
+// UpdateProjectMilestoneRequest adalah payload perubahan milestone, field kosong tidak diubah
+type UpdateProjectMilestoneRequest struct {
+	Name        *string  `json:"name" example:"Go-live"`
+	Description *string  `json:"description" example:"Sistem berjalan di production"`
+	DueDate     *string  `json:"due_date" example:"2025-06-15"` // string kosong mengosongkan tanggal
+	Amount      *float64 `json:"amount" binding:"omitempty,min=0" example:"150000000"`
+	Status      *string  `json:"status" binding:"omitempty,oneof=pending completed" example:"completed"`
+}
+
+// ProjectBudgetResponse membandingkan budget project dengan nilai yang sudah ditagihkan
+type ProjectBudgetResponse struct {
+	ProjectID          string  `json:"project_id" example:"01HXYZ123456789ABCDEF"`
+	Budget             float64 `json:"budget" example:"500000000"`
+	Invoiced           float64 `json:"invoiced" example:"300000000"`
+	Paid               float64 `json:"paid" example:"200000000"`
+	Outstanding        float64 `json:"outstanding" example:"100000000"` // ditagihkan tetapi belum dibayar
+	Remaining          float64 `json:"remaining" example:"200000000"`   // budget yang belum ditagihkan
+	InvoicedPercent    float64 `json:"invoiced_percent" example:"60"`
+	OverBudget         bool    `json:"over_budget" example:"false"`
+	InvoiceCount       int64   `json:"invoice_count" example:"3"`
+	MilestoneCount     int64   `json:"milestone_count" example:"4"`
+	MilestoneCompleted int64   `json:"milestone_completed" example:"2"`
+	MilestoneAmount    float64 `json:"milestone_amount" example:"450000000"` // total nilai milestone
+}
+
+// CreateTimeEntryRequest adalah payload pencatatan jam kerja oleh user yang login
+type CreateTimeEntryRequest struct {
+	ProjectID  string  `json:"project_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	ActivityID *string `json:"activity_id" example:"01HXYZ123456789ABCDEG"`
+	EventID    *string `json:"event_id" example:"01HXYZ123456789ABCDEH"`
+	Date       string  `json:"date" binding:"required" example:"2025-03-10"`
+	Hours      float64 `json:"hours" binding:"required,gt=0,max=24" example:"3.5"`
+	Billable   *bool   `json:"billable" example:"true"` // default: true
+	Note       string  `json:"note" example:"Workshop requirement gudang"`
+}
+
+// UpdateTimeEntryRequest adalah payload perubahan time entry draft/rejected, field kosong tidak diubah
+type UpdateTimeEntryRequest struct {
+	ActivityID *string  `json:"activity_id" example:"01HXYZ123456789ABCDEG"` // string kosong melepas aktivitas
+	EventID    *string  `json:"event_id" example:"01HXYZ123456789ABCDEH"`    // string kosong melepas event
+	Date       *string  `json:"date" example:"2025-03-11"`
+	Hours      *float64 `json:"hours" binding:"omitempty,gt=0,max=24" example:"4"`
+	Billable   *bool    `json:"billable" example:"false"`
+	Note       *string  `json:"note" example:"Revisi dokumen"`
+}
+
+// SubmitTimesheetRequest adalah payload pengajuan jam kerja satu minggu pada satu project
+type SubmitTimesheetRequest struct {
+	ProjectID string `json:"project_id" binding:"required" example:"01HXYZ123456789ABCDEF"`
+	Week      string `json:"week" binding:"required" example:"2025-03-10"` // tanggal mana saja di minggu tersebut
+}
+
+// ReviewTimesheetRequest adalah catatan owner project saat menyetujui/menolak timesheet
+type ReviewTimesheetRequest struct {
+	Note string `json:"note" example:"Jam workshop sudah sesuai"`
+}
+
+// TimesheetReportRow adalah total jam kerja untuk satu user, project atau customer
+type TimesheetReportRow struct {
+	ID                    string  `json:"id" example:"01HXYZ123456789ABCDEF"`
+	Name                  string  `json:"name" example:"ERP Rollout"`
+	Entries               int64   `json:"entries" example:"14"`
+	TotalHours            float64 `json:"total_hours" example:"52"`
+	BillableHours         float64 `json:"billable_hours" example:"44"`
+	ApprovedHours         float64 `json:"approved_hours" example:"40"`
+	ApprovedBillableHours float64 `json:"approved_billable_hours" example:"36"`
+	InvoicedHours         float64 `json:"invoiced_hours" example:"24"`
+}
+
+// TimesheetReportResponse adalah laporan jam kerja dalam satu periode
+type TimesheetReportResponse struct {
+	GroupBy string               `json:"group_by" example:"project"`
+	From    string               `json:"from" example:"2025-03-01"`
+	To      string               `json:"to" example:"2025-03-31"`
+	Rows    []TimesheetReportRow `json:"rows"`
+	Total   TimesheetReportRow   `json:"total"`
+}
+
+// InvoiceTimeRequest adalah payload konversi jam billable yang sudah disetujui menjadi baris invoice.
+// Tanpa invoice_id dibuat invoice baru dengan invoice_number, issued_date dan due_date.
+type InvoiceTimeRequest struct {
+	InvoiceID     *string  `json:"invoice_id" example:"01HXYZ123456789ABCDEF"`
+	InvoiceNumber string   `json:"invoice_number" example:"INV-2025-031"`
+	IssuedDate    *string  `json:"issued_date" example:"2025-04-01"`                      // default: hari ini
+	DueDate       *string  `json:"due_date" example:"2025-05-01"`                         // default: 30 hari setelah issued_date
+	Until         *string  `json:"until" example:"2025-03-31"`                            // hanya jam sampai tanggal ini
+	HourlyRate    *float64 `json:"hourly_rate" binding:"omitempty,gt=0" example:"750000"` // default: tarif project
+}
+
+// InvoiceLineResponse adalah rincian baris invoice
+type InvoiceLineResponse struct {
+	ID          string  `json:"id" example:"01HXYZ123456789ABCDEF"`
+	ProjectID   *string `json:"project_id,omitempty" example:"01HXYZ123456789ABCDEG"`
+	UserID      *string `json:"user_id,omitempty" example:"01HXYZ123456789ABCDEH"`
+	Description string  `json:"description" example:"Konsultasi - budi (2025-03-01 s/d 2025-03-31)"`
+	Quantity    float64 `json:"quantity" example:"24"`
+	UnitPrice   float64 `json:"unit_price" example:"750000"`
+	Amount      float64 `json:"amount" example:"18000000"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// AccessPolicy mengatur customer mana yang boleh dilihat oleh user dengan role tertentu.
+// Role tanpa policy dapat melihat dan mengubah semua customer.
+type AccessPolicy struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:26"`
+	RoleID    string    `json:"role_id" gorm:"size:36;not null;uniqueIndex"`
+	Scope     string    `json:"scope" gorm:"size:20;not null;default:'all'"` // all, team, portfolio
+	ReadOnly  bool      `json:"read_only" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	Role *Role `json:"role,omitempty" gorm:"foreignKey:RoleID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (p *AccessPolicy) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	p.ID = id.String()
+	return nil
+}
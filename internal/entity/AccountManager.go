@@ -11,11 +11,16 @@ import (
 type AccountManager struct {
 	ID          string         `gorm:"type:varchar(5);primaryKey" json:"id"`
 	ManagerName string         `gorm:"type:varchar(255);not null" json:"manager_name"`
+	UserID      *string        `gorm:"size:26;uniqueIndex" json:"user_id"` // akun login account manager
+	Email       string         `gorm:"type:varchar(255)" json:"email"`
+	Phone       string         `gorm:"type:varchar(50)" json:"phone"`
+	IsActive    bool           `gorm:"default:true" json:"is_active"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
+	User      *User      `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID"`
 	Customers []Customer `json:"customers,omitempty" gorm:"foreignKey:AccountManagerID"`
 }
 
@@ -10,7 +10,9 @@ import (
 // Activity model - tabel untuk aktivitas customer
 type Activity struct {
 	ID		string         		`json:"id" gorm:"primaryKey;size:26"`
-	CustomerID   uint           `json:"customer_id" gorm:"not null"`
+	CustomerID   string         `json:"customer_id" gorm:"not null;size:26;index"`
+	AddressID    *string        `json:"address_id" gorm:"size:26"` // alamat yang dikunjungi (opsional)
+	ProjectID    *string        `json:"project_id" gorm:"size:26;index"` // project terkait (opsional)
 	Title        string         `json:"title" gorm:"not null"`
 	Type         string         `json:"type" gorm:"not null"`
 	Agenda       string         `json:"agenda"`
@@ -18,7 +20,7 @@ type Activity struct {
 	EndTime      time.Time      `json:"end_time" gorm:"not null"`
 	LocationName string         `json:"location_name"`
 	Status       string         `json:"status" gorm:"default:'Scheduled'"`
-	CreatedBy    uint           `json:"created_by" gorm:"not null"`
+	CreatedBy    string         `json:"created_by" gorm:"not null;size:26;index"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
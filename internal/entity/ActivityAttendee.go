@@ -10,8 +10,8 @@ import (
 // ActivityAttendee model - tabel pivot untuk attendees aktivitas (many-to-many)
 type ActivityAttendee struct {
 	ID         string    `json:"id" gorm:"type:char(36);primary_key"`
-	ActivityID uint      `json:"activity_id" gorm:"primaryKey;not null"`
-	UserID     uint      `json:"user_id" gorm:"primaryKey;not null"`
+	ActivityID string    `json:"activity_id" gorm:"primaryKey;not null;size:26"`
+	UserID     string    `json:"user_id" gorm:"primaryKey;not null;size:26"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
@@ -9,8 +9,8 @@ import (
 // ActivityCheckin model - tabel untuk check-in aktivitas
 type ActivityCheckin struct {
 	ID		string         		`json:"id" gorm:"primaryKey;size:26"`
-	ActivityID  uint           `json:"activity_id" gorm:"not null"`
-	UserID      uint           `json:"user_id" gorm:"not null"`
+	ActivityID  string         `json:"activity_id" gorm:"not null;size:26"`
+	UserID      string         `json:"user_id" gorm:"not null;size:26"`
 	CheckedInAt time.Time      `json:"checked_in_at" gorm:"not null"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
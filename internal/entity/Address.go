@@ -20,6 +20,14 @@ type Address struct {
 	State      string         `json:"state"`
 	Country    string         `json:"country"`
 	PostalCode string         `json:"postal_code"`
+	// Kode wilayah Kemendagri (lihat package region)
+	ProvinceCode string       `json:"province_code" gorm:"size:13;index"`
+	RegencyCode  string       `json:"regency_code" gorm:"size:13;index"`
+	DistrictCode string       `json:"district_code" gorm:"size:13"`
+	VillageCode  string       `json:"village_code" gorm:"size:13"`
+	// Koordinat (WGS84), diisi manual atau lewat import
+	Latitude   *float64       `json:"latitude" gorm:"index:idx_address_coordinates"`
+	Longitude  *float64       `json:"longitude" gorm:"index:idx_address_coordinates"`
 	Main       bool           `json:"main" gorm:"default:false"`
 	Active     bool           `json:"active" gorm:"default:true"`
 	CreatedAt  time.Time      `json:"created_at"`
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ContactDate menyimpan tanggal relasi custom per contact (work anniversary, first deal date, dll)
+type ContactDate struct {
+	ID        string         `json:"id" gorm:"primaryKey;size:26"`
+	ContactID string         `json:"contact_id" gorm:"size:26;not null;index"`
+	Type      string         `json:"type" gorm:"not null;index"`
+	Label     string         `json:"label"`
+	Date      time.Time      `json:"date" gorm:"type:date;not null"`
+	Recurring bool           `json:"recurring" gorm:"default:true"` // diingatkan setiap tahun
+	Notes     string         `json:"notes"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Contact Contact `json:"-" gorm:"foreignKey:ContactID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (c *ContactDate) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	c.ID = id.String()
+	return nil
+}
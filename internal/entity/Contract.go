@@ -20,6 +20,8 @@ type Contact struct {
 	Phone       string         `json:"phone"`
 	Mobile      string         `json:"mobile"`
 	Department  string         `json:"department"`
+	StructureID *string        `json:"structure_id" gorm:"size:26;index"`  // unit org tempat contact berada
+	ReportsToID *string        `json:"reports_to_id" gorm:"size:26;index"` // atasan langsung (contact lain di customer yang sama)
 	Main        bool           `json:"main" gorm:"default:false"`
 	Active      bool           `json:"active" gorm:"default:true"`
 	CreatedAt   time.Time      `json:"created_at"`
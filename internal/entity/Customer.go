@@ -16,6 +16,9 @@ type Customer struct {
 	BrandName        string         `json:"brand_name"`
 	Code             string         `json:"code" gorm:"unique"`
 	AccountManagerID *string        `json:"account_manager_id" gorm:"size:5"`
+	TeamID           *string        `json:"team_id" gorm:"size:26;index"`
+	TeamSource       string         `json:"team_source" gorm:"size:10"`     // manual atau rule (auto-assign territory)
+	ParentID         *string        `json:"parent_id" gorm:"size:26;index"` // induk perusahaan (holding/konglomerasi)
 	Email            string         `json:"email"`
 	Phone            string         `json:"phone"`
 	Website          string         `json:"website"`
@@ -25,6 +28,8 @@ type Customer struct {
 	Category         string         `json:"category"`
 	Rating           float64        `json:"rating" gorm:"default:0"`
 	AverageCost      float64        `json:"average_cost" gorm:"default:0"`
+	HealthScore      *float64       `json:"health_score" gorm:"index"` // skor terakhir 0-100, riwayat di CustomerHealthScore
+	HealthScoredAt   *time.Time     `json:"health_scored_at"`
 	LogoSmall        string         `json:"logo_small"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
@@ -32,6 +37,8 @@ type Customer struct {
 
 	// Relations
 	AccountManager *AccountManager `json:"account_manager,omitempty" gorm:"foreignKey:AccountManagerID;references:ID"`
+	Team           *Teams          `json:"team,omitempty" gorm:"foreignKey:TeamID;references:ID"`
+	Parent         *Customer       `json:"parent,omitempty" gorm:"foreignKey:ParentID;references:ID"`
 	Addresses      []Address       `json:"addresses,omitempty" gorm:"foreignKey:CustomerID"`
 	Sosmeds        []Sosmed        `json:"sosmeds,omitempty" gorm:"foreignKey:CustomerID"`
 	Contacts       []Contact       `json:"contacts,omitempty" gorm:"foreignKey:CustomerID"`
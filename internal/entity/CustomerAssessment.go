@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// CustomerAssessment adalah hasil penilaian (Assessment) terhadap satu customer, skor 0-100
+type CustomerAssessment struct {
+	ID           string         `json:"id" gorm:"primaryKey;size:26"`
+	CustomerID   string         `json:"customer_id" gorm:"size:26;not null;index"`
+	AssessmentID string         `json:"assessment_id" gorm:"size:26;not null;index"`
+	Score        float64        `json:"score" gorm:"not null"`
+	Note         string         `json:"note"`
+	AssessedBy   string         `json:"assessed_by" gorm:"size:26"`
+	AssessedAt   time.Time      `json:"assessed_at" gorm:"not null"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Assessment *Assessment `json:"assessment,omitempty" gorm:"foreignKey:AssessmentID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (a *CustomerAssessment) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	a.ID = id.String()
+	return nil
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// HealthScoreFactor adalah kontribusi satu sinyal pada health score
+type HealthScoreFactor struct {
+	Signal       string  `json:"signal"`
+	Weight       float64 `json:"weight"`
+	Score        float64 `json:"score"`        // nilai sinyal 0-100
+	Contribution float64 `json:"contribution"` // poin yang disumbangkan ke skor akhir
+	HasData      bool    `json:"has_data"`     // false: sinyal tidak ikut dihitung
+	Detail       string  `json:"detail"`
+}
+
+// CustomerHealthScore adalah riwayat health score customer; baris baru dibuat setiap kali skor berubah
+type CustomerHealthScore struct {
+	ID         string              `json:"id" gorm:"primaryKey;size:26"`
+	CustomerID string              `json:"customer_id" gorm:"size:26;not null;index:idx_health_customer_created,priority:1"`
+	Score      float64             `json:"score"`
+	Breakdown  []HealthScoreFactor `json:"breakdown" gorm:"type:text;serializer:json"`
+	Trigger    string              `json:"trigger" gorm:"size:30"` // activity, invoice, status, nightly, manual, ...
+	CreatedAt  time.Time           `json:"created_at" gorm:"index:idx_health_customer_created,priority:2"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (s *CustomerHealthScore) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.ID = id.String()
+	return nil
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// CustomerOwnership mencatat perpindahan account manager sebuah customer
+type CustomerOwnership struct {
+	ID                   string    `json:"id" gorm:"primaryKey;size:26"`
+	CustomerID           string    `json:"customer_id" gorm:"size:26;not null;index"`
+	FromAccountManagerID *string   `json:"from_account_manager_id" gorm:"size:5;index"`
+	ToAccountManagerID   *string   `json:"to_account_manager_id" gorm:"size:5;index"`
+	ChangedBy            string    `json:"changed_by" gorm:"size:26"`
+	Reason               string    `json:"reason"`
+	BatchID              string    `json:"batch_id,omitempty" gorm:"size:26;index"` // diisi untuk reassign massal
+	CreatedAt            time.Time `json:"created_at"`
+
+	// Relations
+	FromAccountManager *AccountManager `json:"from_account_manager,omitempty" gorm:"foreignKey:FromAccountManagerID;references:ID"`
+	ToAccountManager   *AccountManager `json:"to_account_manager,omitempty" gorm:"foreignKey:ToAccountManagerID;references:ID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (s *CustomerOwnership) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.ID = id.String()
+	return nil
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// CustomerRelationship adalah hubungan bertipe antar customer, dibaca "From <Type> To",
+// mis. From referred-by To berarti From direferensikan oleh To
+type CustomerRelationship struct {
+	ID             string         `json:"id" gorm:"primaryKey;size:26"`
+	FromCustomerID string         `json:"from_customer_id" gorm:"size:26;not null;index"`
+	ToCustomerID   string         `json:"to_customer_id" gorm:"size:26;not null;index"`
+	Type           string         `json:"type" gorm:"size:20;not null;index"` // partner, reseller, competitor, supplier-of, referred-by
+	StartDate      *time.Time     `json:"start_date" gorm:"type:date"`
+	EndDate        *time.Time     `json:"end_date" gorm:"type:date"`
+	Note           string         `json:"note"`
+	CreatedBy      string         `json:"created_by" gorm:"size:26"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	FromCustomer *Customer `json:"from_customer,omitempty" gorm:"foreignKey:FromCustomerID"`
+	ToCustomer   *Customer `json:"to_customer,omitempty" gorm:"foreignKey:ToCustomerID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (r *CustomerRelationship) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	r.ID = id.String()
+	return nil
+}
@@ -13,7 +13,16 @@ type Document struct {
 	CustomerID string           `json:"customer_id" gorm:"not null"`
 	Notes       string         `json:"notes" gorm:"not null"`
 	Type       string         `json:"type" gorm:"not null"`
+	Category   string         `json:"category" gorm:"index"`
 	URLFile        string         `json:"url_file" gorm:"not null"`
+	FileName   string         `json:"file_name"`
+	MimeType   string         `json:"mime_type"`
+	Size       int64          `json:"size"`
+	// RootID menunjuk ke versi pertama dokumen, dipakai untuk mengelompokkan riwayat versi
+	RootID     string         `json:"root_id" gorm:"size:26;index"`
+	Version    int            `json:"version" gorm:"default:1"`
+	IsLatest   bool           `json:"is_latest" gorm:"default:true"`
+	ExpiresAt  *time.Time     `json:"expires_at" gorm:"index"`
 	UserID     string           `json:"user_id" gorm:"not null"`
 	IsActive   bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt  time.Time      `json:"created_at"`
@@ -31,5 +40,8 @@ func (c *Document) BeforeCreate(tx *gorm.DB) error {
 		return err
 	}
 	c.ID = id.String()
+	if c.RootID == "" {
+		c.RootID = c.ID
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -5,8 +5,8 @@ import (
 )
 // EventAttendee model - tabel pivot untuk attendees event (many-to-many)
 type EventAttendee struct {
-	EventID uint      `json:"event_id" gorm:"primaryKey;not null"`
-	UserID  uint      `json:"user_id" gorm:"primaryKey;not null"`
+	EventID string    `json:"event_id" gorm:"primaryKey;not null;size:26"`
+	UserID  string    `json:"user_id" gorm:"primaryKey;not null;size:26"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	// Relations
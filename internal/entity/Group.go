@@ -13,12 +13,16 @@ type Group struct {
 	ID		string         		`json:"id" gorm:"primaryKey;size:26"`
 	NameGroup string         `json:"name_group" gorm:"not null"`
 	Value     string         `json:"value"`
+	TypeID    *string        `json:"type_id" gorm:"size:26;index"`
+	ParentID  *string        `json:"parent_id" gorm:"size:26;index"` // group induk, membentuk pohon
 	Active    bool           `json:"active" gorm:"default:true"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relations
+	Type      *GroupType `json:"type,omitempty" gorm:"foreignKey:TypeID"`
+	Parent    *Group     `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
 	Customers []Customer `json:"customers,omitempty" gorm:"many2many:customer_groups;"`
 }
 
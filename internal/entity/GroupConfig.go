@@ -8,6 +8,8 @@ import (
 	"gorm.io/gorm"
 )
 
+// GroupConfig mengelompokkan customer berdasarkan Field: kolom customer (mis. "category", "status")
+// atau custom field dengan awalan "other:" (mis. "other:company_size"). Bucket-nya ada di GroupConfigDetail.
 type GroupConfig struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
 	Name      string         `json:"name" gorm:"not null;unique"`
@@ -17,6 +19,9 @@ type GroupConfig struct {
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
 	IsDeleted bool           `json:"-" gorm:"default:false"`
+
+	// Relations
+	Details []GroupConfigDetail `json:"details,omitempty" gorm:"foreignKey:GroupConfigID"`
 }
 
 // before save generate id
@@ -8,11 +8,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// GroupConfigDetail adalah satu bucket segmentasi: customer masuk jika nilai field-nya ada di Values,
+// atau (untuk field angka) berada di rentang [MinValue, MaxValue)
 type GroupConfigDetail struct {
 	ID            string         `json:"id" gorm:"primaryKey;size:26"`
-	GroupConfigID string         `json:"-" gorm:"not null"` // ULID string, hidden from JSON
-	Name          string         `json:"name" gorm:"not null;unique"`
+	GroupConfigID string         `json:"-" gorm:"not null;size:26;uniqueIndex:idx_group_config_detail_name"` // ULID string, hidden from JSON
+	Name          string         `json:"name" gorm:"not null;uniqueIndex:idx_group_config_detail_name"`
 	Icon          string         `json:"icon" gorm:"default:'default.icon';not null"`
+	Values        []string       `json:"values" gorm:"type:text;serializer:json"`
+	MinValue      *float64       `json:"min_value"`
+	MaxValue      *float64       `json:"max_value"`
+	SortOrder     int            `json:"sort_order" gorm:"default:0"`
 	IsActive      bool           `json:"-" gorm:"default:true"` // Hidden from JSON
 	CreatedAt     time.Time      `json:"-" gorm:""`             // Hidden from JSON
 	UpdatedAt     time.Time      `json:"-" gorm:""`             // Hidden from JSON
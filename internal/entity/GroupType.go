@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// GroupType adalah jenis group (industry, business_group, holding, region, ...).
+// Jika Exclusive, satu customer hanya boleh masuk ke satu group dengan jenis ini.
+type GroupType struct {
+	ID        string         `json:"id" gorm:"primaryKey;size:26"`
+	Code      string         `json:"code" gorm:"size:50;not null;uniqueIndex"`
+	Name      string         `json:"name" gorm:"not null"`
+	Exclusive bool           `json:"exclusive"`
+	Active    bool           `json:"active" gorm:"default:true"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (g *GroupType) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	g.ID = id.String()
+	return nil
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// HealthSignal adalah satu sinyal pembentuk health score customer beserta bobotnya.
+// Arti Threshold dan WindowDays tergantung sinyal (lihat handler/health_score.go).
+type HealthSignal struct {
+	ID         string         `json:"id" gorm:"primaryKey;size:26"`
+	Code       string         `json:"code" gorm:"size:50;not null;uniqueIndex"`
+	Name       string         `json:"name" gorm:"not null"`
+	Weight     float64        `json:"weight" gorm:"not null;default:0"`
+	Threshold  float64        `json:"threshold" gorm:"default:0"`
+	WindowDays int            `json:"window_days" gorm:"default:0"`
+	Active     bool           `json:"active" gorm:"default:true"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (s *HealthSignal) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.ID = id.String()
+	return nil
+}
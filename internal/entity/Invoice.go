@@ -10,8 +10,8 @@ import (
 // Invoice model - tabel untuk invoice
 type Invoice struct {
 	ID            string         `json:"id" gorm:"type:char(26);primary_key"`
-	CustomerID    uint           `json:"customer_id" gorm:"not null"`
-	ProjectID     string         `json:"project_id"`
+	CustomerID    string         `json:"customer_id" gorm:"size:26;not null;index"`
+	ProjectID     string         `json:"project_id" gorm:"index"`
 	InvoiceNumber string         `json:"invoice_number" gorm:"unique;not null"`
 	Amount        float64        `json:"amount" gorm:"not null"`
 	IssuedDate    time.Time      `json:"issued_date" gorm:"not null"`
@@ -24,6 +24,7 @@ type Invoice struct {
 	// Relations
 	Customer Customer  `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
 	Payments []Payment `json:"payments,omitempty" gorm:"foreignKey:InvoiceID"` // Tambahkan ini
+	Lines    []InvoiceLine `json:"lines,omitempty" gorm:"foreignKey:InvoiceID"`
 }
 
 
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// InvoiceLine adalah rincian tagihan di sebuah invoice, misalnya jam kerja billable
+type InvoiceLine struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:26"`
+	InvoiceID   string    `json:"invoice_id" gorm:"size:26;not null;index"`
+	ProjectID   *string   `json:"project_id" gorm:"size:26;index"`
+	UserID      *string   `json:"user_id" gorm:"size:26"` // konsultan untuk baris jam kerja
+	Description string    `json:"description" gorm:"not null"`
+	Quantity    float64   `json:"quantity"` // jumlah jam
+	UnitPrice   float64   `json:"unit_price"`
+	Amount      float64   `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (l *InvoiceLine) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	l.ID = id.String()
+	return nil
+}
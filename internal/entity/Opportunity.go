@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// Opportunity adalah peluang penjualan ke customer yang bergerak melewati Stages
+type Opportunity struct {
+	ID                string         `json:"id" gorm:"primaryKey;size:26"`
+	Name              string         `json:"name" gorm:"not null"`
+	CustomerID        string         `json:"customer_id" gorm:"size:26;not null;index"`
+	ProjectID         *string        `json:"project_id" gorm:"size:26;index"`
+	StageID           string         `json:"stage_id" gorm:"size:26;not null;index"`
+	OwnerID           string         `json:"owner_id" gorm:"size:26;not null;index"` // user penanggung jawab
+	Value             float64        `json:"value" gorm:"default:0"`
+	Probability       float64        `json:"probability" gorm:"default:0"` // peluang menang (%)
+	ExpectedCloseDate *time.Time     `json:"expected_close_date" gorm:"type:date;index"`
+	Status            string         `json:"status" gorm:"size:10;not null;default:'open';index"` // open, won, lost
+	CloseReason       string         `json:"close_reason"`
+	ClosedAt          *time.Time     `json:"closed_at"`
+	StageChangedAt    time.Time      `json:"stage_changed_at"`
+	Description       string         `json:"description"`
+	CreatedBy         string         `json:"created_by" gorm:"size:26"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Customer *Customer `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
+	Project  *Project  `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Stage    *Stages   `json:"stage,omitempty" gorm:"foreignKey:StageID"`
+	Owner    *User     `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (o *Opportunity) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	o.ID = id.String()
+	return nil
+}
+
+// OpportunityStageHistory mencatat setiap perpindahan stage dan penutupan (won/lost) opportunity
+type OpportunityStageHistory struct {
+	ID            string    `json:"id" gorm:"primaryKey;size:26"`
+	OpportunityID string    `json:"opportunity_id" gorm:"size:26;not null;index"`
+	FromStageID   *string   `json:"from_stage_id" gorm:"size:26"`
+	ToStageID     string    `json:"to_stage_id" gorm:"size:26;not null"`
+	Status        string    `json:"status" gorm:"size:10;not null"` // status opportunity setelah perubahan
+	Probability   float64   `json:"probability"`
+	Reason        string    `json:"reason"`
+	HoursInStage  float64   `json:"hours_in_stage"` // lama di stage sebelumnya
+	ChangedBy     string    `json:"changed_by" gorm:"size:26"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relations
+	FromStage *Stages `json:"from_stage,omitempty" gorm:"foreignKey:FromStageID"`
+	ToStage   *Stages `json:"to_stage,omitempty" gorm:"foreignKey:ToStageID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (h *OpportunityStageHistory) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	h.ID = id.String()
+	return nil
+}
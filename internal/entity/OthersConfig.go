@@ -8,13 +8,22 @@ import (
 )
 
 
+// OthersConfig mendefinisikan custom field customer; Other.Key mengacu ke Key
 type OthersConfig struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	Name       string         `json:"name" gorm:"not null;unique"`
+	Key       string         `json:"key" gorm:"not null;size:100;uniqueIndex"`
+	Label     string         `json:"label" gorm:"not null"`
+	DataType  string         `json:"data_type" gorm:"not null;size:20;default:'text'"` // text, number, date, enum, boolean, url
+	Required  bool           `json:"required" gorm:"default:false"`
+	Icon      string         `json:"icon"`
+	SortOrder int            `json:"sort_order" gorm:"default:0"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
+
+	// relationships
+	Options []OthersConfigDetail `json:"options,omitempty" gorm:"foreignKey:ConfigID"` // pilihan untuk tipe enum
 }
 
 
@@ -8,17 +8,21 @@ import (
 )
 
 
+// OthersConfigDetail adalah satu pilihan untuk custom field bertipe enum
 type OthersConfigDetail struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	ConfigID  string         `json:"config_id" gorm:"index"`
+	ConfigID  string         `json:"config_id" gorm:"index;size:26;not null"`
+	Value     string         `json:"value" gorm:"not null"` // nilai yang disimpan di Other.Value
+	Label     string         `json:"label"`
 	Icon 	  string         `json:"icon"`
-	IsActive  bool           `json:"is_active"`
+	SortOrder int            `json:"sort_order" gorm:"default:0"`
+	IsActive  bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 
 	// relationships
-	Config OthersConfig `json:"config" gorm:"foreignKey:ConfigID;references:ID"`
+	Config *OthersConfig `json:"config,omitempty" gorm:"foreignKey:ConfigID;references:ID"`
 }
 
 
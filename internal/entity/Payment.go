@@ -10,7 +10,7 @@ import (
 // Payment model - tabel untuk pembayaran invoice
 type Payment struct {
 	ID        string         `json:"id" gorm:"type:char(26);primary_key"`
-	InvoiceID uint           `json:"invoice_id" gorm:"not null"`
+	InvoiceID string         `json:"invoice_id" gorm:"type:char(26);not null;index"`
 	Amount    float64        `json:"amount" gorm:"not null"`
 	PaidAt    time.Time      `json:"paid_at" gorm:"not null"`
 	CreatedAt time.Time      `json:"created_at"`
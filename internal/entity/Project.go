@@ -7,14 +7,28 @@ import (
 	"gorm.io/gorm"
 )
 
+// Project model - project milik customer, dengan anggota, budget dan milestone
 type Project struct {
 	ID            string         `json:"id" gorm:"type:char(26);primary_key"`
 	Name        string         `json:"name" gorm:"not null"`
 	Description string         `json:"description"`
+	CustomerID  string         `json:"customer_id" gorm:"size:26;index"`
+	OwnerID     *string        `json:"owner_id" gorm:"size:26;index"` // user penanggung jawab project
+	Budget      float64        `json:"budget" gorm:"default:0"`
+	HourlyRate  float64        `json:"hourly_rate" gorm:"default:0"` // tarif default jam kerja billable
+	StartDate   *time.Time     `json:"start_date" gorm:"type:date"`
+	EndDate     *time.Time     `json:"end_date" gorm:"type:date"`
+	Status      string         `json:"status" gorm:"size:20;default:'planned';index"` // planned, active, on_hold, completed, cancelled
 	IsActive      bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Customer   *Customer          `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
+	Owner      *User              `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
+	Members    []ProjectMember    `json:"members,omitempty" gorm:"foreignKey:ProjectID"`
+	Milestones []ProjectMilestone `json:"milestones,omitempty" gorm:"foreignKey:ProjectID"`
 }
 
 func (s *Project) BeforeCreate(tx *gorm.DB) (err error) {
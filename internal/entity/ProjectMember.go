@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ProjectMember adalah user yang terlibat di project beserta perannya
+type ProjectMember struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:26"`
+	ProjectID string    `json:"project_id" gorm:"size:26;not null;uniqueIndex:idx_project_member_user"`
+	UserID    string    `json:"user_id" gorm:"size:26;not null;uniqueIndex:idx_project_member_user;index"`
+	Role      string    `json:"role" gorm:"size:20;not null;default:'member'"` // manager, member, viewer
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (m *ProjectMember) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	m.ID = id.String()
+	return nil
+}
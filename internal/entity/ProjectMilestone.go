@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ProjectMilestone adalah target/tahapan project dengan tanggal jatuh tempo
+type ProjectMilestone struct {
+	ID          string         `json:"id" gorm:"primaryKey;size:26"`
+	ProjectID   string         `json:"project_id" gorm:"size:26;not null;index"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description"`
+	DueDate     *time.Time     `json:"due_date" gorm:"type:date"`
+	Amount      float64        `json:"amount" gorm:"default:0"`                          // nilai tagihan milestone (opsional)
+	Status      string         `json:"status" gorm:"size:20;not null;default:'pending'"` // pending, completed
+	CompletedAt *time.Time     `json:"completed_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (m *ProjectMilestone) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	m.ID = id.String()
+	return nil
+}
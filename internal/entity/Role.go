@@ -12,6 +12,7 @@ import (
 type Role struct {
 	ID        string         `json:"id" gorm:"type:char(36);primary_key"`
 	RoleName  string         `json:"role_name" gorm:"unique;not null"`
+	IsAdmin   bool           `json:"is_admin" gorm:"not null;default:false"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
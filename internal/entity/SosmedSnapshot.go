@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// SosmedSnapshot menyimpan jumlah followers akun sosmed pada waktu tertentu
+type SosmedSnapshot struct {
+	ID         string    `json:"id" gorm:"primaryKey;size:26"`
+	SosmedID   string    `json:"sosmed_id" gorm:"size:26;not null;uniqueIndex:idx_sosmed_snapshot_time"`
+	Followers  int       `json:"followers" gorm:"not null"`
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null;uniqueIndex:idx_sosmed_snapshot_time;index"`
+	Source     string    `json:"source" gorm:"default:'manual'"` // manual, csv, update, create
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relations
+	Sosmed Sosmed `json:"-" gorm:"foreignKey:SosmedID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (s *SosmedSnapshot) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.ID = id.String()
+	return nil
+}
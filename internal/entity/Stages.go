@@ -11,6 +11,8 @@ import (
 type Stages struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
 	Name       string         `json:"name" gorm:"not null;unique"`
+	Sequence    int            `json:"sequence" gorm:"default:0"`       // urutan kolom di pipeline/kanban
+	Probability float64        `json:"probability" gorm:"default:0"` // default peluang menang (%) opportunity di stage ini
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
@@ -10,7 +10,7 @@ import (
 // StatusReasons model - tabel untuk Reason Status customer
 type StatusReasons struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	CustomerID string           `json:"customer_id" gorm:"not null"`
+	CustomerID string           `json:"customer_id" gorm:"not null;index"`
 	Reason string         	  `json:"reason" gorm:"not null"`
 	Status     string         `json:"status" gorm:"type:varchar(20);check:status IN ('active','blocked');not null"`
 	CreatedAt  time.Time      `json:"created_at"`
@@ -14,7 +14,7 @@ import (
 type Teams struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
 	Name      string         `json:"name" gorm:"not null;unique"`
-	TeamLead  string         `json:"team_lead" gorm:"not null"`
+	TeamLead  string         `json:"team_lead" gorm:"not null;size:26"` // user ID ketua tim
 	Industry  string         `json:"industry" gorm:"not null"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -22,8 +22,10 @@ type Teams struct {
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
 
 	// relationships
-	TeamLeadUser User       `json:"team_lead_user" gorm:"foreignKey:TeamLead;references:ID"`
-	Customers    []Customer `json:"customers" gorm:"foreignKey:TeamID"`
+	TeamLeadUser *User         `json:"team_lead_user,omitempty" gorm:"foreignKey:TeamLead;references:ID"`
+	Members      []TeamsDetail `json:"members,omitempty" gorm:"foreignKey:TeamsID"`
+	Territories  []Territory   `json:"territories,omitempty" gorm:"foreignKey:TeamID"`
+	Customers    []Customer    `json:"customers,omitempty" gorm:"foreignKey:TeamID"`
 }
 
 
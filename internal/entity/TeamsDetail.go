@@ -10,10 +10,11 @@ import (
 
 type TeamsDetail struct {
 	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	TeamsID string         `json:"teams_id" gorm:"not null"` // ULID string
-	JobPosition	   string         `json:"job_position" gorm:"not null;unique"`
+	TeamsID string         `json:"teams_id" gorm:"not null;size:26;index;uniqueIndex:idx_team_member_user"` // ULID string
+	UserID  *string        `json:"user_id" gorm:"size:26;uniqueIndex:idx_team_member_user"` // akun login anggota tim
+	JobPosition	   string         `json:"job_position" gorm:"not null"`
 	EmployeeName string         `json:"employee_name" gorm:"not null"`
-	PhoneNumber string         `json:"phone_number" gorm:"not null;unique"`
+	PhoneNumber string         `json:"phone_number"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
@@ -21,6 +22,7 @@ type TeamsDetail struct {
 
 	// Relations - hilangkan dari JSON response
 	Teams Teams `json:"-" gorm:"foreignKey:TeamsID"`
+	User  *User `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID"`
 
 }
 
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// Territory adalah aturan wilayah kerja tim: customer yang alamatnya berada di RegionCode
+// dan/atau termasuk grup industri GroupID otomatis di-assign ke TeamID
+type Territory struct {
+	ID         string         `json:"id" gorm:"primaryKey;size:26"`
+	TeamID     string         `json:"team_id" gorm:"size:26;not null;index"`
+	Name       string         `json:"name" gorm:"not null"`
+	RegionCode string         `json:"region_code" gorm:"size:13;index"` // kode provinsi/kabupaten/kecamatan (lihat package region)
+	GroupID    *string        `json:"group_id" gorm:"size:26;index"`    // grup industri
+	Priority   int            `json:"priority" gorm:"default:100"`      // makin kecil makin diutamakan
+	Active     bool           `json:"active" gorm:"default:true"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Team  *Teams `json:"team,omitempty" gorm:"foreignKey:TeamID"`
+	Group *Group `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (s *Territory) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.ID = id.String()
+	return nil
+}
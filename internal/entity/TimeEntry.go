@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// TimeEntry adalah jam kerja yang dicatat user pada sebuah project
+type TimeEntry struct {
+	ID            string         `json:"id" gorm:"primaryKey;size:26"`
+	UserID        string         `json:"user_id" gorm:"size:26;not null;index:idx_time_entry_user_date"`
+	ProjectID     string         `json:"project_id" gorm:"size:26;not null;index"`
+	ActivityID    *string        `json:"activity_id" gorm:"size:26;index"` // aktivitas terkait (opsional)
+	EventID       *string        `json:"event_id" gorm:"size:26;index"`    // event terkait (opsional)
+	Date          time.Time      `json:"date" gorm:"type:date;not null;index:idx_time_entry_user_date"`
+	Hours         float64        `json:"hours" gorm:"not null"`
+	Billable      bool           `json:"billable" gorm:"default:true"`
+	Note          string         `json:"note"`
+	Status        string         `json:"status" gorm:"size:10;not null;default:'draft';index"` // draft, submitted, approved, rejected
+	TimesheetID   *string        `json:"timesheet_id" gorm:"size:26;index"`                    // pengajuan mingguan
+	InvoiceLineID *string        `json:"invoice_line_id" gorm:"size:26;index"`                 // terisi setelah ditagihkan
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	User    *User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Project *Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (t *TimeEntry) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	t.ID = id.String()
+	return nil
+}
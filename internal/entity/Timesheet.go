@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// Timesheet adalah pengajuan jam kerja mingguan seorang user pada satu project,
+// disetujui atau ditolak oleh owner project
+type Timesheet struct {
+	ID            string     `json:"id" gorm:"primaryKey;size:26"`
+	UserID        string     `json:"user_id" gorm:"size:26;not null;uniqueIndex:idx_timesheet_week"`
+	ProjectID     string     `json:"project_id" gorm:"size:26;not null;uniqueIndex:idx_timesheet_week;index"`
+	WeekStart     time.Time  `json:"week_start" gorm:"type:date;not null;uniqueIndex:idx_timesheet_week"` // hari Senin
+	TotalHours    float64    `json:"total_hours"`
+	BillableHours float64    `json:"billable_hours"`
+	Status        string     `json:"status" gorm:"size:10;not null;default:'submitted';index"` // submitted, approved, rejected
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	ReviewedBy    *string    `json:"reviewed_by" gorm:"size:26"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	ReviewNote    string     `json:"review_note"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	User    *User       `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Project *Project    `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Entries []TimeEntry `json:"entries,omitempty" gorm:"foreignKey:TimesheetID"`
+}
+
+// BeforeCreate hook - generate ID before create
+func (t *Timesheet) BeforeCreate(tx *gorm.DB) error {
+	id, err := ulid.New(ulid.Timestamp(time.Now()), rand.Reader)
+	if err != nil {
+		return err
+	}
+	t.ID = id.String()
+	return nil
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// requireAdmin mengirim 403 jika user yang login bukan admin
+func requireAdmin(c *gin.Context) bool {
+	policy, err := access.FromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve access policy"})
+		return false
+	}
+	if !policy.IsAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can perform this action"})
+		return false
+	}
+	return true
+}
+
+// accessScope mengembalikan hak akses user yang login, mengirim 500 jika gagal
+func accessScope(c *gin.Context) (*access.Context, bool) {
+	policy, err := access.FromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve access policy"})
+		return nil, false
+	}
+	return policy, true
+}
+
+// canWriteCustomer memeriksa apakah user boleh mengubah data customer, dengan cache per
+// import agar customer yang sama tidak dimuat berulang kali
+func canWriteCustomer(db *gorm.DB, policy *access.Context, cache map[string]bool, customerID string) bool {
+	if allowed, ok := cache[customerID]; ok {
+		return allowed
+	}
+	var customer entity.Customer
+	allowed := db.Select("id, account_manager_id, team_id").Where("id = ?", customerID).First(&customer).Error == nil &&
+		policy.CanWrite(customer)
+	cache[customerID] = allowed
+	return allowed
+}
+
+// @Summary Get access policies
+// @Description Get the customer visibility policy of every role. Roles without a policy see all customers.
+// @Tags Access
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.AccessPolicy
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/access-policies [get]
+func GetAccessPolicies(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	policies := []entity.AccessPolicy{}
+	if err := config.DB.Preload("Role").Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch access policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Access policies fetched successfully",
+		"data":    policies,
+	})
+}
+
+// @Summary Set access policy
+// @Description Create or replace the customer visibility policy of a role. scope is all, team (team customers and own portfolio) or portfolio; read_only blocks writes.
+// @Tags Access
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role_id path string true "Role ID"
+// @Param policy body dto.AccessPolicyRequest true "Policy"
+// @Success 200 {object} entity.AccessPolicy
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/access-policies/{role_id} [put]
+func SetAccessPolicy(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var role entity.Role
+	if err := config.DB.Where("id = ?", c.Param("role_id")).First(&role).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		return
+	}
+	if role.IsAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Admin role always sees all customers"})
+		return
+	}
+
+	var req dto.AccessPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch req.Scope {
+	case access.ScopeAll, access.ScopeTeam, access.ScopePortfolio:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope must be one of all, team, portfolio"})
+		return
+	}
+
+	var policy entity.AccessPolicy
+	if err := config.DB.Where("role_id = ?", role.ID).First(&policy).Error; err != nil {
+		policy = entity.AccessPolicy{RoleID: role.ID}
+	}
+	policy.Scope = req.Scope
+	policy.ReadOnly = req.ReadOnly
+
+	if err := config.DB.Omit("Role").Save(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save access policy"})
+		return
+	}
+	policy.Role = &role
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Access policy saved successfully",
+		"data":    policy,
+	})
+}
+
+// @Summary Delete access policy
+// @Description Remove the policy of a role so that it sees all customers again
+// @Tags Access
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role_id path string true "Role ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/access-policies/{role_id} [delete]
+func DeleteAccessPolicy(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var policy entity.AccessPolicy
+	if err := config.DB.Where("role_id = ?", c.Param("role_id")).First(&policy).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access policy not found"})
+		return
+	}
+	if err := config.DB.Delete(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete access policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Access policy deleted successfully"})
+}
+
+// @Summary Explain record visibility
+// @Description Explain whether a customer or one of its child records is visible to a user, and why. Admins can check any user; other users only themselves.
+// @Tags Access
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "User to check (default: logged-in user)"
+// @Param resource query string false "customer, address, contact, contact-date, structure, other, activity, event, document, sosmed, sosmed-snapshot, relationship, opportunity, project, time-entry or timesheet (default: customer)"
+// @Param id query string true "Record ID"
+// @Success 200 {object} dto.AccessExplanation
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/access/explain [get]
+func ExplainAccess(c *gin.Context) {
+	current, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	userID := c.DefaultQuery("user_id", current.UserID)
+	if userID != current.UserID && !current.IsAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can explain access for other users"})
+		return
+	}
+	resource := c.DefaultQuery("resource", "customer")
+	id := c.Query("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+	if !access.KnownResource(resource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown resource"})
+		return
+	}
+
+	var user entity.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	target, err := access.Resolve(config.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve access policy"})
+		return
+	}
+
+	customerID, found := access.CustomerOf(config.DB, resource, id)
+	var customer entity.Customer
+	if found {
+		found = config.DB.Where("id = ?", customerID).First(&customer).Error == nil
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	visible, reason := target.CanSee(customer)
+	writable := visible && (target.IsAdmin || !target.ReadOnly)
+	if visible && !writable {
+		reason += "; policy bersifat read-only"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Access explained successfully",
+		"data": dto.AccessExplanation{
+			UserID:           target.UserID,
+			Username:         target.Username,
+			RoleName:         target.RoleName,
+			Scope:            target.Scope,
+			ReadOnly:         target.ReadOnly && !target.IsAdmin,
+			TeamIDs:          target.TeamIDs,
+			AccountManagerID: target.AccountManagerID,
+			Resource:         resource,
+			ResourceID:       id,
+			CustomerID:       customer.ID,
+			CustomerName:     customer.Name,
+			CustomerTeamID:   customer.TeamID,
+			CustomerAMID:     customer.AccountManagerID,
+			Visible:          visible,
+			Writable:         writable,
+			Reason:           reason,
+		},
+	})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"customer-api/internal/config"
 	"customer-api/internal/dto"
@@ -10,6 +11,43 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// toAccountManagerResponse mengubah entity account manager ke format response
+func toAccountManagerResponse(am entity.AccountManager) dto.AccountManagerResponse {
+	response := dto.AccountManagerResponse{
+		ID:          am.ID,
+		ManagerName: am.ManagerName,
+		UserID:      am.UserID,
+		Email:       am.Email,
+		Phone:       am.Phone,
+		IsActive:    am.IsActive,
+		CreatedAt:   am.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   am.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if am.User != nil {
+		response.Username = am.User.Username
+	}
+	return response
+}
+
+// linkAccountManagerUser memvalidasi user yang akan ditautkan: user harus ada dan belum
+// ditautkan ke account manager lain. Mengembalikan status HTTP dan pesan jika gagal.
+func linkAccountManagerUser(userID, accountManagerID string) (*entity.User, int, string) {
+	var user entity.User
+	if err := config.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, http.StatusBadRequest, "User tidak ditemukan"
+	}
+
+	var linked entity.AccountManager
+	query := config.DB.Where("user_id = ?", userID)
+	if accountManagerID != "" {
+		query = query.Where("id != ?", accountManagerID)
+	}
+	if err := query.First(&linked).Error; err == nil {
+		return nil, http.StatusConflict, "User sudah ditautkan ke account manager " + linked.ManagerName
+	}
+	return &user, 0, ""
+}
+
 // CreateAccountManager creates a new account manager
 func CreateAccountManager(c *gin.Context) {
 	var input dto.CreateAccountManagerRequest
@@ -27,31 +65,51 @@ func CreateAccountManager(c *gin.Context) {
 
 	accountManager := entity.AccountManager{
 		ManagerName: input.ManagerName,
+		Email:       strings.TrimSpace(input.Email),
+		Phone:       strings.TrimSpace(input.Phone),
+		IsActive:    true,
+	}
+	if input.IsActive != nil {
+		accountManager.IsActive = *input.IsActive
 	}
 
-	if result := config.DB.Create(&accountManager); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat account manager"})
+	// Tautkan ke akun login; email default diambil dari user
+	if userID := strings.TrimSpace(input.UserID); userID != "" {
+		user, status, message := linkAccountManagerUser(userID, "")
+		if user == nil {
+			c.JSON(status, gin.H{"error": message})
+			return
+		}
+		accountManager.UserID = &user.ID
+		accountManager.User = user
+		if accountManager.Email == "" {
+			accountManager.Email = user.Email
+		}
+	}
+	if accountManager.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email wajib diisi jika user_id kosong"})
 		return
 	}
 
-	// Convert to simplified response DTO (excluding unnecessary fields)
-	response := dto.AccountManagerListResponse{
-		ID:          accountManager.ID,
-		ManagerName: accountManager.ManagerName,
-		CreatedAt:   accountManager.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   accountManager.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	if result := config.DB.Omit("User").Select("*").Create(&accountManager); result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat account manager"})
+		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Account manager berhasil dibuat",
-		"data":    response,
+		"data":    toAccountManagerResponse(accountManager),
 	})
 }
 
 // GetAccountManagers gets all account managers
 func GetAccountManagers(c *gin.Context) {
 	var accountManagers []entity.AccountManager
-	if result := config.DB.Find(&accountManagers); result.Error != nil {
+	db := config.DB
+	if active := c.Query("active"); active != "" {
+		db = db.Where("is_active = ?", active == "true")
+	}
+	if result := db.Find(&accountManagers); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data account managers"})
 		return
 	}
@@ -62,6 +120,10 @@ func GetAccountManagers(c *gin.Context) {
 		responses = append(responses, dto.AccountManagerListResponse{
 			ID:          am.ID,
 			ManagerName: am.ManagerName,
+			UserID:      am.UserID,
+			Email:       am.Email,
+			Phone:       am.Phone,
+			IsActive:    am.IsActive,
 			CreatedAt:   am.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:   am.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
@@ -75,21 +137,12 @@ func GetAccountManager(c *gin.Context) {
 	id := c.Param("id")
 	var accountManager entity.AccountManager
 
-	if result := config.DB.Where("id = ?", id).First(&accountManager); result.Error != nil {
+	if result := config.DB.Preload("User").Where("id = ?", id).First(&accountManager); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Account manager tidak ditemukan"})
 		return
 	}
 
-	// Convert to response DTO
-	response := dto.AccountManagerResponse{
-		ID:          accountManager.ID,
-		ManagerName: accountManager.ManagerName,
-
-		CreatedAt: accountManager.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: accountManager.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, toAccountManagerResponse(accountManager))
 }
 
 // UpdateAccountManager updates an existing account manager
@@ -117,24 +170,37 @@ func UpdateAccountManager(c *gin.Context) {
 		}
 		accountManager.ManagerName = *input.ManagerName
 	}
+	if input.UserID != nil {
+		if userID := strings.TrimSpace(*input.UserID); userID == "" {
+			accountManager.UserID = nil
+		} else {
+			user, status, message := linkAccountManagerUser(userID, accountManager.ID)
+			if user == nil {
+				c.JSON(status, gin.H{"error": message})
+				return
+			}
+			accountManager.UserID = &user.ID
+		}
+	}
+	if input.Email != nil {
+		accountManager.Email = strings.TrimSpace(*input.Email)
+	}
+	if input.Phone != nil {
+		accountManager.Phone = strings.TrimSpace(*input.Phone)
+	}
+	if input.IsActive != nil {
+		accountManager.IsActive = *input.IsActive
+	}
 
 	if result := config.DB.Save(&accountManager); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate account manager"})
 		return
 	}
-
-	// Convert to response DTO
-	response := dto.AccountManagerResponse{
-		ID:          accountManager.ID,
-		ManagerName: accountManager.ManagerName,
-
-		CreatedAt: accountManager.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: accountManager.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
+	config.DB.Preload("User").Where("id = ?", accountManager.ID).First(&accountManager)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Account manager berhasil diupdate",
-		"data":    response,
+		"data":    toAccountManagerResponse(accountManager),
 	})
 }
 
@@ -152,7 +218,10 @@ func DeleteAccountManager(c *gin.Context) {
 	var customerCount int64
 	config.DB.Model(&entity.Customer{}).Where("account_manager_id = ?", accountManager.ID).Count(&customerCount)
 	if customerCount > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Account manager tidak dapat dihapus karena masih digunakan oleh customer"})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Account manager tidak dapat dihapus karena masih digunakan oleh customer",
+			"hint":  "Pindahkan customer terlebih dahulu lewat POST /api/account-managers/" + accountManager.ID + "/reassign",
+		})
 		return
 	}
 
@@ -165,7 +234,7 @@ func DeleteAccountManager(c *gin.Context) {
 }
 
 // @Summary Get account managers for dropdown
-// @Description Get simplified list of account managers for dropdown selection (ID and manager_name only)
+// @Description Get simplified list of active account managers for dropdown selection (ID and manager_name only)
 // @Tags AccountManager
 // @Accept json
 // @Produce json
@@ -176,7 +245,7 @@ func DeleteAccountManager(c *gin.Context) {
 // @Router /api/account-managers/dropdown [get]
 func GetAccountManagersDropdown(c *gin.Context) {
 	var accountManagers []entity.AccountManager
-	if result := config.DB.Select("id, manager_name").Find(&accountManagers); result.Error != nil {
+	if result := config.DB.Select("id, manager_name").Where("is_active = ?", true).Find(&accountManagers); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data account managers"})
 		return
 	}
@@ -5,7 +5,6 @@ import (
 	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +22,36 @@ func floatToFloatPtr(val float64) *float64 {
 	return &val
 }
 
+// toActivityResponse mengubah entity activity ke format response
+func toActivityResponse(activity entity.Activity) dto.ActivityResponse {
+	return dto.ActivityResponse{
+		ID:           activity.ID,
+		CustomerID:   activity.CustomerID,
+		AddressID:    activity.AddressID,
+		ProjectID:    activity.ProjectID,
+		Title:        activity.Title,
+		Type:         activity.Type,
+		Agenda:       activity.Agenda,
+		StartTime:    activity.StartTime.Format(time.RFC3339),
+		EndTime:      activity.EndTime.Format(time.RFC3339),
+		LocationName: activity.LocationName,
+		Status:       activity.Status,
+		CreatedBy:    activity.CreatedBy,
+		CreatedAt:    activity.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    activity.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// currentUserID mengambil user ID dari context yang di-set oleh auth middleware
+func currentUserID(c *gin.Context) (string, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return "", false
+	}
+	userID, ok := value.(string)
+	return userID, ok && userID != ""
+}
+
 // @Summary Get all activities
 // @Description Get list of all activities with pagination
 // @Tags Activities
@@ -39,7 +68,11 @@ func GetActivities(c *gin.Context) {
 	status := c.Query("status")
 	activityType := c.Query("type")
 
-	db := config.DB.Preload("Customer").Preload("Creator")
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	db := policy.Filter(config.DB.Preload("Customer").Preload("Creator"), "customer_id")
 
 	if customerID != "" {
 		db = db.Where("customer_id = ?", customerID)
@@ -60,25 +93,12 @@ func GetActivities(c *gin.Context) {
 	// Convert to response format
 	var activityResponses []dto.ActivityResponse
 	for _, activity := range activities {
-		activityResponses = append(activityResponses, dto.ActivityResponse{
-			ID:           activity.ID,
-			CustomerID:   activity.CustomerID,
-			Title:        activity.Title,
-			Type:         activity.Type,
-			Agenda:       activity.Agenda,
-			StartTime:    activity.StartTime.Format(time.RFC3339),
-			EndTime:      activity.EndTime.Format(time.RFC3339),
-			LocationName: activity.LocationName,
-			Status:       activity.Status,
-			CreatedBy:    activity.CreatedBy,
-			CreatedAt:    activity.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:    activity.UpdatedAt.Format(time.RFC3339),
-		})
+		activityResponses = append(activityResponses, toActivityResponse(activity))
 	}
 
 	// Get total count
 	var total int64
-	config.DB.Model(&entity.Activity{}).Count(&total)
+	policy.Filter(config.DB.Model(&entity.Activity{}), "customer_id").Count(&total)
 
 	response := dto.ActivitiesResponse{
 		Activities: activityResponses,
@@ -121,7 +141,7 @@ func CreateActivity(c *gin.Context) {
 	}
 
 	// Get user ID from context (set by auth middleware)
-	userID, exists := c.Get("user_id")
+	userID, exists := currentUserID(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -129,13 +149,34 @@ func CreateActivity(c *gin.Context) {
 
 	// Verify customer exists
 	var customer entity.Customer
-	if err := config.DB.First(&customer, req.CustomerID).Error; err != nil {
+	if err := config.DB.Where("id = ?", req.CustomerID).First(&customer).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Customer not found"})
 		return
 	}
 
+	var addressID *string
+	if req.AddressID != "" {
+		var address entity.Address
+		if err := config.DB.Where("id = ? AND customer_id = ?", req.AddressID, customer.ID).First(&address).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Address not found for this customer"})
+			return
+		}
+		addressID = &address.ID
+	}
+
+	var projectID *string
+	if req.ProjectID != "" {
+		if err := checkCustomerProject(config.DB, req.ProjectID, customer.ID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		projectID = &req.ProjectID
+	}
+
 	activity := entity.Activity{
-		CustomerID:   req.CustomerID,
+		CustomerID:   customer.ID,
+		AddressID:    addressID,
+		ProjectID:    projectID,
 		Title:        req.Title,
 		Type:         req.Type,
 		Agenda:       req.Agenda,
@@ -143,7 +184,7 @@ func CreateActivity(c *gin.Context) {
 		EndTime:      endTime,
 		LocationName: req.LocationName,
 		Status:       "planned",
-		CreatedBy:    userID.(uint),
+		CreatedBy:    userID,
 	}
 
 	result := config.DB.Create(&activity)
@@ -151,24 +192,12 @@ func CreateActivity(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activity"})
 		return
 	}
+	refreshHealthScore(activity.CustomerID, "activity")
 
 	// Load relations for response
-	config.DB.Preload("Customer").Preload("CreatedByUser").First(&activity, activity.ID)
+	config.DB.Preload("Customer").Preload("Creator").Where("id = ?", activity.ID).First(&activity)
 
-	activityResponse := dto.ActivityResponse{
-		ID:           activity.ID,
-		CustomerID:   activity.CustomerID,
-		Title:        activity.Title,
-		Type:         activity.Type,
-		Agenda:       activity.Agenda,
-		StartTime:    activity.StartTime.Format(time.RFC3339),
-		EndTime:      activity.EndTime.Format(time.RFC3339),
-		LocationName: activity.LocationName,
-		Status:       activity.Status,
-		CreatedBy:    activity.CreatedBy,
-		CreatedAt:    activity.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    activity.UpdatedAt.Format(time.RFC3339),
-	}
+	activityResponse := toActivityResponse(activity)
 
 	c.JSON(http.StatusCreated, activityResponse)
 }
@@ -189,26 +218,13 @@ func GetActivity(c *gin.Context) {
 	id := c.Param("id")
 
 	var activity entity.Activity
-	result := config.DB.Preload("Customer").Preload("CreatedByUser").Preload("Attendees").First(&activity, id)
+	result := config.DB.Preload("Customer").Preload("Creator").Preload("Attendees").Where("id = ?", id).First(&activity)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Activity not found"})
 		return
 	}
 
-	activityResponse := dto.ActivityResponse{
-		ID:           activity.ID,
-		CustomerID:   activity.CustomerID,
-		Title:        activity.Title,
-		Type:         activity.Type,
-		Agenda:       activity.Agenda,
-		StartTime:    activity.StartTime.Format(time.RFC3339),
-		EndTime:      activity.EndTime.Format(time.RFC3339),
-		LocationName: activity.LocationName,
-		Status:       activity.Status,
-		CreatedBy:    activity.CreatedBy,
-		CreatedAt:    activity.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    activity.UpdatedAt.Format(time.RFC3339),
-	}
+	activityResponse := toActivityResponse(activity)
 
 	c.JSON(http.StatusOK, activityResponse)
 }
@@ -234,7 +250,7 @@ func UpdateActivityByCustomer(c *gin.Context) {
 
 	// Verify customer exists
 	var customer entity.Customer
-	if err := config.DB.First(&customer, customerID).Error; err != nil {
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
 		return
 	}
@@ -254,6 +270,16 @@ func UpdateActivityByCustomer(c *gin.Context) {
 	}
 
 	// Update fields if provided
+	if req.ProjectID != nil {
+		if *req.ProjectID == "" {
+			activity.ProjectID = nil
+		} else if err := checkCustomerProject(config.DB, *req.ProjectID, activity.CustomerID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		} else {
+			activity.ProjectID = req.ProjectID
+		}
+	}
 	if req.Title != nil {
 		activity.Title = *req.Title
 	}
@@ -291,24 +317,12 @@ func UpdateActivityByCustomer(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update activity"})
 		return
 	}
+	refreshHealthScore(activity.CustomerID, "activity")
 
 	// Load relations for response
-	config.DB.Preload("Customer").Preload("Creator").First(&activity, activity.ID)
+	config.DB.Preload("Customer").Preload("Creator").Where("id = ?", activity.ID).First(&activity)
 
-	activityResponse := dto.ActivityResponse{
-		ID:           activity.ID,
-		CustomerID:   activity.CustomerID,
-		Title:        activity.Title,
-		Type:         activity.Type,
-		Agenda:       activity.Agenda,
-		StartTime:    activity.StartTime.Format(time.RFC3339),
-		EndTime:      activity.EndTime.Format(time.RFC3339),
-		LocationName: activity.LocationName,
-		Status:       activity.Status,
-		CreatedBy:    activity.CreatedBy,
-		CreatedAt:    activity.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    activity.UpdatedAt.Format(time.RFC3339),
-	}
+	activityResponse := toActivityResponse(activity)
 
 	c.JSON(http.StatusOK, activityResponse)
 }
@@ -330,7 +344,7 @@ func DeleteActivity(c *gin.Context) {
 
 	// Check if activity exists
 	var activity entity.Activity
-	if err := config.DB.First(&activity, id).Error; err != nil {
+	if err := config.DB.Where("id = ?", id).First(&activity).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Activity not found"})
 		return
 	}
@@ -340,6 +354,7 @@ func DeleteActivity(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete activity"})
 		return
 	}
+	refreshHealthScore(activity.CustomerID, "activity")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Activity deleted successfully"})
 }
@@ -359,16 +374,11 @@ func DeleteActivity(c *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/activities/{id}/attendees [post]
 func AddActivityAttendees(c *gin.Context) {
-	id := c.Param("id")
-	activityID, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
-		return
-	}
+	activityID := c.Param("id")
 
 	// Check if activity exists
 	var activity entity.Activity
-	if err := config.DB.First(&activity, activityID).Error; err != nil {
+	if err := config.DB.Where("id = ?", activityID).First(&activity).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Activity not found"})
 		return
 	}
@@ -394,12 +404,12 @@ func AddActivityAttendees(c *gin.Context) {
 	// Add attendees
 	for _, userID := range req.UserIDs {
 		attendee := entity.ActivityAttendee{
-			ActivityID: uint(activityID),
+			ActivityID: activityID,
 			UserID:     userID,
 		}
 		// Use FirstOrCreate to avoid duplicates
 		config.DB.FirstOrCreate(&attendee, entity.ActivityAttendee{
-			ActivityID: uint(activityID),
+			ActivityID: activityID,
 			UserID:     userID,
 		})
 	}
@@ -422,12 +432,7 @@ func AddActivityAttendees(c *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/activities/{id}/attendees [delete]
 func RemoveActivityAttendees(c *gin.Context) {
-	id := c.Param("id")
-	activityID, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
-		return
-	}
+	activityID := c.Param("id")
 
 	var req dto.ActivityAttendeeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -460,15 +465,10 @@ func RemoveActivityAttendees(c *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/activities/{id}/checkin [post]
 func CheckinActivity(c *gin.Context) {
-	id := c.Param("id")
-	activityID, err := strconv.ParseUint(id, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
-		return
-	}
+	activityID := c.Param("id")
 
 	// Get user ID from context
-	userID, exists := c.Get("user_id")
+	userID, exists := currentUserID(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -476,7 +476,7 @@ func CheckinActivity(c *gin.Context) {
 
 	// Check if activity exists
 	var activity entity.Activity
-	if err := config.DB.First(&activity, activityID).Error; err != nil {
+	if err := config.DB.Where("id = ?", activityID).First(&activity).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Activity not found"})
 		return
 	}
@@ -499,8 +499,8 @@ func CheckinActivity(c *gin.Context) {
 
 	// Create check-in record
 	checkin := entity.ActivityCheckin{
-		ActivityID:  uint(activityID),
-		UserID:      userID.(uint),
+		ActivityID:  activityID,
+		UserID:      userID,
 		CheckedInAt: time.Now(),
 	}
 
@@ -532,7 +532,7 @@ func UpdateActivity(c *gin.Context) {
 
 	// Find activity
 	var activity entity.Activity
-	result := config.DB.First(&activity, activityID)
+	result := config.DB.Where("id = ?", activityID).First(&activity)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Activity not found"})
 		return
@@ -545,6 +545,16 @@ func UpdateActivity(c *gin.Context) {
 	}
 
 	// Update fields if provided
+	if req.ProjectID != nil {
+		if *req.ProjectID == "" {
+			activity.ProjectID = nil
+		} else if err := checkCustomerProject(config.DB, *req.ProjectID, activity.CustomerID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		} else {
+			activity.ProjectID = req.ProjectID
+		}
+	}
 	if req.Title != nil {
 		activity.Title = *req.Title
 	}
@@ -582,24 +592,12 @@ func UpdateActivity(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update activity"})
 		return
 	}
+	refreshHealthScore(activity.CustomerID, "activity")
 
 	// Load relations for response
-	config.DB.Preload("Customer").Preload("Creator").First(&activity, activity.ID)
+	config.DB.Preload("Customer").Preload("Creator").Where("id = ?", activity.ID).First(&activity)
 
-	activityResponse := dto.ActivityResponse{
-		ID:           activity.ID,
-		CustomerID:   activity.CustomerID,
-		Title:        activity.Title,
-		Type:         activity.Type,
-		Agenda:       activity.Agenda,
-		StartTime:    activity.StartTime.Format(time.RFC3339),
-		EndTime:      activity.EndTime.Format(time.RFC3339),
-		LocationName: activity.LocationName,
-		Status:       activity.Status,
-		CreatedBy:    activity.CreatedBy,
-		CreatedAt:    activity.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:    activity.UpdatedAt.Format(time.RFC3339),
-	}
+	activityResponse := toActivityResponse(activity)
 
 	c.JSON(http.StatusOK, activityResponse)
 }
@@ -4,10 +4,10 @@ import (
 	"customer-api/internal/config"
 	"customer-api/internal/dto"
 	"customer-api/internal/entity"
+	"customer-api/internal/region"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	// Hapus import "gorm.io/gorm" karena tidak digunakan
 )
 
 // @Summary Create address for customer
@@ -42,15 +42,49 @@ func CreateAddress(c *gin.Context) {
 		return
 	}
 
-	// Hapus variabel customerID yang tidak digunakan
-	// customerID := c.Param("id") // Jika dari endpoint /customers/{id}/addresses
+	// Customer ID diambil dari path (/customers/:id/addresses) atau dari body (/addresses)
+	customerID := c.Param("id")
+	if customerID == "" {
+		customerID = req.CustomerID
+	}
+	if customerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "customer_id is required"})
+		return
+	}
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
 
 	address := entity.Address{
-		// CustomerID: customer.ID, // Akan diset sesuai kebutuhan
-		Name:    req.Name,
-		Address: req.Address,
-		Main:    req.IsMain,
-		Active:  req.Active,
+		CustomerID:   customer.ID,
+		Name:         req.Name,
+		Address:      req.Address,
+		Street:       req.Street,
+		ProvinceCode: req.ProvinceCode,
+		RegencyCode:  req.RegencyCode,
+		DistrictCode: req.DistrictCode,
+		VillageCode:  req.VillageCode,
+		PostalCode:   req.PostalCode,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Main:         req.IsMain,
+		Active:       req.Active,
+	}
+	if err := validateCoordinates(address.Latitude, address.Longitude); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyAddressRegion(&address); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// If this is set as main address, set all other addresses to false
+	if address.Main {
+		config.DB.Model(&entity.Address{}).Where("customer_id = ?", customer.ID).Update("main", false)
 	}
 
 	result := config.DB.Create(&address)
@@ -58,10 +92,45 @@ func CreateAddress(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create address"})
 		return
 	}
+	reassignTeamByAddress(address.CustomerID)
+	refreshHealthScore(address.CustomerID, "address")
 
 	c.JSON(http.StatusCreated, address)
 }
 
+// applyAddressRegion memvalidasi kode wilayah dan kode pos alamat, melengkapi kode induk,
+// lalu mengisi State/City/Country dari data wilayah
+func applyAddressRegion(address *entity.Address) error {
+	codes, path, err := region.Resolve(region.Codes{
+		ProvinceCode: address.ProvinceCode,
+		RegencyCode:  address.RegencyCode,
+		DistrictCode: address.DistrictCode,
+		VillageCode:  address.VillageCode,
+		PostalCode:   address.PostalCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	address.ProvinceCode = codes.ProvinceCode
+	address.RegencyCode = codes.RegencyCode
+	address.DistrictCode = codes.DistrictCode
+	address.VillageCode = codes.VillageCode
+	address.PostalCode = codes.PostalCode
+	if path.Province != nil {
+		address.State = path.Province.Name
+		address.Country = "Indonesia"
+	}
+	if path.Regency != nil {
+		address.City = path.Regency.Name
+	}
+	// kode pos bisa diturunkan dari kelurahan jika tidak diisi
+	if address.PostalCode == "" && path.Village != nil {
+		address.PostalCode = path.Village.PostalCode
+	}
+	return nil
+}
+
 // @Summary Get customer addresses
 // @Description Get all addresses for specific customer
 // @Tags Addresses
@@ -127,7 +196,7 @@ func UpdateAddress(c *gin.Context) {
 	id := c.Param("id")
 
 	var address entity.Address
-	result := config.DB.First(&address, id)
+	result := config.DB.Where("id = ?", id).First(&address)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
 		return
@@ -138,6 +207,47 @@ func UpdateAddress(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	updateData.ID, updateData.CustomerID = "", ""
+
+	// Koordinat hanya diubah jika dikirim; untuk menghapus koordinat pakai PUT /addresses/:id/coordinates
+	if updateData.Latitude != nil || updateData.Longitude != nil {
+		if err := validateCoordinates(updateData.Latitude, updateData.Longitude); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Kode wilayah yang dikirim menggantikan seluruh kode lama; kode induk yang kosong dilengkapi otomatis
+	codesChanged := updateData.ProvinceCode != "" || updateData.RegencyCode != "" ||
+		updateData.DistrictCode != "" || updateData.VillageCode != ""
+	if codesChanged || updateData.PostalCode != "" {
+		merged := address
+		if codesChanged {
+			merged.ProvinceCode = updateData.ProvinceCode
+			merged.RegencyCode = updateData.RegencyCode
+			merged.DistrictCode = updateData.DistrictCode
+			merged.VillageCode = updateData.VillageCode
+		}
+		if updateData.PostalCode != "" {
+			merged.PostalCode = updateData.PostalCode
+		}
+		if err := applyAddressRegion(&merged); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		config.DB.Model(&address).Updates(map[string]interface{}{
+			"province_code": merged.ProvinceCode,
+			"regency_code":  merged.RegencyCode,
+			"district_code": merged.DistrictCode,
+			"village_code":  merged.VillageCode,
+			"postal_code":   merged.PostalCode,
+			"state":         merged.State,
+			"city":          merged.City,
+			"country":       merged.Country,
+		})
+		updateData.ProvinceCode, updateData.RegencyCode, updateData.DistrictCode, updateData.VillageCode = "", "", "", ""
+		updateData.PostalCode, updateData.State, updateData.City, updateData.Country = "", "", "", ""
+	}
 
 	// If this is set as main address, set all other addresses to false
 	if updateData.Main {
@@ -146,6 +256,9 @@ func UpdateAddress(c *gin.Context) {
 
 	// Update the address
 	config.DB.Model(&address).Updates(updateData)
+	config.DB.Where("id = ?", address.ID).First(&address)
+	reassignTeamByAddress(address.CustomerID)
+	refreshHealthScore(address.CustomerID, "address")
 	c.JSON(http.StatusOK, address)
 }
 
@@ -163,16 +276,17 @@ func UpdateAddress(c *gin.Context) {
 func DeleteAddress(c *gin.Context) {
 	id := c.Param("id")
 
-	result := config.DB.Delete(&entity.Address{}, id)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete address"})
+	var address entity.Address
+	if err := config.DB.Where("id = ?", id).First(&address).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
+	if err := config.DB.Delete(&address).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete address"})
 		return
 	}
+	refreshHealthScore(address.CustomerID, "address")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Address deleted successfully"})
 }
@@ -145,34 +145,26 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	// Set default role_id if not provided or validate existing role_id
-	roleID := input.RoleID
-
-	if roleID == "" {
-		// Find "User" role from database
-		var userRole entity.Role
-		if err := config.DB.Where("role_name = ?", "User").First(&userRole).Error; err != nil {
-			// If User role not found, use the first available role
-			if err := config.DB.First(&userRole).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "No roles available in the system",
-					"hint":  "Please contact administrator to set up user roles",
-				})
-				return
-			}
-		}
-		roleID = userRole.ID
-	} else {
-		// Validate that the provided role_id exists in database
-		var role entity.Role
-		if err := config.DB.Where("id = ?", roleID).First(&role).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid role_id",
-				"hint":  "Please provide a valid role ID. Use GET /api/roles to see available roles",
+	// Self-registration always gets the default role; other roles are assigned by an admin
+	var userRole entity.Role
+	if err := config.DB.Where("role_name = ? AND is_admin = ?", "User", false).First(&userRole).Error; err != nil {
+		// If User role not found, use the first available non-admin role
+		if err := config.DB.Where("is_admin = ?", false).First(&userRole).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "No roles available in the system",
+				"hint":  "Please contact administrator to set up user roles",
 			})
 			return
 		}
 	}
+	if input.RoleID != "" && input.RoleID != userRole.ID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid role_id",
+			"hint":  "Registration only assigns the default role. Ask an administrator to change your role",
+		})
+		return
+	}
+	roleID := userRole.ID
 
 	// Create new user
 	user := entity.User{
@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	// Hapus import "gorm.io/gorm" karena tidak digunakan
+	"gorm.io/gorm"
 )
 
 // @Summary Create contact for customer
@@ -34,6 +34,7 @@ import (
 // @Success 201 {object} entity.Contact
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/contacts [post]
 func CreateContact(c *gin.Context) {
@@ -42,10 +43,19 @@ func CreateContact(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.CustomerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "customer_id is required"})
+		return
+	}
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", req.CustomerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
 
-	// Hapus validasi customer exists karena CustomerID tidak ada di request
 	contact := entity.Contact{
-		// CustomerID: customer.ID, // Akan diset sesuai kebutuhan
+		CustomerID:  customer.ID,
 		Name:        req.Name,
 		JobPosition: req.JobPosition,
 		Email:       req.Email,
@@ -67,6 +77,7 @@ func CreateContact(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contact"})
 		return
 	}
+	refreshHealthScore(contact.CustomerID, "contact")
 
 	c.JSON(http.StatusCreated, contact)
 }
@@ -136,18 +147,26 @@ func UpdateContact(c *gin.Context) {
 	id := c.Param("id")
 
 	var contact entity.Contact
-	result := config.DB.First(&contact, id)
+	result := config.DB.Where("id = ?", id).First(&contact)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
 		return
 	}
 
+	contactID, customerID := contact.ID, contact.CustomerID
 	if err := c.ShouldBindJSON(&contact); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	contact.ID, contact.CustomerID = contactID, customerID
+
+	if err := validateContactPlacement(config.DB, &contact); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	config.DB.Save(&contact)
+	refreshHealthScore(contact.CustomerID, "contact")
 	c.JSON(http.StatusOK, contact)
 }
 
@@ -165,16 +184,25 @@ func UpdateContact(c *gin.Context) {
 func DeleteContact(c *gin.Context) {
 	id := c.Param("id")
 
-	result := config.DB.Delete(&entity.Contact{}, id)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact"})
+	var contact entity.Contact
+	if err := config.DB.Where("id = ?", id).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+	// Bawahan langsung naik melapor ke atasan contact yang dihapus
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Contact{}).Where("reports_to_id = ?", contact.ID).
+			Update("reports_to_id", contact.ReportsToID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&contact).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact"})
 		return
 	}
+	refreshHealthScore(contact.CustomerID, "contact")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Contact deleted successfully"})
 }
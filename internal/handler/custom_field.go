@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipe data custom field
+const (
+	fieldTypeText    = "text"
+	fieldTypeNumber  = "number"
+	fieldTypeDate    = "date"
+	fieldTypeEnum    = "enum"
+	fieldTypeBoolean = "boolean"
+	fieldTypeURL     = "url"
+)
+
+var (
+	fieldTypes          = []string{fieldTypeText, fieldTypeNumber, fieldTypeDate, fieldTypeEnum, fieldTypeBoolean, fieldTypeURL}
+	customFieldKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,99}$`)
+	customFieldDates    = []string{"2006-01-02", time.RFC3339, "02/01/2006", "02-01-2006"}
+	// format angka Indonesia: titik pemisah ribuan (kelompok 3 digit), koma pemisah desimal
+	customFieldNumberRegex = regexp.MustCompile(`^-?([0-9]+|[0-9]{1,3}(\.[0-9]{3})+)(,[0-9]+)?$`)
+)
+
+func isFieldType(dataType string) bool {
+	for _, t := range fieldTypes {
+		if t == dataType {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCustomFields mengambil definisi custom field aktif beserta pilihan enum, di-index per key
+func loadCustomFields(db *gorm.DB) (map[string]entity.OthersConfig, error) {
+	var configs []entity.OthersConfig
+	err := db.Preload("Options", "is_active = ?", true).Where("is_active = ?", true).Find(&configs).Error
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]entity.OthersConfig, len(configs))
+	for _, field := range configs {
+		fields[field.Key] = field
+	}
+	return fields, nil
+}
+
+// parseCustomFieldNumber membaca angka berformat Indonesia ("1.500.000", "2,5"). Titik yang
+// bukan pemisah ribuan ditolak agar "2.5" tidak terbaca sebagai 25.
+func parseCustomFieldNumber(value string) (float64, error) {
+	if !customFieldNumberRegex.MatchString(value) {
+		return 0, fmt.Errorf("invalid number '%s'", value)
+	}
+	value = strings.ReplaceAll(value, ".", "")
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// coerceCustomFieldValue memvalidasi nilai terhadap tipe custom field dan mengembalikan bentuk kanoniknya:
+// angka tanpa format ribuan, tanggal YYYY-MM-DD, boolean true/false, nilai enum sesuai pilihan, URL lengkap
+func coerceCustomFieldValue(field entity.OthersConfig, raw *string) (*string, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		if field.Required {
+			return nil, fmt.Errorf("%s is required", field.Label)
+		}
+		return nil, nil
+	}
+	value := strings.TrimSpace(*raw)
+
+	switch field.DataType {
+	case fieldTypeNumber:
+		number, err := parseCustomFieldNumber(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number, use . for thousands and , for decimals", field.Label)
+		}
+		value = strconv.FormatFloat(number, 'f', -1, 64)
+	case fieldTypeDate:
+		parsed := false
+		for _, layout := range customFieldDates {
+			if t, err := time.Parse(layout, value); err == nil {
+				value = t.Format("2006-01-02")
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD)", field.Label)
+		}
+	case fieldTypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "1", "yes", "ya", "y":
+			value = "true"
+		case "false", "0", "no", "tidak", "n":
+			value = "false"
+		default:
+			return nil, fmt.Errorf("%s must be true or false", field.Label)
+		}
+	case fieldTypeEnum:
+		matched := false
+		allowed := make([]string, 0, len(field.Options))
+		for _, option := range field.Options {
+			allowed = append(allowed, option.Value)
+			if strings.EqualFold(option.Value, value) {
+				value = option.Value
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("%s must be one of: %s", field.Label, strings.Join(allowed, ", "))
+		}
+	case fieldTypeURL:
+		if !strings.Contains(value, "://") {
+			value = "https://" + value
+		}
+		parsed, err := url.ParseRequestURI(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%s must be a valid URL", field.Label)
+		}
+		value = parsed.String()
+	}
+	return &value, nil
+}
+
+// coerceCustomerOthers memvalidasi semua Other pada create customer: key harus terdefinisi,
+// tidak boleh duplikat, nilai di-coerce sesuai tipe, dan field wajib harus terisi
+func coerceCustomerOthers(db *gorm.DB, others []dto.CreateOtherRequest) ([]dto.CreateOtherRequest, error) {
+	fields, err := loadCustomFields(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.CreateOtherRequest, 0, len(others))
+	seen := map[string]bool{}
+	for _, other := range others {
+		field, ok := fields[other.Key]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field '%s'", other.Key)
+		}
+		if seen[other.Key] {
+			return nil, fmt.Errorf("custom field '%s' is given more than once", other.Key)
+		}
+		seen[other.Key] = true
+
+		value, err := coerceCustomFieldValue(field, other.Value)
+		if err != nil {
+			return nil, err
+		}
+		other.Value = value
+		result = append(result, other)
+	}
+
+	missing := []string{}
+	for key, field := range fields {
+		if field.Required && !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required custom fields: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// coerceUpdatedOthers menerapkan aturan yang sama dengan coerceCustomerOthers pada Other yang
+// dikirim lewat update customer. Field wajib tidak diperiksa karena Other yang tidak dikirim
+// tetap tersimpan.
+func coerceUpdatedOthers(db *gorm.DB, others []entity.Other) error {
+	if len(others) == 0 {
+		return nil
+	}
+	fields, err := loadCustomFields(db)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for i := range others {
+		field, ok := fields[others[i].Key]
+		if !ok {
+			return fmt.Errorf("unknown custom field '%s'", others[i].Key)
+		}
+		if seen[others[i].Key] {
+			return fmt.Errorf("custom field '%s' is given more than once", others[i].Key)
+		}
+		seen[others[i].Key] = true
+
+		value, err := coerceCustomFieldValue(field, others[i].Value)
+		if err != nil {
+			return err
+		}
+		others[i].Value = value
+	}
+	return nil
+}
+
+// applyCustomFieldFilters menerjemahkan query other[key]=value menjadi filter customer.
+// Number dan date menerima rentang "min..max" (salah satu sisi boleh kosong), text dicari sebagian.
+func applyCustomFieldFilters(db *gorm.DB, filters map[string]string) (*gorm.DB, error) {
+	if len(filters) == 0 {
+		return db, nil
+	}
+	fields, err := loadCustomFields(config.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	const subquery = "id IN (SELECT customer_id FROM others WHERE deleted_at IS NULL AND active = ? AND key = ? AND "
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field '%s'", key)
+		}
+		raw := filters[key]
+
+		if (field.DataType == fieldTypeNumber || field.DataType == fieldTypeDate) && strings.Contains(raw, "..") {
+			bounds := strings.SplitN(raw, "..", 2)
+			column := "CASE WHEN value ~ '^-{0,1}[0-9]+(\\.[0-9]+){0,1}$' THEN CAST(value AS numeric) END" // tanpa '?' agar tidak dianggap placeholder
+			if field.DataType == fieldTypeDate {
+				column = "CASE WHEN value ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}$' THEN value END"
+			}
+			for i, op := range []string{">=", "<="} {
+				if strings.TrimSpace(bounds[i]) == "" {
+					continue
+				}
+				optional := field
+				optional.Required = false
+				bound, err := coerceCustomFieldValue(optional, &bounds[i])
+				if err != nil {
+					return nil, err
+				}
+				var arg interface{} = *bound
+				if field.DataType == fieldTypeNumber {
+					arg, _ = strconv.ParseFloat(*bound, 64)
+				}
+				db = db.Where(subquery+column+" "+op+" ?)", true, key, arg)
+			}
+			continue
+		}
+
+		if field.DataType == fieldTypeText {
+			db = db.Where(subquery+"value ILIKE ?)", true, key, "%"+raw+"%")
+			continue
+		}
+		optional := field
+		optional.Required = false
+		value, err := coerceCustomFieldValue(optional, &raw)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		db = db.Where(subquery+"value = ?)", true, key, *value)
+	}
+	return db, nil
+}
@@ -6,6 +6,7 @@ import (
 	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"fmt"
+	"log"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -18,6 +19,15 @@ import (
 	// "strconv"
 )
 
+// customerSortColumns adalah kolom yang boleh dipakai untuk mengurutkan daftar customer
+var customerSortColumns = map[string]bool{
+	"name":         true,
+	"created_at":   true,
+	"rating":       true,
+	"average_cost": true,
+	"health_score": true,
+}
+
 // @Summary Get all customers
 // @Description Get list of all customers with optional status filter
 // @Tags Customers
@@ -25,6 +35,9 @@ import (
 // @Produce json
 // @Security BearerAuth
 // @Param status query string false "Filter by status" Enums(Active, Inactive, Blocked)
+// @Param team_id query string false "Filter by team ID"
+// @Param other[key] query string false "Filter by custom field value, e.g. other[company_size]=enterprise; number/date fields accept min..max"
+// @Param sort query string false "Sort by name, created_at, rating, average_cost or health_score; prefix with - for descending (e.g. -health_score)"
 // @Success 200 {object} dto.CustomersResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -32,11 +45,36 @@ import (
 func GetCustomers(c *gin.Context) {
 	var customers []entity.Customer
 	status := c.Query("status")
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
 
-	db := config.DB.Preload("AccountManager")
+	db := policy.Filter(config.DB.Preload("AccountManager"), "id")
 	if status != "" {
 		db = db.Where("status = ?", status)
 	}
+	if teamID := c.Query("team_id"); teamID != "" {
+		db = db.Where("team_id = ?", teamID)
+	}
+	db, err := applyCustomFieldFilters(db, c.QueryMap("other"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if sortParam := c.Query("sort"); sortParam != "" {
+		column := strings.TrimPrefix(sortParam, "-")
+		if !customerSortColumns[column] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of name, created_at, rating, average_cost, health_score"})
+			return
+		}
+		direction := "ASC"
+		if strings.HasPrefix(sortParam, "-") {
+			direction = "DESC"
+		}
+		// customer yang belum punya health score selalu di akhir
+		db = db.Order(column + " " + direction + " NULLS LAST")
+	}
 
 	result := db.Find(&customers)
 	if result.Error != nil {
@@ -47,43 +85,22 @@ func GetCustomers(c *gin.Context) {
 	// Convert entity customers to DTO response format with proper field ordering
 	var customerResponses []dto.CustomerListResponse
 	for _, customer := range customers {
-		customerResponse := dto.CustomerListResponse{
-			ID:          customer.ID,
-			Name:        customer.Name,
-			BrandName:   customer.BrandName,
-			Code:        customer.Code,
-			Logo:        customer.Logo,
-			Status:      customer.Status,
-			Category:    customer.Category,
-			Rating:      customer.Rating,
-			AverageCost: customer.AverageCost,
-			LogoSmall:   customer.LogoSmall,
-			CreatedAt:   customer.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:   customer.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
-
-		// Add manager_name instead of account_manager_id
-		if customer.AccountManager != nil {
-			customerResponse.ManagerName = &customer.AccountManager.ManagerName
-		} else {
-			customerResponse.ManagerName = nil
-		}
-
-		customerResponses = append(customerResponses, customerResponse)
+		customerResponses = append(customerResponses, toCustomerListResponse(customer))
 	}
 
-	// Calculate statistics
+	// Calculate statistics (hanya customer yang terlihat oleh user)
+	visible := func() *gorm.DB { return policy.Filter(config.DB.Model(&entity.Customer{}), "id") }
 	var totalCustomers int64
-	config.DB.Model(&entity.Customer{}).Count(&totalCustomers)
+	visible().Count(&totalCustomers)
 
 	var newCustomers int64
-	config.DB.Model(&entity.Customer{}).Where("created_at >= NOW() - INTERVAL '1 year'").Count(&newCustomers)
+	visible().Where("created_at >= NOW() - INTERVAL '1 year'").Count(&newCustomers)
 
 	var avgCost float64
-	config.DB.Model(&entity.Customer{}).Select("COALESCE(AVG(average_cost), 0)").Row().Scan(&avgCost)
+	visible().Select("COALESCE(AVG(average_cost), 0)").Row().Scan(&avgCost)
 
 	var blockedCustomers int64
-	config.DB.Model(&entity.Customer{}).Where("status = ?", "blocked").Count(&blockedCustomers)
+	visible().Where("status = ?", "blocked").Count(&blockedCustomers)
 
 	c.JSON(http.StatusOK, gin.H{
 		"customers": customerResponses,
@@ -96,6 +113,31 @@ func GetCustomers(c *gin.Context) {
 	})
 }
 
+// toCustomerListResponse mengubah entity customer ke format list (manager_name, bukan account_manager_id)
+func toCustomerListResponse(customer entity.Customer) dto.CustomerListResponse {
+	customerResponse := dto.CustomerListResponse{
+		ID:          customer.ID,
+		Name:        customer.Name,
+		BrandName:   customer.BrandName,
+		Code:        customer.Code,
+		Logo:        customer.Logo,
+		Status:      customer.Status,
+		Category:    customer.Category,
+		Rating:      customer.Rating,
+		AverageCost: customer.AverageCost,
+		HealthScore: customer.HealthScore,
+		LogoSmall:   customer.LogoSmall,
+		CreatedAt:   customer.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   customer.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	// Add manager_name instead of account_manager_id
+	if customer.AccountManager != nil {
+		customerResponse.ManagerName = &customer.AccountManager.ManagerName
+	}
+	return customerResponse
+}
+
 // @Summary Create new customer
 // @Description Create a new customer record with all related data including addresses, social media, contacts, structures, groups, and other attributes. You can specify AccountManager either by account_manager_id or manager_name.
 // @Tags Customers
@@ -200,6 +242,31 @@ func CreateCustomer(c *gin.Context) {
 		return
 	}
 
+	// Validasi dan normalisasi akun sosmed sebelum transaksi dimulai
+	socialAccounts := make([]socialAccount, 0, len(req.Socials))
+	seenSocials := make(map[string]bool)
+	for i, socialReq := range req.Socials {
+		account, err := normalizeSocialAccount(socialReq.Platform, socialReq.Handle, socialReq.URL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("socials[%d]: %v", i, err)})
+			return
+		}
+		key := account.Platform + "|" + account.Username
+		if seenSocials[key] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("socials[%d]: duplicate %s account %s", i, account.Platform, account.Handle)})
+			return
+		}
+		seenSocials[key] = true
+		socialAccounts = append(socialAccounts, account)
+	}
+
+	// Validasi custom field terhadap definisi OthersConfig
+	others, err := coerceCustomerOthers(config.DB, req.Others)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "others: " + err.Error()})
+		return
+	}
+
 	// Start transaction
 	tx := config.DB.Begin()
 	defer func() {
@@ -224,6 +291,29 @@ func CreateCustomer(c *gin.Context) {
 		accountManagerID = req.AccountManagerID
 	}
 
+	// Team dipilih manual; jika kosong di-assign otomatis dari territory setelah customer tersimpan
+	var teamID *string
+	if req.TeamID != nil && *req.TeamID != "" {
+		var team entity.Teams
+		if err := config.DB.Where("id = ?", *req.TeamID).First(&team).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Team with ID '" + *req.TeamID + "' not found"})
+			return
+		}
+		teamID = &team.ID
+	}
+
+	// Induk perusahaan (opsional)
+	var parentID *string
+	if req.ParentID != "" {
+		if err := validateCustomerParent(config.DB, "", &req.ParentID); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		parentID = &req.ParentID
+	}
+
 	// Di dalam fungsi CreateCustomer, tambahkan setelah Logo assignment:
 	// Create customer entity
 	customer := entity.Customer{
@@ -231,8 +321,13 @@ func CreateCustomer(c *gin.Context) {
 		BrandName:        *req.BrandName,
 		Code:             *req.Code,
 		AccountManagerID: accountManagerID,
+		TeamID:           teamID,
+		ParentID:         parentID,
 		Status:           "Draft", // Default status
 	}
+	if teamID != nil {
+		customer.TeamSource = teamSourceManual
+	}
 
 	// Set logo if provided
 	if req.Logo != "" {
@@ -267,10 +362,27 @@ func CreateCustomer(c *gin.Context) {
 		address := entity.Address{
 			CustomerID: customer.ID,
 			// SupplierID field removed as it doesn't exist in entity.Address
-			Name:    addrReq.Name,
-			Address: addrReq.Address,
-			Main:    addrReq.IsMain,
-			Active:  addrReq.Active,
+			Name:         addrReq.Name,
+			Address:      addrReq.Address,
+			Street:       addrReq.Street,
+			ProvinceCode: addrReq.ProvinceCode,
+			RegencyCode:  addrReq.RegencyCode,
+			DistrictCode: addrReq.DistrictCode,
+			VillageCode:  addrReq.VillageCode,
+			PostalCode:   addrReq.PostalCode,
+			Latitude:     addrReq.Latitude,
+			Longitude:    addrReq.Longitude,
+			Main:         addrReq.IsMain,
+			Active:       addrReq.Active,
+		}
+		err := validateCoordinates(address.Latitude, address.Longitude)
+		if err == nil {
+			err = applyAddressRegion(&address)
+		}
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address '" + addrReq.Name + "': " + err.Error()})
+			return
 		}
 		if err := tx.Create(&address).Error; err != nil {
 			tx.Rollback()
@@ -280,12 +392,20 @@ func CreateCustomer(c *gin.Context) {
 	}
 
 	// Create social media
-	for _, socialReq := range req.Socials {
+	for i, socialReq := range req.Socials {
+		account := socialAccounts[i]
+		name := socialReq.Name
+		if name == "" {
+			name = account.Platform + " " + account.Handle
+		}
 		sosmed := entity.Sosmed{
 			CustomerID: customer.ID,
-			Name:       socialReq.Platform, // Atau buat field Name di DTO
-			Platform:   socialReq.Platform,
-			Handle:     socialReq.Handle,
+			Name:       name,
+			Platform:   account.Platform,
+			Handle:     account.Handle,
+			Username:   account.Username,
+			URL:        account.URL,
+			Followers:  socialReq.Followers,
 			Active:     socialReq.Active,
 		}
 		if err := tx.Create(&sosmed).Error; err != nil {
@@ -293,6 +413,12 @@ func CreateCustomer(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create social media: " + err.Error()})
 			return
 		}
+		// snapshot awal sebagai baseline pertumbuhan followers
+		if _, err := recordSosmedSnapshot(tx, sosmed.ID, sosmed.Followers, sosmed.CreatedAt, "create", ""); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record social media snapshot: " + err.Error()})
+			return
+		}
 	}
 
 	// Create contacts
@@ -324,21 +450,28 @@ func CreateCustomer(c *gin.Context) {
 
 	// Create structures with hierarchy
 	tempKeyMap := make(map[string]string)
+	tempLevelMap := make(map[string]int)
+	siblingCount := make(map[string]int)
 	for _, structReq := range req.Structures {
 		structure := entity.Structure{
 			CustomerID: customer.ID,
 			Name:       structReq.Name,
-			Level:      structReq.Level,
+			Level:      1,
 			Address:    structReq.Address,
 			Active:     structReq.Active,
 		}
 
-		// Set parent if exists
+		// Set parent if exists, level mengikuti parent
+		parentKey := ""
 		if structReq.ParentKey != nil {
 			if parentID, exists := tempKeyMap[*structReq.ParentKey]; exists {
 				structure.ParentID = &parentID
+				structure.Level = tempLevelMap[*structReq.ParentKey] + 1
+				parentKey = parentID
 			}
 		}
+		structure.Position = siblingCount[parentKey]
+		siblingCount[parentKey]++
 
 		if err := tx.Create(&structure).Error; err != nil {
 			tx.Rollback()
@@ -348,10 +481,11 @@ func CreateCustomer(c *gin.Context) {
 
 		// Store temp key mapping
 		tempKeyMap[structReq.TempKey] = structure.ID
+		tempLevelMap[structReq.TempKey] = structure.Level
 	}
 
 	// Create others
-	for _, otherReq := range req.Others {
+	for _, otherReq := range others {
 		other := entity.Other{
 			CustomerID: customer.ID,
 			Key:        otherReq.Key,
@@ -367,23 +501,40 @@ func CreateCustomer(c *gin.Context) {
 
 	// Handle groups (industry and parent group)
 	// Note: This assumes groups already exist in the database
-	// Hapus: for _, group := range req.Groups {
+	groupIDs := []string{}
 	if req.Groups.IndustryID != "" && req.Groups.IndustryActive {
-		// Find industry group and associate
-		var industryGroup entity.Group
-		if err := tx.Where("id = ?", req.Groups.IndustryID).First(&industryGroup).Error; err == nil {
-			tx.Model(&customer).Association("Groups").Append(&industryGroup)
-		}
+		groupIDs = append(groupIDs, req.Groups.IndustryID)
 	}
-
 	if req.Groups.ParentGroupID != "" && req.Groups.ParentGroupActive {
-		// Find parent group and associate
-		var parentGroup entity.Group
-		if err := tx.Where("id = ?", req.Groups.ParentGroupID).First(&parentGroup).Error; err == nil {
-			tx.Model(&customer).Association("Groups").Append(&parentGroup)
+		groupIDs = append(groupIDs, req.Groups.ParentGroupID)
+	}
+	for _, groupID := range groupIDs {
+		var group entity.Group
+		if err := tx.Where("id = ?", groupID).First(&group).Error; err != nil {
+			continue
 		}
+		// Customer maksimal satu group per jenis exclusive
+		if conflict, err := exclusiveGroupConflict(tx, customer.ID, group); err != nil || conflict != nil {
+			tx.Rollback()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check customer groups: " + err.Error()})
+			} else {
+				c.JSON(http.StatusConflict, gin.H{"error": "Groups " + conflict.NameGroup + " and " + group.NameGroup + " have the same exclusive type"})
+			}
+			return
+		}
+		if err := tx.Model(&customer).Association("Groups").Append(&group); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign customer group: " + err.Error()})
+			return
+		}
+	}
+
+	if err := recordOwnershipChange(tx, customer.ID, nil, customer.AccountManagerID, userID, "Initial assignment", ""); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record customer ownership: " + err.Error()})
+		return
 	}
-	// Hapus: }
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
@@ -391,6 +542,12 @@ func CreateCustomer(c *gin.Context) {
 		return
 	}
 
+	if customer.TeamID == nil {
+		if _, _, err := autoAssignCustomerTeams(config.DB, []string{customer.ID}, false, false); err != nil {
+			log.Printf("failed to auto-assign team for customer %s: %v", customer.ID, err)
+		}
+	}
+
 	// Load customer with all relations for response
 	var createdCustomer entity.Customer
 	config.DB.Preload("AccountManager").Preload("Addresses").Preload("Sosmeds").Preload("Contacts").Preload("Structures").Preload("Groups").Preload("Others").First(&createdCustomer, "id = ?", customer.ID)
@@ -402,6 +559,7 @@ func CreateCustomer(c *gin.Context) {
 		BrandName:        createdCustomer.BrandName,
 		Code:             createdCustomer.Code,
 		AccountManagerID: createdCustomer.AccountManagerID,
+		TeamID:           createdCustomer.TeamID,
 		Logo:             createdCustomer.Logo,
 		LogoSmall:        createdCustomer.LogoSmall,
 		Status:           createdCustomer.Status,
@@ -550,12 +708,56 @@ func UpdateCustomer(c *gin.Context) {
 		return
 	}
 
+	// salin nilainya: ShouldBindJSON menulis lewat pointer yang sama
+	var previousAccountManagerID *string
+	if customer.AccountManagerID != nil {
+		previous := *customer.AccountManagerID
+		previousAccountManagerID = &previous
+	}
+
 	if err := c.ShouldBindJSON(&customer); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	customer.ID = id
 
-	config.DB.Save(&customer)
+	if customer.AccountManagerID != nil && *customer.AccountManagerID == "" {
+		customer.AccountManagerID = nil
+	}
+	if !samePtr(previousAccountManagerID, customer.AccountManagerID) && customer.AccountManagerID != nil {
+		var accountManager entity.AccountManager
+		if err := config.DB.Where("id = ?", *customer.AccountManagerID).First(&accountManager).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Account manager not found"})
+			return
+		}
+	}
+	if customer.ParentID != nil && *customer.ParentID == "" {
+		customer.ParentID = nil
+	}
+	if customer.ParentID != nil {
+		if err := validateCustomerParent(config.DB, customer.ID, customer.ParentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	customer.Parent = nil
+
+	// Others ikut tersimpan oleh Save, jadi divalidasi seperti pada create customer
+	if err := coerceUpdatedOthers(config.DB, customer.Others); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "others: " + err.Error()})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&customer).Error; err != nil {
+			return err
+		}
+		return recordOwnershipChange(tx, customer.ID, previousAccountManagerID, customer.AccountManagerID, userID, "Updated customer", "")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update customer: " + err.Error()})
+		return
+	}
 
 	// Insert HistoryCustomer
 	history := entity.HistoryCustomer{
@@ -586,8 +788,17 @@ func DeleteCustomer(c *gin.Context) {
 		return
 	}
 
-	result := config.DB.Delete(&entity.Customer{}, id)
-	if result.Error != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Anak perusahaan dipindah ke induk customer yang dihapus agar pohon tidak terputus
+		var deleted entity.Customer
+		if err := tx.Where("id = ?", id).First(&deleted).Error; err == nil {
+			if err := tx.Model(&entity.Customer{}).Where("parent_id = ?", deleted.ID).Update("parent_id", deleted.ParentID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", id).Delete(&entity.Customer{}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete customer"})
 		return
 	}
@@ -623,7 +834,7 @@ func UploadCustomerLogo(c *gin.Context) {
 	}
 	// Check if customer exists
 	var customer entity.Customer
-	result := config.DB.First(&customer, id)
+	result := config.DB.Where("id = ?", id).First(&customer)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
 		return
@@ -636,26 +847,42 @@ func UploadCustomerLogo(c *gin.Context) {
 		return
 	}
 
-	// Validate file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".svg" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Only JPG, PNG, and SVG files are allowed"})
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
 		return
 	}
+	defer src.Close()
 
-	// Generate unique filename
-	filename := "logo_" + id + "_" + time.Now().Format("20060102150405") + ext
-	logoPath := "uploads/logos/" + filename
-
-	// Save file
-	if err := c.SaveUploadedFile(file, logoPath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+	// Validasi isi file (bukan ekstensi), sanitasi SVG, lalu buat rendisi Logo dan LogoSmall
+	renditions, err := processLogo(customer.ID, src)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	oldLogo, oldLogoSmall := customer.Logo, customer.LogoSmall
+
 	// Update customer logo path
-	customer.Logo = logoPath
-	config.DB.Save(&customer)
+	customer.Logo = renditions.Logo
+	customer.LogoSmall = renditions.LogoSmall
+	if err := config.DB.Model(&customer).Updates(map[string]interface{}{
+		"logo":       customer.Logo,
+		"logo_small": customer.LogoSmall,
+	}).Error; err != nil {
+		removeLogoFile(renditions.Logo)
+		removeLogoFile(renditions.LogoSmall)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update customer logo"})
+		return
+	}
+
+	// Hapus file logo lama yang sudah digantikan
+	if oldLogo != customer.Logo {
+		removeLogoFile(oldLogo)
+	}
+	if oldLogoSmall != customer.LogoSmall {
+		removeLogoFile(oldLogoSmall)
+	}
 
 	// Insert HistoryCustomer
 	history := entity.HistoryCustomer{
@@ -667,9 +894,11 @@ func UploadCustomerLogo(c *gin.Context) {
 	config.DB.Create(&history)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "Logo uploaded successfully",
-		"logo_path": logoPath,
-		"customer":  customer,
+		"message":         "Logo uploaded successfully",
+		"logo_path":       renditions.Logo,
+		"logo_small_path": renditions.LogoSmall,
+		"mime_type":       renditions.MimeType,
+		"customer":        customer,
 	})
 }
 
@@ -734,9 +963,9 @@ func UpdateCustomerStatus(c *gin.Context) {
 	var document entity.Document
 	if err == nil { // kalau ada file
 		// Simpan file ke folder uploads/
-		filePath := fmt.Sprintf("uploads/documents/%d_%s", customer.ID, file.Filename)
-		if err := c.SaveUploadedFile(file, filePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload file"})
+		filePath, mimeType, status, err := saveDocumentFile(c, customer.ID, file)
+		if err != nil {
+			c.JSON(status, gin.H{"error": "Failed to upload file: " + err.Error()})
 			return
 		}
 
@@ -746,7 +975,13 @@ func UpdateCustomerStatus(c *gin.Context) {
 			UserID:     userID,
 			Notes:      notes,
 			Type:       "StatusChange",
+			Category:   "status_change",
 			URLFile:    filePath,
+			FileName:   filepath.Base(file.Filename),
+			MimeType:   mimeType,
+			Size:       file.Size,
+			Version:    1,
+			IsLatest:   true,
 		}
 		config.DB.Create(&document)
 	}
@@ -759,6 +994,7 @@ func UpdateCustomerStatus(c *gin.Context) {
 		Notes:      "Changed status to " + status,
 	}
 	config.DB.Create(&history)
+	refreshHealthScore(customer.ID, "status")
 
 	// Response
 	c.JSON(http.StatusOK, gin.H{
@@ -812,96 +1048,6 @@ func GetCustomerStatus(c *gin.Context) {
 	})
 }
 
-// @Summary Get customer statistics
-// @Description Get statistics about customers including total count, new customers in the last year, average cost, and blocked customers
-// @Tags Customers
-// @Param status query string false "Filter by status" Enums(Active, Inactive, Blocked)
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} dto.CustomerStatsResponse
-// @Failure 401 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /api/customers/stats [get]
-func GetCustomerStats(c *gin.Context) {
-	status := c.Query("status")
-
-	// Helper: apply filter & range tahun
-	queryWithFilter := func(base *gorm.DB, status string, start, end *time.Time) *gorm.DB {
-		q := base
-		if status != "" {
-			q = q.Where("status = ?", status)
-		}
-		if start != nil && end != nil {
-			q = q.Where("created_at BETWEEN ? AND ?", *start, *end)
-		}
-		return q
-	}
-
-	// Periode
-	now := time.Now()
-	thisYearStart := now.AddDate(-1, 0, 0) // 1 tahun ke belakang
-	lastYearStart := now.AddDate(-2, 0, 0) // 2 tahun ke belakang
-	lastYearEnd := now.AddDate(-1, 0, 0)
-
-	var totalThisYear, totalLastYear int64
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, &thisYearStart, &now).
-		Count(&totalThisYear)
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, &lastYearStart, &lastYearEnd).
-		Count(&totalLastYear)
-
-	var newThisYear, newLastYear int64
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, &thisYearStart, &now).
-		Count(&newThisYear)
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, &lastYearStart, &lastYearEnd).
-		Count(&newLastYear)
-
-	var avgThisYear, avgLastYear float64
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, &thisYearStart, &now).
-		Select("COALESCE(AVG(average_cost), 0)").Row().Scan(&avgThisYear)
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, &lastYearStart, &lastYearEnd).
-		Select("COALESCE(AVG(average_cost), 0)").Row().Scan(&avgLastYear)
-
-	var churnThisYear, churnLastYear int64
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, nil, nil).
-		Where("last_transaction_at < ?", thisYearStart).Count(&churnThisYear)
-	queryWithFilter(config.DB.Model(&entity.Customer{}), status, nil, nil).
-		Where("last_transaction_at BETWEEN ? AND ?", lastYearStart, lastYearEnd).Count(&churnLastYear)
-
-	calcGrowth := func(thisYear, lastYear float64) float64 {
-		if lastYear == 0 {
-			if thisYear > 0 {
-				return 100.0
-			}
-			return 0
-		}
-		return ((thisYear - lastYear) / lastYear) * 100
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Customer statistics fetched successfully",
-		"data": gin.H{
-			"total_customers": gin.H{
-				"value":  totalThisYear,
-				"growth": calcGrowth(float64(totalThisYear), float64(totalLastYear)),
-			},
-			"new_customers": gin.H{
-				"value":  newThisYear,
-				"growth": calcGrowth(float64(newThisYear), float64(newLastYear)),
-			},
-			"avg_revenue": gin.H{
-				"value":  avgThisYear,
-				"growth": calcGrowth(avgThisYear, avgLastYear),
-			},
-			"churn_customers": gin.H{
-				"value":  churnThisYear,
-				"growth": calcGrowth(float64(churnThisYear), float64(churnLastYear)),
-			},
-		},
-	})
-}
-
 // ExportCustomers handles customer export to Excel or PDF
 func ExportCustomers(c *gin.Context) {
 	exportType := c.Query("type")
@@ -912,9 +1058,14 @@ func ExportCustomers(c *gin.Context) {
 		return
 	}
 
-	// ambil data customer
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	// ambil data customer yang terlihat oleh user
 	var customers []entity.Customer
-	if err := config.DB.Find(&customers).Error; err != nil {
+	if err := policy.Filter(config.DB, "id").Find(&customers).Error; err != nil {
 		sendError(c, http.StatusInternalServerError, "Failed to fetch customers")
 		return
 	}
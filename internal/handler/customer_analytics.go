@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAnalyticsMonths = 12
+	maxAnalyticsMonths     = 36
+	defaultChurnMonths     = 3
+	maxChurnMonths         = 12
+)
+
+// customerAnalyticsSQL menghitung status setiap customer di akhir setiap bulan.
+// Status blocked diambil dari riwayat status_reasons, aktif berarti ada aktivitas atau invoice
+// dalam churn window (customer baru dianggap aktif selama window pertamanya).
+// Semua langkah berupa agregasi/hash join per tabel, tanpa subquery per baris, supaya tetap cepat
+// pada tabel besar. Bulan pertama di deret hanya dipakai sebagai pembanding.
+const customerAnalyticsSQL = `WITH months AS (
+		SELECT generate_series(?::date, ?::date, interval '1 month')::date AS month_start
+	),
+	base AS (?),
+	touches AS (
+		SELECT customer_id, date_trunc('month', start_time)::date AS month
+		FROM activities
+		WHERE deleted_at IS NULL AND start_time >= ? AND start_time < ?
+			AND LOWER(status) NOT IN ('cancelled', 'canceled')
+			AND customer_id IN (SELECT id FROM base)
+		UNION
+		SELECT customer_id, date_trunc('month', issued_date)::date
+		FROM invoices
+		WHERE deleted_at IS NULL AND issued_date >= ? AND issued_date < ?
+			AND customer_id IN (SELECT id FROM base)
+	),
+	covered AS (
+		SELECT DISTINCT t.customer_id, (t.month + make_interval(months => n))::date AS month
+		FROM touches t CROSS JOIN generate_series(0, ?) AS n
+	),
+	status_periods AS (
+		SELECT customer_id, LOWER(status) AS status, created_at AS valid_from,
+			LEAD(created_at) OVER (PARTITION BY customer_id ORDER BY created_at) AS valid_to
+		FROM status_reasons
+		WHERE deleted_at IS NULL AND customer_id IN (SELECT id FROM base)
+	),
+	first_change AS (
+		SELECT customer_id, MIN(valid_from) AS changed_at FROM status_periods GROUP BY customer_id
+	),
+	grid AS (
+		SELECT m.month_start, b.id, b.created_at,
+			CASE
+				WHEN COALESCE(sp.status, CASE WHEN fc.changed_at IS NULL THEN LOWER(b.status) ELSE 'active' END) = 'blocked' THEN 'blocked'
+				WHEN cv.customer_id IS NOT NULL OR b.created_at >= m.month_start + make_interval(months => 1 - ?) THEN 'active'
+				ELSE 'inactive'
+			END AS state
+		FROM months m
+		JOIN base b ON b.created_at < m.month_start + interval '1 month'
+		LEFT JOIN first_change fc ON fc.customer_id = b.id
+		LEFT JOIN status_periods sp ON sp.customer_id = b.id
+			AND sp.valid_from < m.month_start + interval '1 month'
+			AND (sp.valid_to IS NULL OR sp.valid_to >= m.month_start + interval '1 month')
+		LEFT JOIN covered cv ON cv.customer_id = b.id AND cv.month = m.month_start
+	),
+	transitions AS (
+		SELECT month_start, created_at, state,
+			LAG(state) OVER (PARTITION BY id ORDER BY month_start) AS prev_state
+		FROM grid
+	)
+	SELECT month_start,
+		COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE created_at >= month_start) AS new,
+		COUNT(*) FILTER (WHERE state = 'active') AS active,
+		COUNT(*) FILTER (WHERE state = 'blocked') AS blocked,
+		COUNT(*) FILTER (WHERE state = 'inactive') AS inactive,
+		COUNT(*) FILTER (WHERE prev_state = 'active' AND state <> 'active') AS churned
+	FROM transitions
+	GROUP BY month_start
+	ORDER BY month_start`
+
+// customerAnalyticsRow adalah hasil agregasi satu bulan
+type customerAnalyticsRow struct {
+	MonthStart time.Time
+	Total      int64
+	New        int64
+	Active     int64
+	Blocked    int64
+	Inactive   int64
+	Churned    int64
+}
+
+// calcGrowth menghitung pertumbuhan (%) dari nilai periode sebelumnya
+func calcGrowth(current, previous float64) float64 {
+	if previous == 0 {
+		if current > 0 {
+			return 100.0
+		}
+		return 0
+	}
+	return ((current - previous) / previous) * 100
+}
+
+// parseAnalyticsMonth membaca bulan dengan format YYYY-MM
+func parseAnalyticsMonth(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01", value, time.Local)
+}
+
+// @Summary Get customer statistics
+// @Description Monthly time series of total, new, active, blocked, inactive and churned customers with month-over-month growth. A customer is blocked when its status (from the status history) is blocked at the end of the month, and active when it is not blocked and had an activity or invoice within the churn window (new customers count as active during their first window). Churned counts customers that were active at the end of the previous month but are not anymore.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First month (YYYY-MM), defaults to 11 months before to"
+// @Param to query string false "Last month (YYYY-MM), defaults to the current month"
+// @Param churn_months query int false "Months without activity or invoice before a customer counts as churned (1-12, default 3)"
+// @Param account_manager_id query string false "Filter by account manager ID"
+// @Param group_id query string false "Filter by group ID (includes its descendant groups)"
+// @Param category query string false "Filter by customer category"
+// @Success 200 {object} dto.CustomerAnalyticsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/statistics [get]
+func GetCustomerStats(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseAnalyticsMonth(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must use the YYYY-MM format"})
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, -(defaultAnalyticsMonths - 1), 0)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseAnalyticsMonth(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must use the YYYY-MM format"})
+			return
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	if months > maxAnalyticsMonths {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The period can span at most " + strconv.Itoa(maxAnalyticsMonths) + " months"})
+		return
+	}
+
+	churnMonths := defaultChurnMonths
+	if value := c.Query("churn_months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxChurnMonths {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "churn_months must be between 1 and " + strconv.Itoa(maxChurnMonths)})
+			return
+		}
+		churnMonths = parsed
+	}
+
+	// customer yang dihitung: sesuai access policy dan filter
+	base := policy.Filter(config.DB.Model(&entity.Customer{}).Select("id", "created_at", "status"), "id")
+	if accountManagerID := c.Query("account_manager_id"); accountManagerID != "" {
+		base = base.Where("account_manager_id = ?", accountManagerID)
+	}
+	if category := c.Query("category"); category != "" {
+		base = base.Where("category = ?", category)
+	}
+	if groupID := c.Query("group_id"); groupID != "" {
+		groupIDs, err := groupSubtreeIDs(config.DB, groupID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group subtree"})
+			return
+		}
+		if len(groupIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Group not found"})
+			return
+		}
+		base = base.Where("id IN (SELECT customer_id FROM customer_groups WHERE group_id IN ?)", groupIDs)
+	}
+
+	// satu bulan sebelum from ikut dihitung sebagai pembanding growth dan churn bulan pertama
+	seriesStart := from.AddDate(0, -1, 0)
+	seriesEnd := to.AddDate(0, 1, 0)
+	touchStart := seriesStart.AddDate(0, -(churnMonths - 1), 0)
+	touchEnd := seriesEnd
+	if now.Before(touchEnd) {
+		touchEnd = now
+	}
+
+	var rows []customerAnalyticsRow
+	err := config.DB.Raw(customerAnalyticsSQL,
+		seriesStart.Format("2006-01-02"), to.Format("2006-01-02"),
+		base,
+		touchStart, touchEnd,
+		touchStart, touchEnd,
+		churnMonths-1,
+		churnMonths,
+	).Scan(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute customer statistics"})
+		return
+	}
+
+	byMonth := make(map[string]customerAnalyticsRow, len(rows))
+	for _, row := range rows {
+		byMonth[row.MonthStart.Format("2006-01")] = row
+	}
+
+	response := dto.CustomerAnalyticsResponse{
+		From:        from.Format("2006-01"),
+		To:          to.Format("2006-01"),
+		ChurnMonths: churnMonths,
+		Series:      make([]dto.CustomerAnalyticsPoint, 0, months),
+	}
+	previous := byMonth[seriesStart.Format("2006-01")]
+	response.Summary.ActiveStart = previous.Active
+	var churnRates float64
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		// bulan tanpa customer tidak muncul di hasil query, nilainya nol
+		row := byMonth[month.Format("2006-01")]
+		point := dto.CustomerAnalyticsPoint{
+			Month:    month.Format("2006-01"),
+			Total:    row.Total,
+			New:      row.New,
+			Active:   row.Active,
+			Blocked:  row.Blocked,
+			Inactive: row.Inactive,
+			Churned:  row.Churned,
+			Growth: dto.CustomerAnalyticsGrowth{
+				Total:   calcGrowth(float64(row.Total), float64(previous.Total)),
+				New:     calcGrowth(float64(row.New), float64(previous.New)),
+				Active:  calcGrowth(float64(row.Active), float64(previous.Active)),
+				Blocked: calcGrowth(float64(row.Blocked), float64(previous.Blocked)),
+				Churned: calcGrowth(float64(row.Churned), float64(previous.Churned)),
+			},
+		}
+		if previous.Active > 0 {
+			point.ChurnRate = float64(row.Churned) / float64(previous.Active) * 100
+		}
+		churnRates += point.ChurnRate
+
+		response.Series = append(response.Series, point)
+		response.Summary.New += row.New
+		response.Summary.Churned += row.Churned
+		previous = row
+	}
+	response.Summary.ActiveEnd = previous.Active
+	response.Summary.BlockedEnd = previous.Blocked
+	response.Summary.TotalEnd = previous.Total
+	response.Summary.ActiveGrowth = calcGrowth(float64(response.Summary.ActiveEnd), float64(response.Summary.ActiveStart))
+	response.Summary.AvgChurnRate = churnRates / float64(months)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Customer statistics fetched successfully",
+		"data":    response,
+	})
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCustomerDepth membatasi penelusuran ke atas jika data lama mengandung siklus
+const maxCustomerDepth = 50
+
+// customerSubtreeIDs mengembalikan id customer beserta seluruh anak perusahaannya
+func customerSubtreeIDs(db *gorm.DB, rootID string) ([]string, error) {
+	var ids []string
+	err := db.Raw(`WITH RECURSIVE subtree AS (
+			SELECT id FROM customers WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM customers c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+		) SELECT id FROM subtree`, rootID).Scan(&ids).Error
+	return ids, err
+}
+
+// customerAncestorIDs mengembalikan id dari induk teratas sampai customer itu sendiri
+func customerAncestorIDs(db *gorm.DB, id string) ([]string, error) {
+	var rows []struct {
+		ID    string
+		Depth int
+	}
+	err := db.Raw(`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM customers WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT c.id, c.parent_id, a.depth + 1 FROM customers c JOIN ancestors a ON c.id = a.parent_id
+			WHERE c.deleted_at IS NULL AND a.depth < ?
+		) SELECT id, depth FROM ancestors ORDER BY depth DESC`, id, maxCustomerDepth).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	path := make([]string, 0, len(rows))
+	seen := map[string]bool{}
+	for i := len(rows) - 1; i >= 0; i-- {
+		if seen[rows[i].ID] {
+			break
+		}
+		seen[rows[i].ID] = true
+		path = append([]string{rows[i].ID}, path...)
+	}
+	return path, nil
+}
+
+// validateCustomerParent memastikan induk ada dan bukan customer itu sendiri atau anak perusahaannya
+func validateCustomerParent(db *gorm.DB, customerID string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+	var parent entity.Customer
+	if err := db.Where("id = ?", *parentID).First(&parent).Error; err != nil {
+		return fmt.Errorf("parent customer not found")
+	}
+	if customerID == "" {
+		return nil
+	}
+	descendants, err := customerSubtreeIDs(db, customerID)
+	if err != nil {
+		return err
+	}
+	for _, id := range descendants {
+		if id == *parentID {
+			return fmt.Errorf("parent_id cannot be the customer itself or one of its subsidiaries")
+		}
+	}
+	return nil
+}
+
+// loadHierarchyMetrics menghitung aktivitas dan invoice per customer
+func loadHierarchyMetrics(db *gorm.DB, ids []string) (map[string]dto.HierarchyMetrics, error) {
+	metrics := make(map[string]dto.HierarchyMetrics, len(ids))
+	if len(ids) == 0 {
+		return metrics, nil
+	}
+	now := time.Now()
+
+	var activities []struct {
+		CustomerID     string
+		Activities     int64
+		Upcoming       int64
+		LastActivityAt *time.Time
+	}
+	err := db.Table("activities").
+		Select("customer_id, COUNT(*) AS activities, COUNT(*) FILTER (WHERE start_time > ?) AS upcoming, MAX(start_time) FILTER (WHERE start_time <= ?) AS last_activity_at", now, now).
+		Where("deleted_at IS NULL AND customer_id IN ?", ids).
+		Group("customer_id").Scan(&activities).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range activities {
+		m := metrics[row.CustomerID]
+		m.Activities = row.Activities
+		m.UpcomingActivities = row.Upcoming
+		m.LastActivityAt = row.LastActivityAt
+		metrics[row.CustomerID] = m
+	}
+
+	var invoices []struct {
+		CustomerID  string
+		Invoices    int64
+		Amount      float64
+		Paid        float64
+		Outstanding float64
+		Overdue     float64
+	}
+	err = db.Table("invoices").
+		Select(`customer_id, COUNT(*) AS invoices, COALESCE(SUM(amount), 0) AS amount, COALESCE(SUM(paid_amount), 0) AS paid,
+			COALESCE(SUM(GREATEST(amount - paid_amount, 0)), 0) AS outstanding,
+			COALESCE(SUM(CASE WHEN due_date < ? THEN GREATEST(amount - paid_amount, 0) ELSE 0 END), 0) AS overdue`, now).
+		Where("deleted_at IS NULL AND customer_id IN ?", ids).
+		Group("customer_id").Scan(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range invoices {
+		m := metrics[row.CustomerID]
+		m.Invoices = row.Invoices
+		m.InvoicedAmount = row.Amount
+		m.PaidAmount = row.Paid
+		m.OutstandingBalance = row.Outstanding
+		m.OverdueBalance = row.Overdue
+		metrics[row.CustomerID] = m
+	}
+	return metrics, nil
+}
+
+// addHierarchyMetrics menjumlahkan metrik anak ke rollup induk
+func addHierarchyMetrics(total *dto.HierarchyMetrics, m dto.HierarchyMetrics) {
+	total.Customers += m.Customers
+	total.Activities += m.Activities
+	total.UpcomingActivities += m.UpcomingActivities
+	if m.LastActivityAt != nil && (total.LastActivityAt == nil || m.LastActivityAt.After(*total.LastActivityAt)) {
+		total.LastActivityAt = m.LastActivityAt
+	}
+	total.Invoices += m.Invoices
+	total.InvoicedAmount += m.InvoicedAmount
+	total.PaidAmount += m.PaidAmount
+	total.OutstandingBalance += m.OutstandingBalance
+	total.OverdueBalance += m.OverdueBalance
+}
+
+// @Summary Get customer hierarchy
+// @Description Get the corporate tree (parent company and subsidiaries) around a customer. Every node has its own activity and invoice figures and a roll-up over its subsidiaries. Customers hidden by the access policy appear as restricted nodes without data.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param scope query string false "full (default, tree from the top parent) or subtree (tree from this customer)"
+// @Success 200 {object} dto.CustomerHierarchyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/hierarchy [get]
+func GetCustomerHierarchy(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	path := []string{customer.ID}
+	switch c.DefaultQuery("scope", "full") {
+	case "full":
+		ancestors, err := customerAncestorIDs(config.DB, customer.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch parent companies"})
+			return
+		}
+		if len(ancestors) > 0 {
+			path = ancestors
+		}
+	case "subtree":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope must be full or subtree"})
+		return
+	}
+
+	ids, err := customerSubtreeIDs(config.DB, path[0])
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subsidiaries"})
+		return
+	}
+	var members []entity.Customer
+	if err := config.DB.Select("id", "name", "code", "status", "parent_id").Where("id IN ?", ids).Order("name").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subsidiaries"})
+		return
+	}
+
+	var visibleIDs []string
+	if err := policy.Filter(config.DB.Model(&entity.Customer{}).Where("id IN ?", ids), "id").Pluck("id", &visibleIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve access policy"})
+		return
+	}
+	visible := make(map[string]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+	metrics, err := loadHierarchyMetrics(config.DB, visibleIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute hierarchy roll-ups"})
+		return
+	}
+
+	byID := make(map[string]entity.Customer, len(members))
+	children := map[string][]entity.Customer{}
+	for _, member := range members {
+		byID[member.ID] = member
+		if member.ParentID != nil {
+			children[*member.ParentID] = append(children[*member.ParentID], member)
+		}
+	}
+
+	var build func(member entity.Customer, depth int, visiting map[string]bool) dto.CustomerHierarchyNode
+	build = func(member entity.Customer, depth int, visiting map[string]bool) dto.CustomerHierarchyNode {
+		visiting[member.ID] = true
+		defer delete(visiting, member.ID)
+
+		node := dto.CustomerHierarchyNode{
+			ID:       member.ID,
+			ParentID: member.ParentID,
+			Depth:    depth,
+			Children: []dto.CustomerHierarchyNode{},
+		}
+		if visible[member.ID] {
+			node.Name = member.Name
+			node.Code = member.Code
+			node.Status = member.Status
+			node.Own = metrics[member.ID]
+			node.Own.Customers = 1
+		} else {
+			node.Restricted = true
+		}
+		node.Rollup = node.Own
+
+		for _, child := range children[member.ID] {
+			if visiting[child.ID] {
+				continue
+			}
+			childNode := build(child, depth+1, visiting)
+			addHierarchyMetrics(&node.Rollup, childNode.Rollup)
+			node.Children = append(node.Children, childNode)
+		}
+		return node
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Customer hierarchy fetched successfully",
+		"data": dto.CustomerHierarchyResponse{
+			CustomerID: customer.ID,
+			Path:       path,
+			Tree:       build(byID[path[0]], 0, map[string]bool{}),
+		},
+	})
+}
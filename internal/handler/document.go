@@ -0,0 +1,705 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Kategori dokumen yang diperbolehkan
+var documentCategories = []string{
+	"contract",
+	"npwp",
+	"siup",
+	"nib",
+	"nda",
+	"akta",
+	"invoice",
+	"status_change",
+	"other",
+}
+
+// Tipe file hasil sniffing isi file (bukan dari ekstensi) yang boleh diupload
+var allowedDocumentMimeTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/png",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+const defaultDocumentMaxSizeMB = 10
+
+// documentStorageDir berada di luar folder uploads yang disajikan publik;
+// file dokumen hanya bisa diunduh lewat GET /api/documents/:id/download
+const documentStorageDir = "storage/documents"
+
+// legacyDocumentDir adalah lokasi lama dokumen di bawah folder uploads publik
+const legacyDocumentDir = "uploads/documents/"
+
+func isValidDocumentCategory(category string) bool {
+	for _, c := range documentCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// batas ukuran upload, bisa diatur lewat DOCUMENT_MAX_SIZE_MB
+func documentMaxSize() int64 {
+	if v, err := strconv.Atoi(os.Getenv("DOCUMENT_MAX_SIZE_MB")); err == nil && v > 0 {
+		return int64(v) << 20
+	}
+	return defaultDocumentMaxSizeMB << 20
+}
+
+// sniffDocumentFile mendeteksi MIME type dari isi file dan menolak tipe yang tidak diizinkan
+func sniffDocumentFile(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	mtype, err := mimetype.DetectReader(src)
+	if err != nil {
+		return "", err
+	}
+
+	for _, allowed := range allowedDocumentMimeTypes {
+		if mtype.Is(allowed) {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("file type %s is not allowed", mtype.String())
+}
+
+// saveDocumentFile memvalidasi ukuran dan isi file lalu menyimpannya ke storage/documents
+func saveDocumentFile(c *gin.Context, customerID string, file *multipart.FileHeader) (string, string, int, error) {
+	if file.Size > documentMaxSize() {
+		return "", "", http.StatusRequestEntityTooLarge, fmt.Errorf("file size exceeds the %d MB limit", documentMaxSize()>>20)
+	}
+
+	mimeType, err := sniffDocumentFile(file)
+	if err != nil {
+		return "", "", http.StatusBadRequest, err
+	}
+
+	name := strings.ReplaceAll(filepath.Base(file.Filename), " ", "_")
+	filePath := fmt.Sprintf("%s/%s_%s_%s", documentStorageDir, customerID, time.Now().Format("20060102150405"), name)
+	if err := c.SaveUploadedFile(file, filePath); err != nil {
+		return "", "", http.StatusInternalServerError, fmt.Errorf("failed to save file")
+	}
+
+	return filePath, mimeType, http.StatusOK, nil
+}
+
+// removeDocumentFiles menghapus file dokumen dari disk, hanya jika berada di folder penyimpanan dokumen
+func removeDocumentFiles(paths []string) {
+	for _, path := range paths {
+		clean := filepath.ToSlash(filepath.Clean(path))
+		if strings.HasPrefix(clean, documentStorageDir+"/") || strings.HasPrefix(clean, legacyDocumentDir) {
+			os.Remove(clean)
+		}
+	}
+}
+
+// MoveLegacyDocumentFiles memindahkan dokumen lama dari uploads/documents ke storage/documents
+// agar tidak lagi bisa diakses lewat static file server tanpa login
+func MoveLegacyDocumentFiles() {
+	var documents []entity.Document
+	if err := config.DB.Unscoped().Where("url_file LIKE ?", legacyDocumentDir+"%").Find(&documents).Error; err != nil {
+		log.Println("Failed to load legacy documents:", err)
+		return
+	}
+	if len(documents) == 0 {
+		return
+	}
+	if err := os.MkdirAll(documentStorageDir, 0750); err != nil {
+		log.Println("Failed to create document storage:", err)
+		return
+	}
+	for _, document := range documents {
+		target := documentStorageDir + "/" + strings.TrimPrefix(document.URLFile, legacyDocumentDir)
+		if err := os.Rename(document.URLFile, target); err != nil && !os.IsNotExist(err) {
+			log.Println("Failed to move document", document.ID, ":", err)
+			continue
+		}
+		config.DB.Unscoped().Model(&entity.Document{}).Where("id = ?", document.ID).Update("url_file", target)
+	}
+}
+
+// parseDocumentDate menerima format tanggal (2006-01-02) atau RFC3339
+func parseDocumentDate(value string) (*time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD or RFC3339")
+	}
+	return &t, nil
+}
+
+func convertToDocumentResponse(document entity.Document) dto.DocumentResponse {
+	return dto.DocumentResponse{
+		ID:         document.ID,
+		CustomerID: document.CustomerID,
+		Category:   document.Category,
+		Type:       document.Type,
+		Notes:      document.Notes,
+		URLFile:    "/api/documents/" + document.ID + "/download",
+		FileName:   document.FileName,
+		MimeType:   document.MimeType,
+		Size:       document.Size,
+		RootID:     document.RootID,
+		Version:    document.Version,
+		IsLatest:   document.IsLatest,
+		ExpiresAt:  document.ExpiresAt,
+		UserID:     document.UserID,
+		CreatedAt:  document.CreatedAt,
+		UpdatedAt:  document.UpdatedAt,
+	}
+}
+
+// @Summary Get customer documents
+// @Description Get the latest version of every document for a customer
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param category query string false "Filter by category"
+// @Success 200 {array} dto.DocumentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/documents [get]
+func GetCustomerDocuments(c *gin.Context) {
+	customerID := c.Param("id")
+	category := c.Query("category")
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	query := config.DB.Where("customer_id = ? AND is_latest = ?", customer.ID, true)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var documents []entity.Document
+	if err := query.Order("created_at DESC").Find(&documents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch documents"})
+		return
+	}
+
+	responses := []dto.DocumentResponse{}
+	for _, document := range documents {
+		responses = append(responses, convertToDocumentResponse(document))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Documents fetched successfully",
+		"data":    responses,
+	})
+}
+
+// @Summary Upload customer document
+// @Description Upload a new document for a customer. The file content is sniffed and must be PDF, image, Word or Excel.
+// @Tags Documents
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param file formData file true "Document file"
+// @Param category formData string true "Document category (contract, npwp, siup, nib, nda, akta, invoice, status_change, other)"
+// @Param notes formData string false "Notes"
+// @Param expires_at formData string false "Expiry date (YYYY-MM-DD)"
+// @Success 201 {object} dto.DocumentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/documents [post]
+func UploadCustomerDocument(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	customerID := c.Param("id")
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	category := c.PostForm("category")
+	if !isValidDocumentCategory(category) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid document category",
+			"categories": documentCategories,
+		})
+		return
+	}
+
+	var expiresAt *time.Time
+	if value := c.PostForm("expires_at"); value != "" {
+		parsed, err := parseDocumentDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at: " + err.Error()})
+			return
+		}
+		expiresAt = parsed
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+
+	filePath, mimeType, status, err := saveDocumentFile(c, customer.ID, file)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	document := entity.Document{
+		CustomerID: customer.ID,
+		UserID:     userID,
+		Notes:      c.PostForm("notes"),
+		Type:       "Upload",
+		Category:   category,
+		URLFile:    filePath,
+		FileName:   filepath.Base(file.Filename),
+		MimeType:   mimeType,
+		Size:       file.Size,
+		Version:    1,
+		IsLatest:   true,
+		ExpiresAt:  expiresAt,
+	}
+	if err := config.DB.Create(&document).Error; err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save document"})
+		return
+	}
+
+	// Insert HistoryCustomer
+	history := entity.HistoryCustomer{
+		CustomerID: customer.ID,
+		UserID:     userID,
+		Status:     "Document Uploaded",
+		Notes:      "Uploaded " + category + " document " + document.FileName,
+	}
+	config.DB.Create(&history)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Document uploaded successfully",
+		"data":    convertToDocumentResponse(document),
+	})
+}
+
+// @Summary Get document by ID
+// @Description Get a specific document version by ID
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Success 200 {object} dto.DocumentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/documents/{id} [get]
+func GetDocument(c *gin.Context) {
+	id := c.Param("id")
+
+	var document entity.Document
+	if err := config.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Document fetched successfully",
+		"data":    convertToDocumentResponse(document),
+	})
+}
+
+// @Summary Download document
+// @Description Download the file of a specific document version
+// @Tags Documents
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Success 200 {file} file
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/documents/{id}/download [get]
+func DownloadDocument(c *gin.Context) {
+	var document entity.Document
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	if _, err := os.Stat(document.URLFile); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document file not found"})
+		return
+	}
+
+	if document.MimeType != "" {
+		c.Header("Content-Type", document.MimeType)
+	}
+	c.FileAttachment(document.URLFile, document.FileName)
+}
+
+// @Summary Get document versions
+// @Description Get the full version history of a document, newest first
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID (any version)"
+// @Success 200 {array} dto.DocumentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/documents/{id}/versions [get]
+func GetDocumentVersions(c *gin.Context) {
+	id := c.Param("id")
+
+	var document entity.Document
+	if err := config.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	var versions []entity.Document
+	if err := config.DB.Where("root_id = ?", document.RootID).Order("version DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch document versions"})
+		return
+	}
+
+	responses := []dto.DocumentResponse{}
+	for _, version := range versions {
+		responses = append(responses, convertToDocumentResponse(version))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Document versions fetched successfully",
+		"data":    responses,
+	})
+}
+
+// @Summary Replace document
+// @Description Upload a new version of a document. The previous version is kept in the history.
+// @Tags Documents
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID (any version)"
+// @Param file formData file true "Document file"
+// @Param notes formData string false "Notes (defaults to previous version)"
+// @Param expires_at formData string false "Expiry date (defaults to previous version)"
+// @Success 201 {object} dto.DocumentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Router /api/documents/{id}/versions [post]
+func ReplaceDocument(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id := c.Param("id")
+	var document entity.Document
+	if err := config.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	// selalu ganti dari versi terakhir
+	var latest entity.Document
+	if err := config.DB.Where("root_id = ? AND is_latest = ?", document.RootID, true).First(&latest).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Latest document version not found"})
+		return
+	}
+
+	expiresAt := latest.ExpiresAt
+	if value := c.PostForm("expires_at"); value != "" {
+		parsed, err := parseDocumentDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at: " + err.Error()})
+			return
+		}
+		expiresAt = parsed
+	}
+
+	notes := latest.Notes
+	if value, ok := c.GetPostForm("notes"); ok {
+		notes = value
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+
+	filePath, mimeType, status, err := saveDocumentFile(c, latest.CustomerID, file)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	newVersion := entity.Document{
+		CustomerID: latest.CustomerID,
+		UserID:     userID,
+		Notes:      notes,
+		Type:       latest.Type,
+		Category:   latest.Category,
+		URLFile:    filePath,
+		FileName:   filepath.Base(file.Filename),
+		MimeType:   mimeType,
+		Size:       file.Size,
+		RootID:     latest.RootID,
+		Version:    latest.Version + 1,
+		IsLatest:   true,
+		ExpiresAt:  expiresAt,
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Document{}).Where("id = ?", latest.ID).Update("is_latest", false).Error; err != nil {
+			return err
+		}
+		return tx.Create(&newVersion).Error
+	})
+	if err != nil {
+		os.Remove(filePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save document version"})
+		return
+	}
+
+	// Insert HistoryCustomer
+	history := entity.HistoryCustomer{
+		CustomerID: latest.CustomerID,
+		UserID:     userID,
+		Status:     "Document Replaced",
+		Notes:      fmt.Sprintf("Replaced %s document with version %d", latest.Category, newVersion.Version),
+	}
+	config.DB.Create(&history)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Document replaced successfully",
+		"data":    convertToDocumentResponse(newVersion),
+	})
+}
+
+// @Summary Update document metadata
+// @Description Update category, notes or expiry date of the latest document version
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Param document body dto.UpdateDocumentRequest true "Document metadata"
+// @Success 200 {object} dto.DocumentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/documents/{id} [put]
+func UpdateDocument(c *gin.Context) {
+	id := c.Param("id")
+
+	var document entity.Document
+	if err := config.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	var req dto.UpdateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Category != nil {
+		if !isValidDocumentCategory(*req.Category) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid document category",
+				"categories": documentCategories,
+			})
+			return
+		}
+		updates["category"] = *req.Category
+	}
+	if req.ExpiresAt != nil {
+		if *req.ExpiresAt == "" {
+			updates["expires_at"] = nil
+		} else {
+			parsed, err := parseDocumentDate(*req.ExpiresAt)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at: " + err.Error()})
+				return
+			}
+			updates["expires_at"] = *parsed
+		}
+	}
+
+	// semua input sudah valid: catatan hanya untuk versi ini, kategori dan tanggal kadaluarsa
+	// berlaku untuk semua versi dokumen
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Notes != nil {
+			if err := tx.Model(&document).Update("notes", *req.Notes).Error; err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			return tx.Model(&entity.Document{}).Where("root_id = ?", document.RootID).Updates(updates).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update document"})
+		return
+	}
+
+	config.DB.Where("id = ?", document.ID).First(&document)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Document updated successfully",
+		"data":    convertToDocumentResponse(document),
+	})
+}
+
+// @Summary Delete document
+// @Description Delete a document together with all of its versions
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID (any version)"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/documents/{id} [delete]
+func DeleteDocument(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id := c.Param("id")
+	var document entity.Document
+	if err := config.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	var filePaths []string
+	if err := config.DB.Model(&entity.Document{}).Where("root_id = ?", document.RootID).Pluck("url_file", &filePaths).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+		return
+	}
+	result := config.DB.Where("root_id = ?", document.RootID).Delete(&entity.Document{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+		return
+	}
+	removeDocumentFiles(filePaths)
+
+	// Insert HistoryCustomer
+	history := entity.HistoryCustomer{
+		CustomerID: document.CustomerID,
+		UserID:     userID,
+		Status:     "Document Deleted",
+		Notes:      "Deleted " + document.Category + " document " + document.FileName,
+	}
+	config.DB.Create(&history)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Document deleted successfully",
+		"versions_deleted": result.RowsAffected,
+	})
+}
+
+// @Summary Get expiring documents
+// @Description Get latest document versions that expire within the next N days
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Window in days (default 30)"
+// @Param customer_id query string false "Filter by customer"
+// @Param category query string false "Filter by category"
+// @Param include_expired query bool false "Include documents that already expired"
+// @Success 200 {array} dto.DocumentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/documents/expiring [get]
+func GetExpiringDocuments(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter"})
+		return
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	query := policy.Filter(config.DB, "customer_id").Where("is_latest = ? AND expires_at IS NOT NULL AND expires_at <= ?", true, now.AddDate(0, 0, days))
+	if includeExpired, _ := strconv.ParseBool(c.Query("include_expired")); !includeExpired {
+		query = query.Where("expires_at >= ?", now)
+	}
+	if customerID := c.Query("customer_id"); customerID != "" {
+		query = query.Where("customer_id = ?", customerID)
+	}
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var documents []entity.Document
+	if err := query.Order("expires_at ASC").Find(&documents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch expiring documents"})
+		return
+	}
+
+	responses := []dto.DocumentResponse{}
+	for _, document := range documents {
+		responses = append(responses, convertToDocumentResponse(document))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Expiring documents fetched successfully",
+		"data":    responses,
+	})
+}
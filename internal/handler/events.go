@@ -37,8 +37,12 @@ func ReadEvents(c *gin.Context) {
 
 	var events []entity.Event
 	offset := (page - 1) * limit
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
 
-	if result := config.DB.Limit(limit).Offset(offset).Find(&events); result.Error != nil {
+	if result := policy.Filter(config.DB, "customer_id").Limit(limit).Offset(offset).Find(&events); result.Error != nil {
 
 		c.JSON(http.StatusNotFound, dto.Response{
 			Status:  http.StatusNotFound,
@@ -86,6 +90,7 @@ func CreateEvents(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data event"})
 		return
 	}
+	refreshHealthScore(event.CustomerID, "event")
 
 	c.JSON(http.StatusCreated, event)
 }
@@ -151,6 +156,7 @@ func UpdateEvents(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data event"})
 		return
 	}
+	refreshHealthScore(event.CustomerID, "event")
 
 	c.JSON(http.StatusOK, event)
 }
@@ -183,6 +189,7 @@ func DeleteEvents(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus data event"})
 		return
 	}
+	refreshHealthScore(event.CustomerID, "event")
 
 	c.JSON(http.StatusNoContent, gin.H{})
 }
@@ -230,8 +237,12 @@ func GetCustomerEvents(c *gin.Context) {
 func GetEventType(c *gin.Context) {
 	eventType := c.Query("type")
 	var events []entity.Event
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
 
-	if result := config.DB.Where("type = ?", eventType).Find(&events); result.Error != nil {
+	if result := policy.Filter(config.DB, "customer_id").Where("type = ?", eventType).Find(&events); result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "No events found for this type"})
 		} else {
@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	earthRadiusKm          = 6371.0
+	kmPerDegreeLatitude    = 111.32
+	defaultNearbyRadiusKm  = 10.0
+	maxNearbyRadiusKm      = 500.0
+	defaultNearbyLimit     = 50
+	maxNearbyLimit         = 500
+	addressCoordsMaxImport = 5 << 20 // 5 MB
+)
+
+// haversineKm menghitung jarak garis lurus (great-circle) antara dua titik dalam kilometer
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// validateCoordinates memastikan latitude dan longitude diisi berpasangan dan berada dalam rentang yang valid
+func validateCoordinates(lat, lng *float64) error {
+	if lat == nil && lng == nil {
+		return nil
+	}
+	if lat == nil || lng == nil {
+		return fmt.Errorf("latitude and longitude must be provided together")
+	}
+	if math.IsNaN(*lat) || *lat < -90 || *lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(*lng) || *lng < -180 || *lng > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+// parseCoordinate membaca satu nilai koordinat dari query/CSV; koma desimal juga diterima
+func parseCoordinate(value string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", "."), 64)
+}
+
+// roundKm membulatkan jarak ke dua angka desimal untuk response
+func roundKm(km float64) float64 {
+	return math.Round(km*100) / 100
+}
+
+// nearbyRow adalah satu alamat berkoordinat beserta data customer-nya
+type nearbyRow struct {
+	CustomerID         string
+	CustomerName       string
+	CustomerCode       string
+	Status             string
+	AccountManagerID   *string
+	AccountManagerName *string
+	AddressID          string
+	AddressName        string
+	Address            string
+	City               string
+	Latitude           float64
+	Longitude          float64
+}
+
+// @Summary Get nearby customers
+// @Description Get customers with an active address within radius_km of a point, sorted by distance. Each customer appears once, with its closest address.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lat query number true "Latitude" example(-6.2088)
+// @Param lng query number true "Longitude" example(106.8456)
+// @Param radius_km query number false "Search radius in km (default 10, max 500)"
+// @Param status query string false "Filter by customer status"
+// @Param group_id query string false "Filter by group ID"
+// @Param account_manager_id query string false "Filter by account manager ID"
+// @Param limit query int false "Max results (default 50, max 500)"
+// @Success 200 {array} dto.NearbyCustomer
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/nearby [get]
+func GetNearbyCustomers(c *gin.Context) {
+	lat, err := parseCoordinate(c.Query("lat"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lat parameter"})
+		return
+	}
+	lng, err := parseCoordinate(c.Query("lng"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lng parameter"})
+		return
+	}
+	if err := validateCoordinates(&lat, &lng); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	radius := defaultNearbyRadiusKm
+	if v := c.Query("radius_km"); v != "" {
+		radius, err = strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid radius_km parameter"})
+			return
+		}
+	}
+	if radius > maxNearbyRadiusKm {
+		radius = maxNearbyRadiusKm
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultNearbyLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	if limit > maxNearbyLimit {
+		limit = maxNearbyLimit
+	}
+
+	// Bounding box untuk menyaring kandidat di database, jarak persisnya dihitung di bawah
+	dLat := radius / kmPerDegreeLatitude
+	dLng := radius / (kmPerDegreeLatitude * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+
+	query := config.DB.Table("addresses").
+		Select("customers.id AS customer_id, customers.name AS customer_name, customers.code AS customer_code, "+
+			"customers.status, customers.account_manager_id, account_managers.manager_name AS account_manager_name, "+
+			"addresses.id AS address_id, addresses.name AS address_name, addresses.address, addresses.city, "+
+			"addresses.latitude, addresses.longitude").
+		Joins("JOIN customers ON customers.id = addresses.customer_id AND customers.deleted_at IS NULL").
+		Joins("LEFT JOIN account_managers ON account_managers.id = customers.account_manager_id").
+		Where("addresses.deleted_at IS NULL AND addresses.active = ?", true).
+		Where("addresses.latitude BETWEEN ? AND ?", lat-dLat, lat+dLat).
+		Where("addresses.longitude BETWEEN ? AND ?", lng-dLng, lng+dLng)
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("customers.status = ?", status)
+	}
+	if groupID := c.Query("group_id"); groupID != "" {
+		query = query.Where("customers.id IN (SELECT customer_id FROM customer_groups WHERE group_id = ?)", groupID)
+	}
+	if amID := c.Query("account_manager_id"); amID != "" {
+		query = query.Where("customers.account_manager_id = ?", amID)
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	query = policy.Filter(query, "customers.id")
+
+	var rows []nearbyRow
+	if err := query.Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch nearby customers"})
+		return
+	}
+
+	// Satu baris per customer: alamat terdekat
+	nearest := map[string]dto.NearbyCustomer{}
+	for _, row := range rows {
+		distance := haversineKm(lat, lng, row.Latitude, row.Longitude)
+		if distance > radius {
+			continue
+		}
+		if existing, ok := nearest[row.CustomerID]; ok && existing.DistanceKm <= distance {
+			continue
+		}
+		nearest[row.CustomerID] = dto.NearbyCustomer{
+			CustomerID:         row.CustomerID,
+			CustomerName:       row.CustomerName,
+			CustomerCode:       row.CustomerCode,
+			Status:             row.Status,
+			AccountManagerID:   row.AccountManagerID,
+			AccountManagerName: row.AccountManagerName,
+			AddressID:          row.AddressID,
+			AddressName:        row.AddressName,
+			Address:            row.Address,
+			City:               row.City,
+			Latitude:           row.Latitude,
+			Longitude:          row.Longitude,
+			DistanceKm:         distance,
+		}
+	}
+
+	results := make([]dto.NearbyCustomer, 0, len(nearest))
+	for _, item := range nearest {
+		results = append(results, item)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].DistanceKm != results[j].DistanceKm {
+			return results[i].DistanceKm < results[j].DistanceKm
+		}
+		return results[i].CustomerName < results[j].CustomerName
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].DistanceKm = roundKm(results[i].DistanceKm)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Nearby customers fetched successfully",
+		"data":    results,
+		"center":  gin.H{"latitude": lat, "longitude": lng},
+		"radius":  radius,
+	})
+}
+
+// @Summary Set address coordinates
+// @Description Set the latitude/longitude of an address. Send both as null to clear them.
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Address ID"
+// @Param coordinates body dto.AddressCoordinatesRequest true "Coordinates"
+// @Success 200 {object} entity.Address
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/addresses/{id}/coordinates [put]
+func UpdateAddressCoordinates(c *gin.Context) {
+	var address entity.Address
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&address).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Address not found"})
+		return
+	}
+
+	var req dto.AddressCoordinatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := config.DB.Model(&address).Updates(map[string]interface{}{
+		"latitude":  req.Latitude,
+		"longitude": req.Longitude,
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update coordinates"})
+		return
+	}
+
+	address.Latitude, address.Longitude = req.Latitude, req.Longitude
+	c.JSON(http.StatusOK, address)
+}
+
+// @Summary Import address coordinates
+// @Description Bulk set address coordinates from CSV (field "file"). Header columns: latitude, longitude and either address_id or customer_code (+ optional address_name; without it the customer's main address is used).
+// @Tags Addresses
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Success 200 {object} dto.AddressCoordinatesImportResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/addresses/coordinates/import [post]
+func ImportAddressCoordinates(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	if file.Size > addressCoordsMaxImport {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File must not be larger than %d MB", addressCoordsMaxImport>>20)})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer src.Close()
+
+	reader := csv.NewReader(src)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header is required"})
+		return
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	_, hasLat := columns["latitude"]
+	_, hasLng := columns["longitude"]
+	_, hasAddressID := columns["address_id"]
+	_, hasCode := columns["customer_code"]
+	if !hasLat || !hasLng {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV must have latitude and longitude columns"})
+		return
+	}
+	if !hasAddressID && !hasCode {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV must have address_id or customer_code column"})
+		return
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	result := dto.AddressCoordinatesImportResult{Errors: []string{}}
+	writable := make(map[string]bool) // customer ID -> boleh diubah user
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		line := 1
+		for {
+			record, err := reader.Read()
+			line++
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				// baris rusak dilewati; error lain (gagal baca file) tidak akan pulih jadi import dihentikan
+				var parseErr *csv.ParseError
+				if !errors.As(err, &parseErr) {
+					return err
+				}
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+
+			lat, latErr := parseCoordinate(field(record, "latitude"))
+			lng, lngErr := parseCoordinate(field(record, "longitude"))
+			if latErr != nil || lngErr != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: invalid coordinates", line))
+				continue
+			}
+			if err := validateCoordinates(&lat, &lng); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+
+			address, err := resolveImportAddress(tx, field(record, "address_id"), field(record, "customer_code"), field(record, "address_name"))
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+			// alamat milik customer di luar scope user diperlakukan seperti tidak ada
+			if !canWriteCustomer(tx, policy, writable, address.CustomerID) {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: address not found", line))
+				continue
+			}
+
+			err = tx.Model(&address).Updates(map[string]interface{}{"latitude": lat, "longitude": lng}).Error
+			if err != nil {
+				return err
+			}
+			result.Updated++
+		}
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import coordinates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Coordinates imported",
+		"data":    result,
+	})
+}
+
+// resolveImportAddress mencari alamat dari address_id, atau dari customer_code + nama alamat
+// (tanpa nama alamat dipakai alamat utama customer)
+func resolveImportAddress(tx *gorm.DB, addressID, customerCode, addressName string) (entity.Address, error) {
+	var address entity.Address
+	if addressID != "" {
+		if err := tx.Where("id = ?", addressID).First(&address).Error; err != nil {
+			return address, fmt.Errorf("address '%s' not found", addressID)
+		}
+		return address, nil
+	}
+	if customerCode == "" {
+		return address, fmt.Errorf("address_id or customer_code is required")
+	}
+
+	var customer entity.Customer
+	if err := tx.Where("code = ?", customerCode).First(&customer).Error; err != nil {
+		return address, fmt.Errorf("customer '%s' not found", customerCode)
+	}
+
+	query := tx.Where("customer_id = ?", customer.ID)
+	if addressName != "" {
+		query = query.Where("LOWER(name) = LOWER(?)", addressName)
+	} else {
+		query = query.Order("main DESC").Order("created_at")
+	}
+	if err := query.First(&address).Error; err != nil {
+		if addressName != "" {
+			return address, fmt.Errorf("address '%s' not found for customer '%s'", addressName, customerCode)
+		}
+		return address, fmt.Errorf("customer '%s' has no address", customerCode)
+	}
+	return address, nil
+}
@@ -8,70 +8,70 @@ import (
 	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-type GroupInput struct {
-	NameGroup string `json:"name_group" binding:"required"`
-	Value     string `json:"value"`
-	Active    bool   `json:"active"`
+// validateGroupRequest memeriksa jenis dan parent group; groupID kosong berarti group baru
+func validateGroupRequest(groupID string, req *dto.GroupRequest) (int, string) {
+	if req.TypeID != nil && *req.TypeID == "" {
+		req.TypeID = nil
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+	if req.TypeID != nil {
+		groupType, err := findGroupType(config.DB, *req.TypeID)
+		if err != nil {
+			return http.StatusBadRequest, "Group type not found"
+		}
+		req.TypeID = &groupType.ID
+	}
+	if err := validateGroupParent(config.DB, groupID, req.ParentID); err != nil {
+		return http.StatusBadRequest, err.Error()
+	}
+	return 0, ""
 }
 
 // @Summary Create group
-// @Description Create a new group
+// @Description Create a new group with an optional type and parent group
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param group body dto.CreateGroupsRequest true "Group data"
+// @Param group body dto.GroupRequest true "Group data"
 // @Success 201 {object} entity.Group
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/groups [post]
 func CreateGroup(c *gin.Context) {
-	var req dto.CreateGroupsRequest
+	var req dto.GroupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Create Industry Group if provided
-	if req.IndustryID != "" {
-		industryGroup := entity.Group{
-			NameGroup: "Industry",
-			Value:     req.IndustryID,
-			Active:    req.IndustryActive,
-		}
-
-		result := config.DB.Create(&industryGroup)
-		if result.Error != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create industry group"})
-			return
-		}
-
-		c.JSON(http.StatusCreated, industryGroup)
+	if status, message := validateGroupRequest("", &req); status != 0 {
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
 
-	// Create Parent Group if provided
-	if req.ParentGroupID != "" {
-		parentGroup := entity.Group{
-			NameGroup: "Parent Group",
-			Value:     req.ParentGroupID,
-			Active:    req.ParentGroupActive,
-		}
-
-		result := config.DB.Create(&parentGroup)
-		if result.Error != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create parent group"})
-			return
-		}
+	group := entity.Group{
+		NameGroup: req.NameGroup,
+		Value:     req.Value,
+		TypeID:    req.TypeID,
+		ParentID:  req.ParentID,
+		Active:    req.Active == nil || *req.Active,
+	}
 
-		c.JSON(http.StatusCreated, parentGroup)
+	// Select agar Active=false tidak diganti default:true
+	result := config.DB.Select("ID", "NameGroup", "Value", "TypeID", "ParentID", "Active", "CreatedAt", "UpdatedAt").Create(&group)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create group"})
 		return
 	}
 
-	c.JSON(http.StatusBadRequest, gin.H{"error": "Either IndustryId or ParentGroupId must be provided"})
+	config.DB.Preload("Type").Preload("Parent").Where("id = ?", group.ID).First(&group)
+	c.JSON(http.StatusCreated, group)
 }
 
 // @Summary Get all groups
@@ -81,13 +81,16 @@ func CreateGroup(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param active query bool false "Filter by active status"
+// @Param type query string false "Filter by group type ID or code"
+// @Param parent_id query string false "Filter by parent group ID, or 'root' for top-level groups"
 // @Success 200 {array} entity.Group
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/groups [get]
 func GetGroups(c *gin.Context) {
 	var groups []entity.Group
-	query := config.DB
+	query := config.DB.Preload("Type")
 
 	// Filter by active status if provided
 	if activeParam := c.Query("active"); activeParam != "" {
@@ -95,8 +98,21 @@ func GetGroups(c *gin.Context) {
 			query = query.Where("active = ?", active)
 		}
 	}
+	if typeParam := c.Query("type"); typeParam != "" {
+		groupType, err := findGroupType(config.DB, typeParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown group type"})
+			return
+		}
+		query = query.Where("type_id = ?", groupType.ID)
+	}
+	if parentID := c.Query("parent_id"); parentID == "root" {
+		query = query.Where("parent_id IS NULL")
+	} else if parentID != "" {
+		query = query.Where("parent_id = ?", parentID)
+	}
 
-	if result := query.Find(&groups); result.Error != nil {
+	if result := query.Order("name_group").Find(&groups); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data groups"})
 		return
 	}
@@ -110,7 +126,7 @@ func GetGroups(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Group ID"
+// @Param id path string true "Group ID"
 // @Success 200 {object} entity.Group
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
@@ -119,7 +135,7 @@ func GetGroup(c *gin.Context) {
 	id := c.Param("id")
 	var group entity.Group
 
-	if result := config.DB.First(&group, id); result.Error != nil {
+	if result := config.DB.Preload("Type").Preload("Parent").Where("id = ?", id).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
 		return
 	}
@@ -128,32 +144,37 @@ func GetGroup(c *gin.Context) {
 }
 
 // @Summary Update group
-// @Description Update an existing group
+// @Description Update an existing group. Moving a group under one of its descendants is rejected, and so is switching to an exclusive type that members already have another group of.
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Group ID"
-// @Param group body GroupInput true "Group data"
+// @Param id path string true "Group ID"
+// @Param group body dto.GroupRequest true "Group data"
 // @Success 200 {object} entity.Group
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Router /api/groups/{id} [put]
 func UpdateGroup(c *gin.Context) {
 	id := c.Param("id")
 	var group entity.Group
 
-	if result := config.DB.First(&group, id); result.Error != nil {
+	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
 		return
 	}
 
-	var input GroupInput
+	var input dto.GroupRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if status, message := validateGroupRequest(group.ID, &input); status != 0 {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 
 	// Check if group name already exists (exclude current group)
 	var existingGroup entity.Group
@@ -162,12 +183,36 @@ func UpdateGroup(c *gin.Context) {
 		return
 	}
 
+	// Pindah ke jenis exclusive: anggota tidak boleh sudah punya group lain dengan jenis tersebut
+	if input.TypeID != nil && (group.TypeID == nil || *group.TypeID != *input.TypeID) {
+		var groupType entity.GroupType
+		config.DB.Where("id = ?", *input.TypeID).First(&groupType)
+		if groupType.Exclusive {
+			var conflicts int64
+			config.DB.Raw(`SELECT COUNT(DISTINCT member.customer_id) FROM customer_groups member
+				JOIN customer_groups other ON other.customer_id = member.customer_id AND other.group_id <> member.group_id
+				JOIN groups ON groups.id = other.group_id AND groups.deleted_at IS NULL
+				WHERE member.group_id = ? AND groups.type_id = ?`, group.ID, groupType.ID).Scan(&conflicts)
+			if conflicts > 0 {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":     "Some members already belong to another group of this exclusive type",
+					"conflicts": conflicts,
+				})
+				return
+			}
+		}
+	}
+
 	// Update group
 	group.NameGroup = input.NameGroup
 	group.Value = input.Value
-	group.Active = input.Active
+	group.TypeID = input.TypeID
+	group.ParentID = input.ParentID
+	if input.Active != nil {
+		group.Active = *input.Active
+	}
 
-	if result := config.DB.Save(&group); result.Error != nil {
+	if result := config.DB.Omit("Type", "Parent", "Customers").Save(&group); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate group"})
 		return
 	}
@@ -179,12 +224,12 @@ func UpdateGroup(c *gin.Context) {
 }
 
 // @Summary Delete group
-// @Description Delete a group by ID
+// @Description Delete a group by ID. Its child groups move up to the deleted group's parent.
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Group ID"
+// @Param id path string true "Group ID"
 // @Success 200 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
@@ -193,15 +238,23 @@ func DeleteGroup(c *gin.Context) {
 	id := c.Param("id")
 	var group entity.Group
 
-	if result := config.DB.First(&group, id); result.Error != nil {
+	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
 		return
 	}
 
-	// Remove all customer-group associations first
-	config.DB.Model(&group).Association("Customers").Clear()
-
-	if result := config.DB.Delete(&group); result.Error != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Anak group naik satu tingkat agar pohon tidak terputus
+		if err := tx.Model(&entity.Group{}).Where("parent_id = ?", group.ID).Update("parent_id", group.ParentID).Error; err != nil {
+			return err
+		}
+		// Remove all customer-group associations first
+		if err := tx.Model(&group).Association("Customers").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&group).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus group"})
 		return
 	}
@@ -210,38 +263,71 @@ func DeleteGroup(c *gin.Context) {
 }
 
 // @Summary Assign customer to group
-// @Description Assign a customer to a specific group
+// @Description Assign a customer to a specific group. A customer can belong to at most one group per exclusive type; use replace=true to move it out of the conflicting group.
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param group_id path int true "Group ID"
-// @Param customer_id path int true "Customer ID"
+// @Param id path string true "Group ID"
+// @Param customer_id path string true "Customer ID"
+// @Param replace query bool false "Remove the customer from its other group of the same exclusive type"
 // @Success 200 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
-// @Router /api/groups/{group_id}/customers/{customer_id} [post]
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/groups/{id}/customers/{customer_id} [put]
 func AssignCustomerToGroup(c *gin.Context) {
-	groupID := c.Param("group_id")
+	groupID := c.Param("id")
 	customerID := c.Param("customer_id")
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
 
 	var group entity.Group
-	if result := config.DB.First(&group, groupID); result.Error != nil {
+	if result := config.DB.Where("id = ?", groupID).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
 		return
 	}
 
 	var customer entity.Customer
-	if result := config.DB.First(&customer, customerID); result.Error != nil {
+	if result := config.DB.Where("id = ?", customerID).First(&customer); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer tidak ditemukan"})
+		return
+	}
+	if !policy.CanWrite(customer) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer tidak ditemukan"})
 		return
 	}
 
-	// Add customer to group
-	if err := config.DB.Model(&group).Association("Customers").Append(&customer); err != nil {
+	conflict, err := exclusiveGroupConflict(config.DB, customer.ID, group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa group customer"})
+		return
+	}
+	replace, _ := strconv.ParseBool(c.Query("replace"))
+	if conflict != nil && !replace {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Customer already belongs to another group of this exclusive type",
+			"conflict_group": conflict,
+		})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if conflict != nil {
+			if err := tx.Model(conflict).Association("Customers").Delete(&customer); err != nil {
+				return err
+			}
+		}
+		// Add customer to group
+		return tx.Model(&group).Association("Customers").Append(&customer)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menambahkan customer ke group"})
 		return
 	}
+	reassignTeamByAddress(customer.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Customer berhasil ditambahkan ke group"})
 }
@@ -252,24 +338,32 @@ func AssignCustomerToGroup(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param group_id path int true "Group ID"
-// @Param customer_id path int true "Customer ID"
+// @Param id path string true "Group ID"
+// @Param customer_id path string true "Customer ID"
 // @Success 200 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
-// @Router /api/groups/{group_id}/customers/{customer_id} [delete]
+// @Router /api/groups/{id}/customers/{customer_id} [delete]
 func RemoveCustomerFromGroup(c *gin.Context) {
-	groupID := c.Param("group_id")
+	groupID := c.Param("id")
 	customerID := c.Param("customer_id")
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
 
 	var group entity.Group
-	if result := config.DB.First(&group, groupID); result.Error != nil {
+	if result := config.DB.Where("id = ?", groupID).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
 		return
 	}
 
 	var customer entity.Customer
-	if result := config.DB.First(&customer, customerID); result.Error != nil {
+	if result := config.DB.Where("id = ?", customerID).First(&customer); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer tidak ditemukan"})
+		return
+	}
+	if !policy.CanWrite(customer) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer tidak ditemukan"})
 		return
 	}
@@ -279,17 +373,18 @@ func RemoveCustomerFromGroup(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus customer dari group"})
 		return
 	}
+	reassignTeamByAddress(customer.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Customer berhasil dihapus dari group"})
 }
 
 // @Summary Get group customers
-// @Description Get all customers in a specific group
+// @Description Get the customers that are direct members of a group (see /groups/{id}/subtree/customers for descendants)
 // @Tags Groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Group ID"
+// @Param id path string true "Group ID"
 // @Success 200 {array} entity.Customer
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
@@ -297,11 +392,17 @@ func RemoveCustomerFromGroup(c *gin.Context) {
 func GetGroupCustomers(c *gin.Context) {
 	id := c.Param("id")
 	var group entity.Group
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
 
-	if result := config.DB.Preload("Customers").First(&group, id); result.Error != nil {
+	if result := config.DB.Preload("Customers", func(db *gorm.DB) *gorm.DB {
+		return policy.Filter(db, "customers.id")
+	}).Where("id = ?", id).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
 		return
 	}
 
 	c.JSON(http.StatusOK, group.Customers)
-}
\ No newline at end of file
+}
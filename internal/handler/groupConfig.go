@@ -1,84 +1,108 @@
 package handler
 
 import (
-	"net/http"
 	"customer-api/internal/config"
+	"customer-api/internal/dto"
 	"customer-api/internal/entity"
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
-	"time"
 	"gorm.io/gorm"
 )
 
-
-
-
-type GroupConfig struct {
-	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	Name       string         `json:"name" gorm:"not null;unique"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+// findGroupConfig mengambil group config berdasarkan param :id, mengirim 404 jika tidak ada
+func findGroupConfig(c *gin.Context) (entity.GroupConfig, bool) {
+	var group entity.GroupConfig
+	if result := config.DB.Where("id = ?", c.Param("id")).First(&group); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+		return group, false
+	}
+	return group, true
 }
 
-type GroupConfigDetail struct {
-	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	GroupConfigID string         `json:"group_config_id" gorm:"not null"` // ULID string
-	Name       string         `json:"name" gorm:"not null;unique"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
-	// Relations - hilangkan dari JSON response
-	GroupConfig GroupConfig `json:"-" gorm:"foreignKey:GroupConfigID"`
+// findGroupConfigDetail mengambil bucket berdasarkan param :detail_id di dalam group config :id
+func findGroupConfigDetail(c *gin.Context) (entity.GroupConfigDetail, bool) {
+	var detail entity.GroupConfigDetail
+	if result := config.DB.Where("id = ? AND group_config_id = ?", c.Param("detail_id"), c.Param("id")).First(&detail); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Config group detail not found"})
+		return detail, false
+	}
+	return detail, true
 }
 
-
+// @Summary Create group config
+// @Description Create a segmentation config. field is a customer column (status, category, rating, average_cost, team_id, account_manager_id) or other:<custom field key>.
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group body dto.CreateGroupConfigRequest true "Group config"
+// @Success 201 {object} entity.GroupConfig
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs [post]
 func CreateConfigGroup(c *gin.Context) {
-	var input entity.GroupConfig
+	var input dto.CreateGroupConfigRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": err.Error(),
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
-
-	if result := config.DB.Create(&input); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "failed",
-			"message": "Gagal membuat config group",
-			"data":   nil,
+	input.Field = strings.TrimSpace(input.Field)
+	if _, _, err := resolveSegmentField(input.Field); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+			"data":    nil,
 		})
 		return
 	}
 
-	// insert group config
-
 	groupConfig := entity.GroupConfig{
-		Name: input.Name,
+		Name:     input.Name,
+		Field:    input.Field,
+		IsActive: true,
 	}
-	if result := config.DB.Create(&groupConfig); result.Error != nil {
+	if input.Active != nil {
+		groupConfig.IsActive = *input.Active
+	}
+	// Select agar IsActive=false tidak diganti default:true
+	if result := config.DB.Select("ID", "Name", "Field", "IsActive", "IsDeleted", "CreatedAt", "UpdatedAt").Create(&groupConfig); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal membuat group config",
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
-	
 
 	c.JSON(http.StatusCreated, gin.H{
 		"status":  "success",
 		"message": "Config group berhasil dibuat",
-		"data": groupConfig,
+		"data":    groupConfig,
 	})
 }
 
-
+// @Summary Get group configs
+// @Description Get all segmentation configs with their buckets
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.GroupConfig
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs [get]
 func GetConfigGroups(c *gin.Context) {
 	var groups []entity.GroupConfig
-	if result := config.DB.Find(&groups); result.Error != nil {
+	if result := config.DB.Preload("Details", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order, name")
+	}).Order("name").Find(&groups); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data config groups"})
 		return
 	}
@@ -90,10 +114,22 @@ func GetConfigGroups(c *gin.Context) {
 	})
 }
 
+// @Summary Get group config
+// @Description Get a segmentation config with its buckets
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Success 200 {object} entity.GroupConfig
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id} [get]
 func GetConfigGroup(c *gin.Context) {
-	id := c.Param("id")
 	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
+	if result := config.DB.Preload("Details", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order, name")
+	}).Where("id = ?", c.Param("id")).First(&group); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
 		return
 	}
@@ -104,33 +140,76 @@ func GetConfigGroup(c *gin.Context) {
 	})
 }
 
+// @Summary Update group config
+// @Description Update name, field or active flag of a segmentation config
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Param group body dto.UpdateGroupConfigRequest true "Group config"
+// @Success 200 {object} entity.GroupConfig
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id} [put]
 func UpdateConfigGroup(c *gin.Context) {
-	id := c.Param("id")
-	var input entity.GroupConfig
+	var input dto.UpdateGroupConfigRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": err.Error(),
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
 
-	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+	group, ok := findGroupConfig(c)
+	if !ok {
 		return
 	}
 
-	if input.Name != "" {
-		group.Name = input.Name
+	if input.Name != nil && strings.TrimSpace(*input.Name) != "" {
+		group.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Field != nil {
+		field := strings.TrimSpace(*input.Field)
+		numeric, _, err := resolveSegmentField(field)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+				"data":    nil,
+			})
+			return
+		}
+		// bucket rentang hanya berlaku untuk field angka
+		if !numeric {
+			var ranged int64
+			config.DB.Model(&entity.GroupConfigDetail{}).
+				Where("group_config_id = ? AND (min_value IS NOT NULL OR max_value IS NOT NULL)", group.ID).
+				Count(&ranged)
+			if ranged > 0 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"status":  "error",
+					"message": "Field is not numeric but some details use min_value/max_value ranges",
+					"data":    nil,
+				})
+				return
+			}
+		}
+		group.Field = field
+	}
+	if input.Active != nil {
+		group.IsActive = *input.Active
 	}
 
-	if result := config.DB.Save(&group); result.Error != nil {
+	if result := config.DB.Omit("Details").Save(&group); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal memperbarui config group",
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
@@ -141,19 +220,35 @@ func UpdateConfigGroup(c *gin.Context) {
 	})
 }
 
+// @Summary Delete group config
+// @Description Delete a segmentation config and its buckets
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id} [delete]
 func DeleteConfigGroup(c *gin.Context) {
-	id := c.Param("id")
-	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+	group, ok := findGroupConfig(c)
+	if !ok {
 		return
 	}
 
-	if result := config.DB.Delete(&group); result.Error != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_config_id = ?", group.ID).Delete(&entity.GroupConfigDetail{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&group).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal menghapus config group",
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
@@ -164,29 +259,63 @@ func DeleteConfigGroup(c *gin.Context) {
 	})
 }
 
+// @Summary Create group config detail
+// @Description Add a bucket to a segmentation config. A bucket matches customers whose field value is in values, or (numeric fields only) lies in [min_value, max_value).
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Param detail body dto.CreateGroupConfigDetailRequest true "Bucket"
+// @Success 201 {object} entity.GroupConfigDetail
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id}/details [post]
 func CreateConfigGroupDetail(c *gin.Context) {
-	var input entity.GroupConfigDetail
+	var input dto.CreateGroupConfigDetailRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": err.Error(),
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
 
-	// check if group config exists
-	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", input.GroupConfigID).First(&group); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Group config not found"})
+	group, ok := findGroupConfig(c)
+	if !ok {
+		return
+	}
+	numeric, _, err := resolveSegmentField(group.Field)
+	if err == nil {
+		err = validateSegmentBucket(numeric, input)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+			"data":    nil,
+		})
 		return
 	}
 
-	if result := config.DB.Create(&input); result.Error != nil {
+	detail := entity.GroupConfigDetail{
+		GroupConfigID: group.ID,
+		Name:          input.Name,
+		Icon:          input.Icon,
+		Values:        input.Values,
+		MinValue:      input.MinValue,
+		MaxValue:      input.MaxValue,
+		SortOrder:     input.SortOrder,
+		IsActive:      true,
+	}
+	if result := config.DB.Omit("GroupConfig").Create(&detail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal membuat config group detail",
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
@@ -194,18 +323,34 @@ func CreateConfigGroupDetail(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"status":  "success",
 		"message": "Config group detail berhasil dibuat",
-		"data": input,
+		"data":    detail,
 	})
 }
 
+// @Summary Get group config details
+// @Description Get the buckets of a segmentation config
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Success 200 {array} entity.GroupConfigDetail
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id}/details [get]
 func GetConfigGroupDetails(c *gin.Context) {
+	group, ok := findGroupConfig(c)
+	if !ok {
+		return
+	}
+
 	var details []entity.GroupConfigDetail
-	if result := config.DB.Find(&details); result.Error != nil {
+	if result := config.DB.Where("group_config_id = ?", group.ID).Order("sort_order, name").Find(&details); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data config group details"})
 		return
 	}
 
-
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Data config group details berhasil diambil",
@@ -213,11 +358,21 @@ func GetConfigGroupDetails(c *gin.Context) {
 	})
 }
 
+// @Summary Get group config detail
+// @Description Get one bucket of a segmentation config
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Param detail_id path string true "Detail ID"
+// @Success 200 {object} entity.GroupConfigDetail
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id}/details/{detail_id} [get]
 func GetConfigGroupDetail(c *gin.Context) {
-	id := c.Param("id")
-	var detail entity.GroupConfigDetail
-	if result := config.DB.Where("id = ?", id).First(&detail); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group detail not found"})
+	detail, ok := findGroupConfigDetail(c)
+	if !ok {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -227,33 +382,64 @@ func GetConfigGroupDetail(c *gin.Context) {
 	})
 }
 
+// @Summary Update group config detail
+// @Description Replace a bucket of a segmentation config
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Param detail_id path string true "Detail ID"
+// @Param detail body dto.CreateGroupConfigDetailRequest true "Bucket"
+// @Success 200 {object} entity.GroupConfigDetail
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id}/details/{detail_id} [put]
 func UpdateConfigGroupDetail(c *gin.Context) {
-	id := c.Param("id")
-	var input entity.GroupConfigDetail
+	var input dto.CreateGroupConfigDetailRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": err.Error(),
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
-	var detail entity.GroupConfigDetail
-	if result := config.DB.Where("id = ?", id).First(&detail); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group detail not found"})
+
+	group, ok := findGroupConfig(c)
+	if !ok {
+		return
+	}
+	detail, ok := findGroupConfigDetail(c)
+	if !ok {
 		return
 	}
-	if input.Name != "" {
-		detail.Name = input.Name
+	numeric, _, err := resolveSegmentField(group.Field)
+	if err == nil {
+		err = validateSegmentBucket(numeric, input)
 	}
-	if input.IsActive != detail.IsActive {
-		detail.IsActive = input.IsActive
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+			"data":    nil,
+		})
+		return
 	}
-	if result := config.DB.Save(&detail); result.Error != nil {
+
+	detail.Name = input.Name
+	detail.Icon = input.Icon
+	detail.Values = input.Values
+	detail.MinValue = input.MinValue
+	detail.MaxValue = input.MaxValue
+	detail.SortOrder = input.SortOrder
+	if result := config.DB.Omit("GroupConfig").Save(&detail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal memperbarui config group detail",
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
@@ -264,18 +450,29 @@ func UpdateConfigGroupDetail(c *gin.Context) {
 	})
 }
 
+// @Summary Delete group config detail
+// @Description Remove a bucket from a segmentation config
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Param detail_id path string true "Detail ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id}/details/{detail_id} [delete]
 func DeleteConfigGroupDetail(c *gin.Context) {
-	id := c.Param("id")
-	var detail entity.GroupConfigDetail
-	if result := config.DB.Where("id = ?", id).First(&detail); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group detail not found"})
+	detail, ok := findGroupConfigDetail(c)
+	if !ok {
 		return
 	}
 	if result := config.DB.Delete(&detail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal menghapus config group detail",
-			"data":   nil,
+			"data":    nil,
 		})
 		return
 	}
@@ -285,7 +482,3 @@ func DeleteConfigGroupDetail(c *gin.Context) {
 		"data":    nil,
 	})
 }
-
-
-
-
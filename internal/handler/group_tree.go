@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// groupSubtreeIDs mengembalikan id group beserta seluruh turunannya.
+// UNION (bukan UNION ALL) membuat query tetap berhenti walaupun data lama mengandung siklus.
+func groupSubtreeIDs(db *gorm.DB, rootID string) ([]string, error) {
+	var ids []string
+	err := db.Raw(`WITH RECURSIVE subtree AS (
+			SELECT id FROM groups WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT g.id FROM groups g JOIN subtree s ON g.parent_id = s.id WHERE g.deleted_at IS NULL
+		) SELECT id FROM subtree`, rootID).Scan(&ids).Error
+	return ids, err
+}
+
+// validateGroupParent memastikan parent ada dan bukan group itu sendiri atau turunannya
+func validateGroupParent(db *gorm.DB, groupID string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+	var parent entity.Group
+	if err := db.Where("id = ?", *parentID).First(&parent).Error; err != nil {
+		return fmt.Errorf("parent group not found")
+	}
+	if groupID == "" {
+		return nil
+	}
+	descendants, err := groupSubtreeIDs(db, groupID)
+	if err != nil {
+		return err
+	}
+	for _, id := range descendants {
+		if id == *parentID {
+			return fmt.Errorf("parent_id cannot be the group itself or one of its descendants")
+		}
+	}
+	return nil
+}
+
+// exclusiveGroupConflict mencari group lain milik customer dengan jenis exclusive yang sama
+func exclusiveGroupConflict(db *gorm.DB, customerID string, group entity.Group) (*entity.Group, error) {
+	if group.TypeID == nil {
+		return nil, nil
+	}
+	var groupType entity.GroupType
+	if err := db.Where("id = ?", *group.TypeID).First(&groupType).Error; err != nil {
+		return nil, err
+	}
+	if !groupType.Exclusive {
+		return nil, nil
+	}
+
+	var existing entity.Group
+	err := db.Joins("JOIN customer_groups ON customer_groups.group_id = groups.id").
+		Where("customer_groups.customer_id = ? AND groups.type_id = ? AND groups.id <> ?", customerID, groupType.ID, group.ID).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// findGroupType mencari jenis group berdasarkan id atau code
+func findGroupType(db *gorm.DB, idOrCode string) (entity.GroupType, error) {
+	var groupType entity.GroupType
+	err := db.Where("id = ? OR code = ?", idOrCode, idOrCode).First(&groupType).Error
+	return groupType, err
+}
+
+// groupMember adalah satu baris keanggotaan customer_groups beserta AverageCost customer
+type groupMember struct {
+	GroupID     string
+	CustomerID  string
+	AverageCost float64
+}
+
+// buildGroupNodes menyusun pohon group dari rootIDs dengan rollup customer unik per subtree.
+// Hanya customer yang terlihat oleh user (access policy) yang dihitung.
+func buildGroupNodes(db *gorm.DB, policy *access.Context, groups []entity.Group, rootIDs []string) ([]dto.GroupNode, error) {
+	byID := make(map[string]entity.Group, len(groups))
+	children := map[string][]entity.Group{}
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		byID[group.ID] = group
+		ids = append(ids, group.ID)
+		if group.ParentID != nil {
+			children[*group.ParentID] = append(children[*group.ParentID], group)
+		}
+	}
+	for parentID := range children {
+		sort.Slice(children[parentID], func(i, j int) bool {
+			return children[parentID][i].NameGroup < children[parentID][j].NameGroup
+		})
+	}
+
+	members := map[string][]string{}
+	revenue := map[string]float64{}
+	if len(ids) > 0 {
+		var rows []groupMember
+		query := db.Table("customer_groups").
+			Select("customer_groups.group_id, customer_groups.customer_id, customers.average_cost").
+			Joins("JOIN customers ON customers.id = customer_groups.customer_id AND customers.deleted_at IS NULL").
+			Where("customer_groups.group_id IN ?", ids)
+		if err := policy.Filter(query, "customers.id").Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			members[row.GroupID] = append(members[row.GroupID], row.CustomerID)
+			revenue[row.CustomerID] = row.AverageCost
+		}
+	}
+
+	var build func(group entity.Group, visiting map[string]bool) (dto.GroupNode, map[string]bool)
+	build = func(group entity.Group, visiting map[string]bool) (dto.GroupNode, map[string]bool) {
+		visiting[group.ID] = true
+		defer delete(visiting, group.ID)
+
+		node := dto.GroupNode{
+			ID:              group.ID,
+			NameGroup:       group.NameGroup,
+			Value:           group.Value,
+			TypeID:          group.TypeID,
+			ParentID:        group.ParentID,
+			Active:          group.Active,
+			DirectCustomers: int64(len(members[group.ID])),
+			Children:        []dto.GroupNode{},
+		}
+		if group.Type != nil {
+			node.TypeCode = group.Type.Code
+		}
+
+		customers := map[string]bool{}
+		for _, customerID := range members[group.ID] {
+			customers[customerID] = true
+		}
+		for _, child := range children[group.ID] {
+			if visiting[child.ID] {
+				continue
+			}
+			childNode, childCustomers := build(child, visiting)
+			node.Children = append(node.Children, childNode)
+			for customerID := range childCustomers {
+				customers[customerID] = true
+			}
+		}
+
+		node.TotalCustomers = int64(len(customers))
+		for customerID := range customers {
+			node.Revenue += revenue[customerID]
+		}
+		return node, customers
+	}
+
+	nodes := make([]dto.GroupNode, 0, len(rootIDs))
+	for _, id := range rootIDs {
+		if group, ok := byID[id]; ok {
+			node, _ := build(group, map[string]bool{})
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// @Summary Get group tree
+// @Description Get groups as a tree with customer counts and revenue (sum of AverageCost) rolled up over each subtree. With type, only groups of that type start a tree.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Group type ID or code of the root groups"
+// @Param active query bool false "Filter by active status"
+// @Success 200 {array} dto.GroupNode
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/groups/tree [get]
+func GetGroupTree(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Preload("Type")
+	if activeParam := c.Query("active"); activeParam != "" {
+		if active, err := strconv.ParseBool(activeParam); err == nil {
+			query = query.Where("active = ?", active)
+		}
+	}
+	var groups []entity.Group
+	if err := query.Order("name_group").Find(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	typeID := ""
+	if typeParam := c.Query("type"); typeParam != "" {
+		groupType, err := findGroupType(config.DB, typeParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown group type"})
+			return
+		}
+		typeID = groupType.ID
+	}
+
+	byID := make(map[string]entity.Group, len(groups))
+	for _, group := range groups {
+		byID[group.ID] = group
+	}
+	rootIDs := []string{}
+	for _, group := range groups {
+		parent, hasParent := entity.Group{}, false
+		if group.ParentID != nil {
+			parent, hasParent = byID[*group.ParentID]
+		}
+		if typeID == "" {
+			if !hasParent {
+				rootIDs = append(rootIDs, group.ID)
+			}
+			continue
+		}
+		// group dengan jenis yang diminta menjadi akar, kecuali induknya berjenis sama
+		if group.TypeID != nil && *group.TypeID == typeID && (!hasParent || parent.TypeID == nil || *parent.TypeID != typeID) {
+			rootIDs = append(rootIDs, group.ID)
+		}
+	}
+
+	nodes, err := buildGroupNodes(config.DB, policy, groups, rootIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute group rollups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group tree fetched successfully",
+		"data":    nodes,
+	})
+}
+
+// @Summary Get group subtree
+// @Description Get a group with all its descendants and customer counts and revenue rolled up over each subtree
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} dto.GroupNode
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/groups/{id}/subtree [get]
+func GetGroupSubtree(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	ids, err := groupSubtreeIDs(config.DB, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group subtree"})
+		return
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
+		return
+	}
+
+	var groups []entity.Group
+	if err := config.DB.Preload("Type").Where("id IN ?", ids).Find(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group subtree"})
+		return
+	}
+	nodes, err := buildGroupNodes(config.DB, policy, groups, []string{c.Param("id")})
+	if err != nil || len(nodes) == 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute group rollups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group subtree fetched successfully",
+		"data":    nodes[0],
+	})
+}
+
+// @Summary Get group subtree customers
+// @Description List distinct customers that belong to a group or any of its descendants, with the rolled-up count and revenue
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param status query string false "Filter by customer status"
+// @Success 200 {array} dto.CustomerListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/groups/{id}/subtree/customers [get]
+func GetGroupSubtreeCustomers(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	ids, err := groupSubtreeIDs(config.DB, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group subtree"})
+		return
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group tidak ditemukan"})
+		return
+	}
+
+	db := policy.Filter(config.DB.Preload("AccountManager"), "id").
+		Where("id IN (SELECT customer_id FROM customer_groups WHERE group_id IN ?)", ids)
+	if status := c.Query("status"); status != "" {
+		db = db.Where("status = ?", status)
+	}
+	var result []entity.Customer
+	if err := db.Order("name").Find(&result).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customers"})
+		return
+	}
+
+	customers := make([]dto.CustomerListResponse, 0, len(result))
+	var revenue float64
+	for _, customer := range result {
+		customers = append(customers, toCustomerListResponse(customer))
+		revenue += customer.AverageCost
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group customers fetched successfully",
+		"data":    customers,
+		"rollup": gin.H{
+			"group_count":     len(ids),
+			"total_customers": len(customers),
+			"revenue":         revenue,
+		},
+	})
+}
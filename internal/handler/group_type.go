@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var groupTypeCodeRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// exclusiveViolations menghitung customer yang sudah berada di lebih dari satu group dengan jenis ini
+func exclusiveViolations(db *gorm.DB, typeID string) int64 {
+	var count int64
+	db.Raw(`SELECT COUNT(*) FROM (
+			SELECT customer_groups.customer_id FROM customer_groups
+			JOIN groups ON groups.id = customer_groups.group_id AND groups.deleted_at IS NULL
+			WHERE groups.type_id = ?
+			GROUP BY customer_groups.customer_id HAVING COUNT(*) > 1
+		) violations`, typeID).Scan(&count)
+	return count
+}
+
+// @Summary Get group types
+// @Description Get all group types (industry, business group, holding, region, ...)
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.GroupType
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-types [get]
+func GetGroupTypes(c *gin.Context) {
+	groupTypes := []entity.GroupType{}
+	if err := config.DB.Order("name").Find(&groupTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group types fetched successfully",
+		"data":    groupTypes,
+	})
+}
+
+// @Summary Create group type
+// @Description Create a group type. An exclusive type allows at most one group of that type per customer.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param groupType body dto.GroupTypeRequest true "Group type"
+// @Success 201 {object} entity.GroupType
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-types [post]
+func CreateGroupType(c *gin.Context) {
+	var req dto.GroupTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	if !groupTypeCodeRegex.MatchString(req.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code must be lowercase letters, digits or underscores (max 50)"})
+		return
+	}
+	if _, err := findGroupType(config.DB, req.Code); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Group type code already exists"})
+		return
+	}
+
+	groupType := entity.GroupType{
+		Code:      req.Code,
+		Name:      req.Name,
+		Exclusive: req.Exclusive,
+		Active:    req.Active == nil || *req.Active,
+	}
+	if err := config.DB.Select("*").Create(&groupType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create group type"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Group type created successfully",
+		"data":    groupType,
+	})
+}
+
+// @Summary Update group type
+// @Description Update a group type. Making a type exclusive fails while a customer still belongs to several groups of that type.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group type ID"
+// @Param groupType body dto.GroupTypeRequest true "Group type"
+// @Success 200 {object} entity.GroupType
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-types/{id} [put]
+func UpdateGroupType(c *gin.Context) {
+	var groupType entity.GroupType
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&groupType).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group type not found"})
+		return
+	}
+
+	var req dto.GroupTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	if !groupTypeCodeRegex.MatchString(req.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code must be lowercase letters, digits or underscores (max 50)"})
+		return
+	}
+	if existing, err := findGroupType(config.DB, req.Code); err == nil && existing.ID != groupType.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Group type code already exists"})
+		return
+	}
+	if req.Exclusive && !groupType.Exclusive {
+		if violations := exclusiveViolations(config.DB, groupType.ID); violations > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "Some customers belong to more than one group of this type",
+				"violations": violations,
+			})
+			return
+		}
+	}
+
+	groupType.Code = req.Code
+	groupType.Name = req.Name
+	groupType.Exclusive = req.Exclusive
+	if req.Active != nil {
+		groupType.Active = *req.Active
+	}
+	if err := config.DB.Save(&groupType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update group type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group type updated successfully",
+		"data":    groupType,
+	})
+}
+
+// @Summary Delete group type
+// @Description Delete a group type that is no longer used by any group
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group type ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-types/{id} [delete]
+func DeleteGroupType(c *gin.Context) {
+	var groupType entity.GroupType
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&groupType).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group type not found"})
+		return
+	}
+
+	var used int64
+	config.DB.Model(&entity.Group{}).Where("type_id = ?", groupType.ID).Count(&used)
+	if used > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Group type is still used by groups"})
+		return
+	}
+	if err := config.DB.Delete(&groupType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete group type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Group type deleted successfully"})
+}
@@ -0,0 +1,712 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultHealthScoreHour   = 2
+	healthScoreBatchSize     = 500
+	defaultHealthHistoryDays = 90
+)
+
+// healthInputs adalah data mentah satu customer yang dibutuhkan untuk menghitung sinyal
+type healthInputs struct {
+	Status         string
+	PreviousScore  *float64
+	LastActivityAt *time.Time
+	PastEvents     int64
+	AttendedEvents int64
+	OverdueBalance float64
+	Assessment     *float64
+	Blocks         int64
+	MainContact    bool
+	MainAddress    bool
+}
+
+// healthSignalScorer menghitung nilai 0-100 satu sinyal; hasData false berarti sinyal tidak ikut dihitung
+type healthSignalScorer func(signal entity.HealthSignal, in healthInputs, now time.Time) (score float64, hasData bool, detail string)
+
+// healthSignalScorers memetakan code HealthSignal ke cara menghitungnya
+var healthSignalScorers = map[string]healthSignalScorer{
+	// Threshold: jumlah hari tanpa aktivitas sampai nilainya 0
+	"activity_recency": func(signal entity.HealthSignal, in healthInputs, now time.Time) (float64, bool, string) {
+		if in.LastActivityAt == nil {
+			return 0, true, "No activity yet"
+		}
+		days := math.Floor(now.Sub(*in.LastActivityAt).Hours() / 24)
+		detail := fmt.Sprintf("Last activity %.0f days ago", days)
+		if signal.Threshold <= 0 {
+			return 100, true, detail
+		}
+		return linearHealthScore(days, signal.Threshold), true, detail
+	},
+	// Threshold: target event yang dihadiri dalam WindowDays; 0 berarti memakai rasio hadir/seluruh event
+	"event_attendance": func(signal entity.HealthSignal, in healthInputs, now time.Time) (float64, bool, string) {
+		// tanpa event sama sekali tidak ada kehadiran yang bisa dinilai
+		if in.PastEvents == 0 {
+			return 0, false, fmt.Sprintf("No events in the last %d days", signal.WindowDays)
+		}
+		detail := fmt.Sprintf("%d of %d events attended in the last %d days", in.AttendedEvents, in.PastEvents, signal.WindowDays)
+		if signal.Threshold > 0 {
+			return math.Min(float64(in.AttendedEvents)/signal.Threshold, 1) * 100, true, detail
+		}
+		return float64(in.AttendedEvents) / float64(in.PastEvents) * 100, true, detail
+	},
+	// Threshold: saldo overdue yang membuat nilainya 0
+	"overdue_balance": func(signal entity.HealthSignal, in healthInputs, now time.Time) (float64, bool, string) {
+		if in.OverdueBalance <= 0 {
+			return 100, true, "No overdue invoices"
+		}
+		detail := fmt.Sprintf("Overdue balance %.0f", in.OverdueBalance)
+		if signal.Threshold <= 0 {
+			return 0, true, detail
+		}
+		return linearHealthScore(in.OverdueBalance, signal.Threshold), true, detail
+	},
+	"assessment_score": func(signal entity.HealthSignal, in healthInputs, now time.Time) (float64, bool, string) {
+		if in.Assessment == nil {
+			return 0, false, "No assessment yet"
+		}
+		return math.Max(0, math.Min(*in.Assessment, 100)), true, fmt.Sprintf("Latest assessment score %.1f", *in.Assessment)
+	},
+	// Threshold: jumlah blokir dalam WindowDays sampai nilainya 0; customer yang sedang diblokir selalu 0
+	"status_history": func(signal entity.HealthSignal, in healthInputs, now time.Time) (float64, bool, string) {
+		if strings.EqualFold(in.Status, "blocked") {
+			return 0, true, "Customer is currently blocked"
+		}
+		detail := fmt.Sprintf("Blocked %d times in the last %d days", in.Blocks, signal.WindowDays)
+		if signal.Threshold <= 0 {
+			if in.Blocks > 0 {
+				return 0, true, detail
+			}
+			return 100, true, detail
+		}
+		return linearHealthScore(float64(in.Blocks), signal.Threshold), true, detail
+	},
+	"contact_coverage": func(signal entity.HealthSignal, in healthInputs, now time.Time) (float64, bool, string) {
+		score := 0.0
+		missing := []string{}
+		if in.MainContact {
+			score += 50
+		} else {
+			missing = append(missing, "main contact")
+		}
+		if in.MainAddress {
+			score += 50
+		} else {
+			missing = append(missing, "main address")
+		}
+		if len(missing) == 0 {
+			return score, true, "Main contact and main address present"
+		}
+		return score, true, "Missing " + strings.Join(missing, " and ")
+	},
+}
+
+// linearHealthScore bernilai 100 pada 0 dan turun linear sampai 0 pada limit
+func linearHealthScore(value, limit float64) float64 {
+	return math.Max(0, 100*(1-value/limit))
+}
+
+// roundHealthScore membulatkan ke satu angka di belakang koma
+func roundHealthScore(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// signalWindowStart mengembalikan awal window sinyal, nil jika sinyal tidak aktif
+func signalWindowStart(signals []entity.HealthSignal, code string, now time.Time) *time.Time {
+	for _, signal := range signals {
+		if signal.Code == code {
+			start := now.AddDate(0, 0, -signal.WindowDays)
+			return &start
+		}
+	}
+	return nil
+}
+
+// loadHealthInputs mengambil semua data sinyal untuk banyak customer sekaligus (satu query per sinyal)
+func loadHealthInputs(db *gorm.DB, ids []string, signals []entity.HealthSignal, now time.Time) (map[string]*healthInputs, error) {
+	inputs := make(map[string]*healthInputs, len(ids))
+	var customers []entity.Customer
+	if err := db.Select("id", "status", "health_score").Where("id IN ?", ids).Find(&customers).Error; err != nil {
+		return nil, err
+	}
+	for _, customer := range customers {
+		inputs[customer.ID] = &healthInputs{Status: customer.Status, PreviousScore: customer.HealthScore}
+	}
+
+	var activities []struct {
+		CustomerID     string
+		LastActivityAt time.Time
+	}
+	err := db.Table("activities").
+		Select("customer_id, MAX(start_time) AS last_activity_at").
+		Where("deleted_at IS NULL AND customer_id IN ? AND start_time <= ?", ids, now).
+		Where("LOWER(status) NOT IN ?", []string{"cancelled", "canceled"}).
+		Group("customer_id").Scan(&activities).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range activities {
+		if in, ok := inputs[row.CustomerID]; ok {
+			last := row.LastActivityAt
+			in.LastActivityAt = &last
+		}
+	}
+
+	if since := signalWindowStart(signals, "event_attendance", now); since != nil {
+		var events []struct {
+			CustomerID string
+			Past       int64
+			Attended   int64
+		}
+		err := db.Table("events").
+			Select(`customer_id, COUNT(*) AS past,
+				COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM event_attendees WHERE event_attendees.event_id = events.id)) AS attended`).
+			Where("deleted_at IS NULL AND is_active = ? AND customer_id IN ? AND scheduled_at BETWEEN ? AND ?", true, ids, *since, now).
+			Where("LOWER(status) NOT IN ?", []string{"cancelled", "canceled"}).
+			Group("customer_id").Scan(&events).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range events {
+			if in, ok := inputs[row.CustomerID]; ok {
+				in.PastEvents = row.Past
+				in.AttendedEvents = row.Attended
+			}
+		}
+	}
+
+	var invoices []struct {
+		CustomerID string
+		Overdue    float64
+	}
+	err = db.Table("invoices").
+		Select("customer_id, COALESCE(SUM(GREATEST(amount - paid_amount, 0)), 0) AS overdue").
+		Where("deleted_at IS NULL AND customer_id IN ? AND due_date < ?", ids, now).
+		Group("customer_id").Scan(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range invoices {
+		if in, ok := inputs[row.CustomerID]; ok {
+			in.OverdueBalance = row.Overdue
+		}
+	}
+
+	var assessments []struct {
+		CustomerID string
+		Score      float64
+	}
+	err = db.Raw(`SELECT DISTINCT ON (customer_id) customer_id, score FROM customer_assessments
+		WHERE deleted_at IS NULL AND customer_id IN ?
+		ORDER BY customer_id, assessed_at DESC, created_at DESC`, ids).Scan(&assessments).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range assessments {
+		if in, ok := inputs[row.CustomerID]; ok {
+			score := row.Score
+			in.Assessment = &score
+		}
+	}
+
+	if since := signalWindowStart(signals, "status_history", now); since != nil {
+		var blocks []struct {
+			CustomerID string
+			Blocks     int64
+		}
+		err := db.Table("status_reasons").
+			Select("customer_id, COUNT(*) AS blocks").
+			Where("deleted_at IS NULL AND customer_id IN ? AND LOWER(status) = ? AND created_at >= ?", ids, "blocked", *since).
+			Group("customer_id").Scan(&blocks).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range blocks {
+			if in, ok := inputs[row.CustomerID]; ok {
+				in.Blocks = row.Blocks
+			}
+		}
+	}
+
+	var mainContacts, mainAddresses []string
+	if err := db.Model(&entity.Contact{}).Where("customer_id IN ? AND main = ? AND active = ?", ids, true, true).Distinct().Pluck("customer_id", &mainContacts).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&entity.Address{}).Where("customer_id IN ? AND main = ? AND active = ?", ids, true, true).Distinct().Pluck("customer_id", &mainAddresses).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range mainContacts {
+		if in, ok := inputs[id]; ok {
+			in.MainContact = true
+		}
+	}
+	for _, id := range mainAddresses {
+		if in, ok := inputs[id]; ok {
+			in.MainAddress = true
+		}
+	}
+	return inputs, nil
+}
+
+// computeHealthScore menggabungkan sinyal aktif menjadi skor 0-100 berbobot.
+// Bobot sinyal tanpa data dibagi ulang ke sinyal lain.
+func computeHealthScore(signals []entity.HealthSignal, in healthInputs, now time.Time) (float64, []entity.HealthScoreFactor) {
+	breakdown := make([]entity.HealthScoreFactor, 0, len(signals))
+	var totalWeight float64
+	for _, signal := range signals {
+		scorer, ok := healthSignalScorers[signal.Code]
+		if !ok {
+			continue
+		}
+		score, hasData, detail := scorer(signal, in, now)
+		breakdown = append(breakdown, entity.HealthScoreFactor{
+			Signal:  signal.Code,
+			Weight:  signal.Weight,
+			Score:   roundHealthScore(score),
+			HasData: hasData,
+			Detail:  detail,
+		})
+		if hasData {
+			totalWeight += signal.Weight
+		}
+	}
+	if totalWeight == 0 {
+		return 0, breakdown
+	}
+
+	var total float64
+	for i := range breakdown {
+		if !breakdown[i].HasData {
+			continue
+		}
+		contribution := breakdown[i].Weight * breakdown[i].Score / totalWeight
+		breakdown[i].Contribution = roundHealthScore(contribution)
+		total += contribution
+	}
+	return roundHealthScore(total), breakdown
+}
+
+// loadHealthSignals mengambil sinyal aktif dengan bobot lebih dari 0
+func loadHealthSignals(db *gorm.DB) ([]entity.HealthSignal, error) {
+	var signals []entity.HealthSignal
+	err := db.Where("active = ? AND weight > 0", true).Order("code").Find(&signals).Error
+	return signals, err
+}
+
+// recomputeHealthScores menghitung ulang skor customer, menyimpannya di customers dan
+// menambah riwayat jika skornya berubah. Mengembalikan jumlah customer yang skornya berubah.
+func recomputeHealthScores(db *gorm.DB, ids []string, trigger string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	signals, err := loadHealthSignals(db)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	inputs, err := loadHealthInputs(db, ids, signals, now)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for id, in := range inputs {
+		score, breakdown := computeHealthScore(signals, *in, now)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&entity.Customer{}).Where("id = ?", id).
+				UpdateColumns(map[string]interface{}{"health_score": score, "health_scored_at": now}).Error; err != nil {
+				return err
+			}
+			if in.PreviousScore != nil && *in.PreviousScore == score {
+				return nil
+			}
+			changed++
+			return tx.Create(&entity.CustomerHealthScore{
+				CustomerID: id,
+				Score:      score,
+				Breakdown:  breakdown,
+				Trigger:    trigger,
+			}).Error
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// refreshHealthScore menghitung ulang skor satu customer setelah data sinyalnya berubah
+func refreshHealthScore(customerID, trigger string) {
+	if customerID == "" {
+		return
+	}
+	if _, err := recomputeHealthScores(config.DB, []string{customerID}, trigger); err != nil {
+		log.Printf("failed to recompute health score for customer %s: %v", customerID, err)
+	}
+}
+
+// recomputeAllHealthScores menghitung ulang skor semua customer per batch
+func recomputeAllHealthScores(trigger string) (int, error) {
+	changed := 0
+	lastID := ""
+	for {
+		var ids []string
+		if err := config.DB.Model(&entity.Customer{}).Where("id > ?", lastID).Order("id").
+			Limit(healthScoreBatchSize).Pluck("id", &ids).Error; err != nil {
+			return changed, err
+		}
+		if len(ids) == 0 {
+			return changed, nil
+		}
+		n, err := recomputeHealthScores(config.DB, ids, trigger)
+		changed += n
+		if err != nil {
+			return changed, err
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
+
+// StartHealthScoreScheduler menghitung ulang semua health score setiap malam pada jam HEALTH_SCORE_HOUR (default 02:00).
+// Set HEALTH_SCORE_ENABLED=false untuk menonaktifkan.
+func StartHealthScoreScheduler() {
+	if enabled, err := strconv.ParseBool(os.Getenv("HEALTH_SCORE_ENABLED")); err == nil && !enabled {
+		return
+	}
+	hour := defaultHealthScoreHour
+	if v, err := strconv.Atoi(os.Getenv("HEALTH_SCORE_HOUR")); err == nil && v >= 0 && v < 24 {
+		hour = v
+	}
+
+	go func() {
+		for {
+			now := time.Now()
+			next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			time.Sleep(time.Until(next))
+
+			changed, err := recomputeAllHealthScores("nightly")
+			if err != nil {
+				log.Printf("nightly health score recompute failed: %v", err)
+				continue
+			}
+			log.Printf("nightly health score recompute changed %d customers", changed)
+		}
+	}()
+}
+
+// @Summary Get customer health score
+// @Description Get the current health score (0-100) of a customer with a per-signal breakdown and the score history. Customers that were never scored are scored on the fly.
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param history_days query int false "Days of history to include (default 90)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/health [get]
+func GetCustomerHealth(c *gin.Context) {
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+	historyDays, err := strconv.Atoi(c.DefaultQuery("history_days", strconv.Itoa(defaultHealthHistoryDays)))
+	if err != nil || historyDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid history_days parameter"})
+		return
+	}
+
+	if customer.HealthScore == nil {
+		if _, err := recomputeHealthScores(config.DB, []string{customer.ID}, "manual"); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute health score"})
+			return
+		}
+	}
+	respondCustomerHealth(c, customer.ID, historyDays)
+}
+
+// @Summary Recompute customer health score
+// @Description Recompute the health score of a customer now
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/health/recompute [post]
+func RecomputeCustomerHealth(c *gin.Context) {
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+	if _, err := recomputeHealthScores(config.DB, []string{customer.ID}, "manual"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute health score"})
+		return
+	}
+	respondCustomerHealth(c, customer.ID, defaultHealthHistoryDays)
+}
+
+// respondCustomerHealth mengirim skor terbaru beserta breakdown dan riwayatnya
+func respondCustomerHealth(c *gin.Context, customerID string, historyDays int) {
+	var customer entity.Customer
+	if err := config.DB.Select("id", "health_score", "health_scored_at").Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+	// breakdown diambil dari riwayat terakhir (dibuat setiap kali skor berubah)
+	var latest entity.CustomerHealthScore
+	if err := config.DB.Where("customer_id = ?", customerID).Order("created_at DESC").Limit(1).Find(&latest).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health score"})
+		return
+	}
+
+	var rows []entity.CustomerHealthScore
+	err := config.DB.Select("score", "trigger", "created_at").
+		Where("customer_id = ? AND created_at >= ?", customerID, time.Now().AddDate(0, 0, -historyDays)).
+		Order("created_at").Find(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health score history"})
+		return
+	}
+	history := make([]dto.HealthScorePoint, 0, len(rows))
+	for _, row := range rows {
+		history = append(history, dto.HealthScorePoint{Score: row.Score, Trigger: row.Trigger, CreatedAt: row.CreatedAt})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Customer health score fetched successfully",
+		"data": gin.H{
+			"customer_id": customerID,
+			"score":       customer.HealthScore,
+			"scored_at":   customer.HealthScoredAt,
+			"changed_at":  latest.CreatedAt,
+			"breakdown":   latest.Breakdown,
+			"history":     history,
+		},
+	})
+}
+
+// @Summary Recompute all health scores
+// @Description Recompute the health score of every customer now, e.g. after changing signal weights (admin only)
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/health-scores/recompute [post]
+func RecomputeAllHealthScores(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	changed, err := recomputeAllHealthScores("manual")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute health scores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Health scores recomputed successfully",
+		"changed": changed,
+	})
+}
+
+// @Summary Get health signals
+// @Description List the weighted signals that make up the customer health score
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.HealthSignal
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/health-signals [get]
+func GetHealthSignals(c *gin.Context) {
+	signals := []entity.HealthSignal{}
+	if err := config.DB.Order("code").Find(&signals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health signals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Health signals fetched successfully",
+		"data":    signals,
+	})
+}
+
+// @Summary Update health signal
+// @Description Change the weight, threshold, window or active flag of a signal (admin only). Stored scores follow on the next recompute.
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Health signal ID"
+// @Param signal body dto.HealthSignalRequest true "Signal settings"
+// @Success 200 {object} entity.HealthSignal
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/health-signals/{id} [put]
+func UpdateHealthSignal(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	var signal entity.HealthSignal
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&signal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Health signal not found"})
+		return
+	}
+
+	var req dto.HealthSignalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name != nil {
+		signal.Name = *req.Name
+	}
+	if req.Weight != nil {
+		signal.Weight = *req.Weight
+	}
+	if req.Threshold != nil {
+		signal.Threshold = *req.Threshold
+	}
+	if req.WindowDays != nil {
+		signal.WindowDays = *req.WindowDays
+	}
+	if req.Active != nil {
+		signal.Active = *req.Active
+	}
+	if err := config.DB.Save(&signal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update health signal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Health signal updated successfully",
+		"data":    signal,
+	})
+}
+
+// @Summary Get customer assessments
+// @Description List assessment results recorded for a customer, newest first
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {array} entity.CustomerAssessment
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/assessments [get]
+func GetCustomerAssessments(c *gin.Context) {
+	assessments := []entity.CustomerAssessment{}
+	err := config.DB.Preload("Assessment").Where("customer_id = ?", c.Param("id")).
+		Order("assessed_at DESC").Find(&assessments).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customer assessments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Customer assessments fetched successfully",
+		"data":    assessments,
+	})
+}
+
+// @Summary Create customer assessment
+// @Description Record an assessment result (score 0-100) for a customer; the latest result feeds the health score
+// @Tags Health Score
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param assessment body dto.CustomerAssessmentRequest true "Assessment result"
+// @Success 201 {object} entity.CustomerAssessment
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/assessments [post]
+func CreateCustomerAssessment(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	var req dto.CustomerAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var assessment entity.Assessment
+	if err := config.DB.Where("id = ?", req.AssessmentID).First(&assessment).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assessment not found"})
+		return
+	}
+	assessedAt := time.Now()
+	if req.AssessedAt != nil && *req.AssessedAt != "" {
+		parsed, err := parseDocumentDate(*req.AssessedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "assessed_at: " + err.Error()})
+			return
+		}
+		assessedAt = *parsed
+	}
+
+	result := entity.CustomerAssessment{
+		CustomerID:   customer.ID,
+		AssessmentID: assessment.ID,
+		Score:        *req.Score,
+		Note:         req.Note,
+		AssessedBy:   userID,
+		AssessedAt:   assessedAt,
+	}
+	if err := config.DB.Create(&result).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save customer assessment"})
+		return
+	}
+	refreshHealthScore(customer.ID, "assessment")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Customer assessment created successfully",
+		"data":    result,
+	})
+}
@@ -82,7 +82,7 @@ func CreateInvoice(c *gin.Context) {
 
 	// Check if customer exists
 	var customer entity.Customer
-	if err := db.First(&customer, req.CustomerID).Error; err != nil {
+	if err := db.Where("id = ?", req.CustomerID).First(&customer).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Customer not found"})
 		return
 	}
@@ -102,9 +102,10 @@ func CreateInvoice(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invoice"})
 		return
 	}
+	refreshHealthScore(invoice.CustomerID, "invoice")
 
 	// Load customer for response
-	db.Preload("Customer").First(&invoice, invoice.ID)
+	db.Preload("Customer").Where("id = ?", invoice.ID).First(&invoice)
 
 	response := convertToInvoiceResponse(invoice)
 	c.JSON(http.StatusCreated, gin.H{"data": response})
@@ -116,7 +117,7 @@ func GetInvoice(c *gin.Context) {
 	var invoice entity.Invoice
 
 	db := config.DB
-	if err := db.Preload("Customer").First(&invoice, id).Error; err != nil {
+	if err := db.Preload("Customer").Preload("Lines").Where("id = ?", id).First(&invoice).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
 		} else {
@@ -140,7 +141,7 @@ func UpdateInvoice(c *gin.Context) {
 
 	db := config.DB
 	var invoice entity.Invoice
-	if err := db.First(&invoice, id).Error; err != nil {
+	if err := db.Where("id = ?", id).First(&invoice).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
 		} else {
@@ -173,9 +174,10 @@ func UpdateInvoice(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice"})
 		return
 	}
+	refreshHealthScore(invoice.CustomerID, "invoice")
 
 	// Load customer for response
-	db.Preload("Customer").First(&invoice, invoice.ID)
+	db.Preload("Customer").Where("id = ?", invoice.ID).First(&invoice)
 
 	response := convertToInvoiceResponse(invoice)
 	c.JSON(http.StatusOK, gin.H{"data": response})
@@ -187,7 +189,7 @@ func DeleteInvoice(c *gin.Context) {
 	var invoice entity.Invoice
 
 	db := config.DB
-	if err := db.First(&invoice, id).Error; err != nil {
+	if err := db.Where("id = ?", id).First(&invoice).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
 		} else {
@@ -196,10 +198,23 @@ func DeleteInvoice(c *gin.Context) {
 		return
 	}
 
-	if err := db.Delete(&invoice).Error; err != nil {
+	// jam kerja yang sudah ditagihkan di invoice ini bisa ditagihkan ulang
+	err := db.Transaction(func(tx *gorm.DB) error {
+		lines := tx.Model(&entity.InvoiceLine{}).Select("id").Where("invoice_id = ?", invoice.ID)
+		if err := tx.Model(&entity.TimeEntry{}).Where("invoice_line_id IN (?)", lines).
+			Update("invoice_line_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&entity.InvoiceLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&invoice).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete invoice"})
 		return
 	}
+	refreshHealthScore(invoice.CustomerID, "invoice")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Invoice deleted successfully"})
 }
@@ -249,6 +264,19 @@ func convertToInvoiceResponse(invoice entity.Invoice) dto.InvoiceResponse {
 		response.Customer = customerResponse
 	}
 
+	// Add lines if loaded
+	for _, line := range invoice.Lines {
+		response.Lines = append(response.Lines, dto.InvoiceLineResponse{
+			ID:          line.ID,
+			ProjectID:   line.ProjectID,
+			UserID:      line.UserID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Amount:      line.Amount,
+		})
+	}
+
 	return response
 }
 
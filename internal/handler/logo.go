@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	logoMaxFileSize  = 5 << 20 // 5 MB
+	logoMinDimension = 32
+	logoMaxDimension = 6000 // cegah decompression bomb sebelum decode penuh
+	logoSize         = 512
+	logoSmallSize    = 64
+)
+
+// Tipe gambar (hasil sniffing isi file) yang diterima untuk logo
+var allowedLogoMimeTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"image/svg+xml",
+}
+
+// Elemen SVG yang bisa menjalankan script atau memuat konten eksternal (style lewat @import/url())
+var svgBlockedElements = map[string]bool{
+	"script":        true,
+	"style":         true,
+	"foreignobject": true,
+	"iframe":        true,
+	"object":        true,
+	"embed":         true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// logoRenditions berisi path file hasil pemrosesan logo
+type logoRenditions struct {
+	Logo      string
+	LogoSmall string
+	MimeType  string
+	Width     int
+	Height    int
+}
+
+// detectLogoType mengembalikan MIME type logo berdasarkan isi file
+func detectLogoType(data []byte) (string, error) {
+	mtype := mimetype.Detect(data)
+	for _, allowed := range allowedLogoMimeTypes {
+		if mtype.Is(allowed) {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("file type %s is not a supported image (PNG, JPG, GIF, WebP or SVG)", mtype.String())
+}
+
+// processLogo memvalidasi logo lalu menghasilkan rendisi Logo (512px) dan LogoSmall (64px).
+// Gambar raster dinormalisasi menjadi PNG, sedangkan SVG disanitasi dan disimpan apa adanya.
+// Nama file memakai ULID agar dua upload beruntun tidak menimpa file yang sama.
+func processLogo(customerID string, src io.Reader) (*logoRenditions, error) {
+	data, err := io.ReadAll(io.LimitReader(src, logoMaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file")
+	}
+	if len(data) > logoMaxFileSize {
+		return nil, fmt.Errorf("logo must not be larger than %d MB", logoMaxFileSize>>20)
+	}
+
+	mimeType, err := detectLogoType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := ulid.Make().String()
+	logoPath := "uploads/logos/logo_" + customerID + "_" + suffix
+	logoSmallPath := "uploads/logos_small/logo_small_" + customerID + "_" + suffix
+
+	if mimeType == "image/svg+xml" {
+		clean, err := sanitizeSVG(data)
+		if err != nil {
+			return nil, err
+		}
+		logoPath += ".svg"
+		logoSmallPath += ".svg"
+		if err := writeLogoFile(logoPath, clean); err != nil {
+			return nil, err
+		}
+		if err := writeLogoFile(logoSmallPath, clean); err != nil {
+			os.Remove(logoPath)
+			return nil, err
+		}
+		return &logoRenditions{Logo: logoPath, LogoSmall: logoSmallPath, MimeType: mimeType}, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if cfg.Width < logoMinDimension || cfg.Height < logoMinDimension {
+		return nil, fmt.Errorf("logo must be at least %dx%d pixels", logoMinDimension, logoMinDimension)
+	}
+	if cfg.Width > logoMaxDimension || cfg.Height > logoMaxDimension {
+		return nil, fmt.Errorf("logo must not exceed %dx%d pixels", logoMaxDimension, logoMaxDimension)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+
+	logoPath += ".png"
+	logoSmallPath += ".png"
+	if err := writeLogoPNG(logoPath, resizeToFit(img, logoSize)); err != nil {
+		return nil, err
+	}
+	if err := writeLogoPNG(logoSmallPath, resizeToFit(img, logoSmallSize)); err != nil {
+		os.Remove(logoPath)
+		return nil, err
+	}
+
+	return &logoRenditions{
+		Logo:      logoPath,
+		LogoSmall: logoSmallPath,
+		MimeType:  mimeType,
+		Width:     cfg.Width,
+		Height:    cfg.Height,
+	}, nil
+}
+
+// resizeToFit mengecilkan gambar agar sisi terpanjang maksimal size pixel (tidak memperbesar)
+func resizeToFit(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+		return dst
+	}
+
+	if w >= h {
+		h = h * size / w
+		w = size
+	} else {
+		w = w * size / h
+		h = size
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	return dst
+}
+
+func writeLogoPNG(path string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode logo")
+	}
+	return writeLogoFile(path, buf.Bytes())
+}
+
+func writeLogoFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to save file")
+	}
+	if err := os.WriteFile(path, data, 0640); err != nil {
+		return fmt.Errorf("failed to save file")
+	}
+	return nil
+}
+
+// removeLogoFile menghapus file logo lama, hanya jika berada di folder uploads logo
+func removeLogoFile(path string) {
+	if path == "" {
+		return
+	}
+	clean := filepath.ToSlash(filepath.Clean(path))
+	if strings.HasPrefix(clean, "uploads/logos/") || strings.HasPrefix(clean, "uploads/logos_small/") {
+		os.Remove(clean)
+	}
+}
+
+// sanitizeSVG membuang script, style, event handler (on*), link javascript: dan DOCTYPE dari SVG
+func sanitizeSVG(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var out bytes.Buffer
+	skipDepth := 0
+	foundRoot := false
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid SVG: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			if !foundRoot {
+				if strings.ToLower(t.Name.Local) != "svg" {
+					return nil, fmt.Errorf("invalid SVG: root element must be <svg>")
+				}
+				foundRoot = true
+			}
+			if svgBlockedElements[strings.ToLower(t.Name.Local)] {
+				skipDepth = 1
+				continue
+			}
+			out.WriteString("<" + rawXMLName(t.Name))
+			for _, attr := range t.Attr {
+				if !isSafeSVGAttr(attr) {
+					continue
+				}
+				out.WriteString(" " + rawXMLName(attr.Name) + `="`)
+				xml.EscapeText(&out, []byte(attr.Value))
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			out.WriteString("</" + rawXMLName(t.Name) + ">")
+		case xml.CharData:
+			if skipDepth > 0 {
+				continue
+			}
+			xml.EscapeText(&out, t)
+		case xml.ProcInst:
+			if t.Target == "xml" && out.Len() == 0 {
+				out.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+			}
+		}
+		// Comment dan Directive (DOCTYPE/ENTITY) sengaja dibuang
+	}
+
+	if !foundRoot {
+		return nil, fmt.Errorf("invalid SVG: root element must be <svg>")
+	}
+	return out.Bytes(), nil
+}
+
+func rawXMLName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+func isSafeSVGAttr(attr xml.Attr) bool {
+	local := strings.ToLower(attr.Name.Local)
+	if strings.HasPrefix(local, "on") {
+		return false
+	}
+
+	value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+	if strings.Contains(value, "javascript:") || strings.Contains(value, "vbscript:") {
+		return false
+	}
+	if local == "href" || local == "src" {
+		// hanya izinkan anchor internal dan data gambar raster
+		if strings.HasPrefix(value, "#") {
+			return true
+		}
+		if strings.HasPrefix(value, "data:image/") && !strings.HasPrefix(value, "data:image/svg") {
+			return true
+		}
+		return false
+	}
+	return true
+}
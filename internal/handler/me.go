@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentAccountManager mengembalikan account manager yang ditautkan ke user, atau nil jika tidak ada
+func currentAccountManager(userID string) *entity.AccountManager {
+	var accountManager entity.AccountManager
+	if err := config.DB.Where("user_id = ?", userID).First(&accountManager).Error; err != nil {
+		return nil
+	}
+	return &accountManager
+}
+
+// parseOptionalRange membaca query from/to (YYYY-MM-DD atau RFC3339); tanggal tanpa jam pada "to"
+// dianggap sampai akhir hari
+func parseOptionalRange(c *gin.Context) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if v := c.Query("from"); v != "" {
+		t, err := parseDocumentDate(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := parseDocumentDate(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(v) == len("2006-01-02") {
+			end := t.Add(24*time.Hour - time.Nanosecond)
+			t = &end
+		}
+		to = t
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+// @Summary Get my profile
+// @Description Get the logged-in user and the account manager linked to it (if any)
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entity.User
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/me [get]
+func GetMe(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var user entity.User
+	if err := config.DB.Preload("Role").Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var accountManager *dto.AccountManagerResponse
+	if am := currentAccountManager(userID); am != nil {
+		response := toAccountManagerResponse(*am)
+		accountManager = &response
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Profile fetched successfully",
+		"data":            user,
+		"account_manager": accountManager,
+	})
+}
+
+// @Summary Get my customers
+// @Description Get the customers in the portfolio of the account manager linked to the logged-in user
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status"
+// @Param category query string false "Filter by category"
+// @Param q query string false "Search name, brand name or code"
+// @Success 200 {array} dto.CustomerListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/me/customers [get]
+func GetMyCustomers(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	customers := []dto.CustomerListResponse{}
+	accountManager := currentAccountManager(userID)
+	if accountManager == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "User is not linked to an account manager",
+			"data":    customers,
+		})
+		return
+	}
+
+	db := config.DB.Preload("AccountManager").Where("account_manager_id = ?", accountManager.ID)
+	if status := c.Query("status"); status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if category := c.Query("category"); category != "" {
+		db = db.Where("category = ?", category)
+	}
+	if q := c.Query("q"); q != "" {
+		like := "%" + q + "%"
+		db = db.Where("name ILIKE ? OR brand_name ILIKE ? OR code ILIKE ?", like, like, like)
+	}
+
+	var result []entity.Customer
+	if err := db.Order("name").Find(&result).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customers"})
+		return
+	}
+	for _, customer := range result {
+		customers = append(customers, toCustomerListResponse(customer))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Customers fetched successfully",
+		"data":            customers,
+		"account_manager": dto.AccountManagerDetail{ID: accountManager.ID, ManagerName: accountManager.ManagerName},
+	})
+}
+
+// @Summary Get my activities
+// @Description Get activities for customers in my portfolio, plus activities I created or attend
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start time from (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Start time until (YYYY-MM-DD or RFC3339)"
+// @Param status query string false "Filter by status"
+// @Success 200 {array} dto.ActivityResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/me/activities [get]
+func GetMyActivities(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scope := "created_by = ? OR id IN (SELECT activity_id FROM activity_attendees WHERE user_id = ?)"
+	args := []interface{}{userID, userID}
+	if accountManager := currentAccountManager(userID); accountManager != nil {
+		scope += " OR customer_id IN (SELECT id FROM customers WHERE account_manager_id = ? AND deleted_at IS NULL)"
+		args = append(args, accountManager.ID)
+	}
+
+	db := config.DB.Where(scope, args...)
+	if from != nil {
+		db = db.Where("start_time >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("start_time <= ?", *to)
+	}
+	if status := c.Query("status"); status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	var activities []entity.Activity
+	if err := db.Order("start_time").Find(&activities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activities"})
+		return
+	}
+
+	responses := make([]dto.ActivityResponse, 0, len(activities))
+	for _, activity := range activities {
+		responses = append(responses, toActivityResponse(activity))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Activities fetched successfully",
+		"data":    responses,
+	})
+}
+
+// @Summary Get my events
+// @Description Get events for customers in my portfolio, plus events I attend
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Scheduled from (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Scheduled until (YYYY-MM-DD or RFC3339)"
+// @Param status query string false "Filter by status"
+// @Success 200 {array} entity.Event
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/me/events [get]
+func GetMyEvents(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	from, to, err := parseOptionalRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scope := "id IN (SELECT event_id FROM event_attendees WHERE user_id = ?)"
+	args := []interface{}{userID}
+	if accountManager := currentAccountManager(userID); accountManager != nil {
+		scope += " OR customer_id IN (SELECT id FROM customers WHERE account_manager_id = ? AND deleted_at IS NULL)"
+		args = append(args, accountManager.ID)
+	}
+
+	db := config.DB.Where(scope, args...)
+	if from != nil {
+		db = db.Where("scheduled_at >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("scheduled_at <= ?", *to)
+	}
+	if status := c.Query("status"); status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	events := []entity.Event{}
+	if err := db.Order("scheduled_at").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Events fetched successfully",
+		"data":    events,
+	})
+}
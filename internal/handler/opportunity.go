@@ -0,0 +1,753 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	opportunityOpen = "open"
+	opportunityWon  = "won"
+	opportunityLost = "lost"
+)
+
+// resolveOpportunityStage mengambil stage yang diminta, atau stage aktif pertama jika kosong
+func resolveOpportunityStage(db *gorm.DB, stageID *string) (entity.Stages, error) {
+	var stage entity.Stages
+	if stageID == nil || *stageID == "" {
+		err := db.Where("is_active = ?", true).Order("sequence, name").First(&stage).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return stage, errors.New("no active stage is configured")
+		}
+		return stage, err
+	}
+	if err := db.Where("id = ?", *stageID).First(&stage).Error; err != nil {
+		return stage, errors.New("stage not found")
+	}
+	if !stage.IsActive {
+		return stage, errors.New("stage is not active")
+	}
+	return stage, nil
+}
+
+// validateOpportunityRefs memastikan project milik customer yang sama dan owner yang dirujuk ada
+func validateOpportunityRefs(db *gorm.DB, customerID string, projectID, ownerID *string) error {
+	if projectID != nil && *projectID != "" {
+		if err := checkCustomerProject(db, *projectID, customerID); err != nil {
+			return err
+		}
+	}
+	if ownerID != nil {
+		var owner entity.User
+		if err := db.Where("id = ?", *ownerID).First(&owner).Error; err != nil {
+			return errors.New("owner not found")
+		}
+	}
+	return nil
+}
+
+// recordOpportunityChange menyimpan riwayat perpindahan stage atau penutupan opportunity
+func recordOpportunityChange(tx *gorm.DB, opportunity entity.Opportunity, fromStageID *string, since time.Time, reason, userID string) error {
+	history := entity.OpportunityStageHistory{
+		OpportunityID: opportunity.ID,
+		FromStageID:   fromStageID,
+		ToStageID:     opportunity.StageID,
+		Status:        opportunity.Status,
+		Probability:   opportunity.Probability,
+		Reason:        reason,
+		ChangedBy:     userID,
+	}
+	if fromStageID != nil && !since.IsZero() {
+		history.HoursInStage = math.Round(time.Since(since).Hours()*10) / 10
+	}
+	return tx.Create(&history).Error
+}
+
+// applyOpportunityFilters menerapkan filter umum daftar opportunity
+func applyOpportunityFilters(c *gin.Context, db *gorm.DB) *gorm.DB {
+	for _, param := range []string{"customer_id", "owner_id", "stage_id", "project_id", "status"} {
+		if value := c.Query(param); value != "" {
+			db = db.Where(param+" = ?", value)
+		}
+	}
+	return db
+}
+
+// findOpportunity mengambil opportunity berdasarkan param :id, mengirim 404 jika tidak ada
+func findOpportunity(c *gin.Context) (entity.Opportunity, bool) {
+	var opportunity entity.Opportunity
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&opportunity).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Opportunity not found"})
+		return opportunity, false
+	}
+	return opportunity, true
+}
+
+// @Summary Get opportunities
+// @Description List opportunities of visible customers
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param customer_id query string false "Filter by customer"
+// @Param owner_id query string false "Filter by owner"
+// @Param stage_id query string false "Filter by stage"
+// @Param project_id query string false "Filter by project"
+// @Param status query string false "open, won or lost"
+// @Success 200 {array} entity.Opportunity
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities [get]
+func GetOpportunities(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	db := applyOpportunityFilters(c, policy.Filter(config.DB.Preload("Stage").Preload("Owner"), "customer_id"))
+	opportunities := []entity.Opportunity{}
+	if err := db.Order("expected_close_date NULLS LAST, created_at DESC").Find(&opportunities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch opportunities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunities fetched successfully",
+		"data":    opportunities,
+	})
+}
+
+// @Summary Create opportunity
+// @Description Create an opportunity. Without stage_id it starts in the first active stage; without probability it takes the stage's default probability.
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param opportunity body dto.CreateOpportunityRequest true "Opportunity"
+// @Success 201 {object} entity.Opportunity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities [post]
+func CreateOpportunity(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req dto.CreateOpportunityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", req.CustomerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Customer not found"})
+		return
+	}
+	stage, err := resolveOpportunityStage(config.DB, req.StageID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ownerID := userID
+	if req.OwnerID != nil && *req.OwnerID != "" {
+		ownerID = *req.OwnerID
+	}
+	if err := validateOpportunityRefs(config.DB, customer.ID, req.ProjectID, &ownerID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opportunity := entity.Opportunity{
+		Name:           req.Name,
+		CustomerID:     customer.ID,
+		StageID:        stage.ID,
+		OwnerID:        ownerID,
+		Value:          req.Value,
+		Probability:    stage.Probability,
+		Status:         opportunityOpen,
+		StageChangedAt: time.Now(),
+		Description:    req.Description,
+		CreatedBy:      userID,
+	}
+	if req.ProjectID != nil && *req.ProjectID != "" {
+		opportunity.ProjectID = req.ProjectID
+	}
+	if req.Probability != nil {
+		opportunity.Probability = *req.Probability
+	}
+	if req.ExpectedCloseDate != nil && *req.ExpectedCloseDate != "" {
+		if opportunity.ExpectedCloseDate, err = parseDocumentDate(*req.ExpectedCloseDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expected_close_date: " + err.Error()})
+			return
+		}
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&opportunity).Error; err != nil {
+			return err
+		}
+		return recordOpportunityChange(tx, opportunity, nil, time.Time{}, "Created", userID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create opportunity"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Opportunity created successfully",
+		"data":    opportunity,
+	})
+}
+
+// @Summary Get opportunity
+// @Description Get an opportunity with its customer, project, stage, owner and stage history
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Opportunity ID"
+// @Success 200 {object} entity.Opportunity
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/{id} [get]
+func GetOpportunity(c *gin.Context) {
+	var opportunity entity.Opportunity
+	err := config.DB.Preload("Customer").Preload("Project").Preload("Stage").Preload("Owner").
+		Where("id = ?", c.Param("id")).First(&opportunity).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Opportunity not found"})
+		return
+	}
+
+	history := []entity.OpportunityStageHistory{}
+	if err := config.DB.Preload("FromStage").Preload("ToStage").Where("opportunity_id = ?", opportunity.ID).
+		Order("created_at").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stage history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunity fetched successfully",
+		"data":    opportunity,
+		"history": history,
+	})
+}
+
+// @Summary Update opportunity
+// @Description Update the details of an opportunity. Use the stage and close endpoints to move or close it.
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Opportunity ID"
+// @Param opportunity body dto.UpdateOpportunityRequest true "Opportunity"
+// @Success 200 {object} entity.Opportunity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/{id} [put]
+func UpdateOpportunity(c *gin.Context) {
+	opportunity, ok := findOpportunity(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateOpportunityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateOpportunityRefs(config.DB, opportunity.CustomerID, req.ProjectID, req.OwnerID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != nil {
+		opportunity.Name = *req.Name
+	}
+	if req.ProjectID != nil {
+		if *req.ProjectID == "" {
+			opportunity.ProjectID = nil
+		} else {
+			opportunity.ProjectID = req.ProjectID
+		}
+	}
+	if req.OwnerID != nil {
+		opportunity.OwnerID = *req.OwnerID
+	}
+	if req.Value != nil {
+		opportunity.Value = *req.Value
+	}
+	if req.Probability != nil {
+		if opportunity.Status != opportunityOpen {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The probability of a closed opportunity cannot be changed"})
+			return
+		}
+		opportunity.Probability = *req.Probability
+	}
+	if req.ExpectedCloseDate != nil {
+		if *req.ExpectedCloseDate == "" {
+			opportunity.ExpectedCloseDate = nil
+		} else {
+			date, err := parseDocumentDate(*req.ExpectedCloseDate)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "expected_close_date: " + err.Error()})
+				return
+			}
+			opportunity.ExpectedCloseDate = date
+		}
+	}
+	if req.Description != nil {
+		opportunity.Description = *req.Description
+	}
+
+	if err := config.DB.Omit(clause.Associations).Save(&opportunity).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update opportunity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunity updated successfully",
+		"data":    opportunity,
+	})
+}
+
+// @Summary Delete opportunity
+// @Description Delete an opportunity
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Opportunity ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/{id} [delete]
+func DeleteOpportunity(c *gin.Context) {
+	opportunity, ok := findOpportunity(c)
+	if !ok {
+		return
+	}
+	if err := config.DB.Delete(&opportunity).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete opportunity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Opportunity deleted successfully"})
+}
+
+// @Summary Move opportunity to stage
+// @Description Move an open opportunity to another stage. The probability resets to the stage default unless given. The move is recorded in the stage history.
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Opportunity ID"
+// @Param stage body dto.OpportunityStageRequest true "Target stage"
+// @Success 200 {object} entity.Opportunity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/{id}/stage [post]
+func MoveOpportunityStage(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	opportunity, ok := findOpportunity(c)
+	if !ok {
+		return
+	}
+	if opportunity.Status != opportunityOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Opportunity is already closed as " + opportunity.Status})
+		return
+	}
+
+	var req dto.OpportunityStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	stage, err := resolveOpportunityStage(config.DB, &req.StageID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if stage.ID == opportunity.StageID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Opportunity is already in this stage"})
+		return
+	}
+
+	fromStageID, since := opportunity.StageID, opportunity.StageChangedAt
+	opportunity.StageID = stage.ID
+	opportunity.Probability = stage.Probability
+	if req.Probability != nil {
+		opportunity.Probability = *req.Probability
+	}
+	opportunity.StageChangedAt = time.Now()
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&opportunity).Error; err != nil {
+			return err
+		}
+		return recordOpportunityChange(tx, opportunity, &fromStageID, since, req.Reason, userID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move opportunity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunity moved successfully",
+		"data":    opportunity,
+	})
+}
+
+// @Summary Close opportunity
+// @Description Close an open opportunity as won (probability 100) or lost (probability 0) with a reason
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Opportunity ID"
+// @Param close body dto.CloseOpportunityRequest true "Outcome and reason"
+// @Success 200 {object} entity.Opportunity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/{id}/close [post]
+func CloseOpportunity(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	opportunity, ok := findOpportunity(c)
+	if !ok {
+		return
+	}
+	if opportunity.Status != opportunityOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Opportunity is already closed as " + opportunity.Status})
+		return
+	}
+
+	var req dto.CloseOpportunityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	closedAt := time.Now()
+	if req.ClosedAt != nil && *req.ClosedAt != "" {
+		parsed, err := parseDocumentDate(*req.ClosedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "closed_at: " + err.Error()})
+			return
+		}
+		closedAt = *parsed
+	}
+
+	since := opportunity.StageChangedAt
+	opportunity.Status = req.Outcome
+	opportunity.CloseReason = req.Reason
+	opportunity.ClosedAt = &closedAt
+	opportunity.Probability = 0
+	if req.Outcome == opportunityWon {
+		opportunity.Probability = 100
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&opportunity).Error; err != nil {
+			return err
+		}
+		stageID := opportunity.StageID
+		return recordOpportunityChange(tx, opportunity, &stageID, since, req.Reason, userID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close opportunity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunity closed as " + opportunity.Status,
+		"data":    opportunity,
+	})
+}
+
+// @Summary Get opportunity kanban
+// @Description Open opportunities grouped by stage in pipeline order, with count, total and weighted value per column
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param customer_id query string false "Filter by customer"
+// @Param owner_id query string false "Filter by owner"
+// @Param project_id query string false "Filter by project"
+// @Success 200 {array} dto.KanbanColumn
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/kanban [get]
+func GetOpportunityKanban(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	db := policy.Filter(config.DB.Preload("Customer").Preload("Owner"), "customer_id").
+		Where("status = ?", opportunityOpen)
+	for _, param := range []string{"customer_id", "owner_id", "project_id"} {
+		if value := c.Query(param); value != "" {
+			db = db.Where(param+" = ?", value)
+		}
+	}
+	var opportunities []entity.Opportunity
+	if err := db.Order("expected_close_date NULLS LAST, value DESC").Find(&opportunities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch opportunities"})
+		return
+	}
+
+	// kolom: semua stage aktif, ditambah stage non-aktif yang masih berisi opportunity open
+	stageIDs := make([]string, 0, len(opportunities))
+	for _, opportunity := range opportunities {
+		stageIDs = append(stageIDs, opportunity.StageID)
+	}
+	var stages []entity.Stages
+	if err := config.DB.Where("is_active = ? OR id IN ?", true, append(stageIDs, "")).
+		Order("sequence, name").Find(&stages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stages"})
+		return
+	}
+
+	columns := make([]dto.KanbanColumn, 0, len(stages))
+	index := make(map[string]int, len(stages))
+	for _, stage := range stages {
+		index[stage.ID] = len(columns)
+		columns = append(columns, dto.KanbanColumn{
+			StageID:       stage.ID,
+			StageName:     stage.Name,
+			Sequence:      stage.Sequence,
+			Probability:   stage.Probability,
+			Opportunities: []dto.OpportunityCard{},
+		})
+	}
+
+	now := time.Now()
+	for _, opportunity := range opportunities {
+		i, found := index[opportunity.StageID]
+		if !found {
+			continue
+		}
+		card := dto.OpportunityCard{
+			ID:                opportunity.ID,
+			Name:              opportunity.Name,
+			CustomerID:        opportunity.CustomerID,
+			OwnerID:           opportunity.OwnerID,
+			Value:             opportunity.Value,
+			Probability:       opportunity.Probability,
+			WeightedValue:     opportunity.Value * opportunity.Probability / 100,
+			ExpectedCloseDate: opportunity.ExpectedCloseDate,
+			DaysInStage:       int(now.Sub(opportunity.StageChangedAt).Hours() / 24),
+		}
+		if opportunity.Customer != nil {
+			card.CustomerName = opportunity.Customer.Name
+		}
+		if opportunity.Owner != nil {
+			card.OwnerName = opportunity.Owner.Username
+		}
+		column := &columns[i]
+		column.Opportunities = append(column.Opportunities, card)
+		column.Count++
+		column.TotalValue += card.Value
+		column.WeightedValue += card.WeightedValue
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunity kanban fetched successfully",
+		"data":    columns,
+	})
+}
+
+// forecastAggregate adalah hasil agregasi opportunity per bulan dan owner
+type forecastAggregate struct {
+	Month    string
+	OwnerID  string
+	Count    int64
+	Value    float64
+	Weighted float64
+}
+
+// forecastQuery membuat query agregasi opportunity yang terlihat oleh user
+func forecastQuery(c *gin.Context, policy *access.Context) *gorm.DB {
+	db := policy.Filter(config.DB.Model(&entity.Opportunity{}), "customer_id")
+	for _, param := range []string{"owner_id", "customer_id", "project_id"} {
+		if value := c.Query(param); value != "" {
+			db = db.Where(param+" = ?", value)
+		}
+	}
+	return db
+}
+
+// @Summary Get opportunity forecast
+// @Description Weighted forecast (value x probability) of open opportunities by expected close month and owner, next to the value already won in each month
+// @Tags Opportunities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First month (YYYY-MM), defaults to the current month"
+// @Param to query string false "Last month (YYYY-MM), defaults to 5 months after from"
+// @Param owner_id query string false "Filter by owner"
+// @Param customer_id query string false "Filter by customer"
+// @Param project_id query string false "Filter by project"
+// @Success 200 {object} dto.ForecastResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/opportunities/forecast [get]
+func GetOpportunityForecast(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseAnalyticsMonth(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must use the YYYY-MM format"})
+			return
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 5, 0)
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseAnalyticsMonth(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must use the YYYY-MM format"})
+			return
+		}
+		to = parsed
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	end := to.AddDate(0, 1, 0)
+
+	var open, won []forecastAggregate
+	err := forecastQuery(c, policy).
+		Select("to_char(expected_close_date, 'YYYY-MM') AS month, owner_id, COUNT(*) AS count, COALESCE(SUM(value), 0) AS value, COALESCE(SUM(value * probability / 100), 0) AS weighted").
+		Where("status = ? AND expected_close_date >= ? AND expected_close_date < ?", opportunityOpen, from, end).
+		Group("1, owner_id").Scan(&open).Error
+	if err == nil {
+		err = forecastQuery(c, policy).
+			Select("to_char(closed_at, 'YYYY-MM') AS month, owner_id, COUNT(*) AS count, COALESCE(SUM(value), 0) AS value, COALESCE(SUM(value), 0) AS weighted").
+			Where("status = ? AND closed_at >= ? AND closed_at < ?", opportunityWon, from, end).
+			Group("1, owner_id").Scan(&won).Error
+	}
+	var unscheduled forecastAggregate
+	if err == nil {
+		err = forecastQuery(c, policy).
+			Select("COUNT(*) AS count, COALESCE(SUM(value), 0) AS value, COALESCE(SUM(value * probability / 100), 0) AS weighted").
+			Where("status = ? AND expected_close_date IS NULL", opportunityOpen).
+			Scan(&unscheduled).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute forecast"})
+		return
+	}
+
+	type rowKey struct{ month, owner string }
+	rows := map[rowKey]*dto.ForecastRow{}
+	ownerIDs := []string{}
+	row := func(month, ownerID string) *dto.ForecastRow {
+		key := rowKey{month, ownerID}
+		if rows[key] == nil {
+			rows[key] = &dto.ForecastRow{Month: month, OwnerID: ownerID}
+			ownerIDs = append(ownerIDs, ownerID)
+		}
+		return rows[key]
+	}
+	for _, agg := range open {
+		r := row(agg.Month, agg.OwnerID)
+		r.OpenCount = agg.Count
+		r.PipelineValue = agg.Value
+		r.WeightedValue = agg.Weighted
+	}
+	for _, agg := range won {
+		r := row(agg.Month, agg.OwnerID)
+		r.WonCount = agg.Count
+		r.WonValue = agg.Value
+	}
+
+	var owners []entity.User
+	if err := config.DB.Select("id", "username").Where("id IN ?", append(ownerIDs, "")).Find(&owners).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch owners"})
+		return
+	}
+	ownerNames := make(map[string]string, len(owners))
+	for _, owner := range owners {
+		ownerNames[owner.ID] = owner.Username
+	}
+
+	response := dto.ForecastResponse{
+		From: from.Format("2006-01"),
+		To:   to.Format("2006-01"),
+		Rows: make([]dto.ForecastRow, 0, len(rows)),
+		Unscheduled: dto.ForecastTotal{
+			OpenCount:     unscheduled.Count,
+			PipelineValue: unscheduled.Value,
+			WeightedValue: unscheduled.Weighted,
+		},
+	}
+	monthIndex := map[string]int{} // bulan -> posisi di response.Months
+	for month := from; month.Before(end); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		monthIndex[key] = len(response.Months)
+		response.Months = append(response.Months, dto.ForecastTotal{Month: key})
+	}
+	for _, r := range rows {
+		r.OwnerName = ownerNames[r.OwnerID]
+		response.Rows = append(response.Rows, *r)
+		if i, ok := monthIndex[r.Month]; ok {
+			total := &response.Months[i]
+			total.OpenCount += r.OpenCount
+			total.PipelineValue += r.PipelineValue
+			total.WeightedValue += r.WeightedValue
+			total.WonCount += r.WonCount
+			total.WonValue += r.WonValue
+		}
+	}
+	sort.Slice(response.Rows, func(i, j int) bool {
+		if response.Rows[i].Month != response.Rows[j].Month {
+			return response.Rows[i].Month < response.Rows[j].Month
+		}
+		return response.Rows[i].OwnerName < response.Rows[j].OwnerName
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Opportunity forecast fetched successfully",
+		"data":    response,
+	})
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// normalizeOptionalID mengubah string kosong menjadi nil
+func normalizeOptionalID(id *string) *string {
+	if id == nil || *id == "" {
+		return nil
+	}
+	return id
+}
+
+// validateContactPlacement memastikan unit dan atasan contact berada di customer yang sama
+// dan relasi "reports to" tidak membentuk siklus
+func validateContactPlacement(db *gorm.DB, contact *entity.Contact) error {
+	contact.StructureID = normalizeOptionalID(contact.StructureID)
+	contact.ReportsToID = normalizeOptionalID(contact.ReportsToID)
+
+	if contact.StructureID != nil {
+		var count int64
+		db.Model(&entity.Structure{}).Where("id = ? AND customer_id = ?", *contact.StructureID, contact.CustomerID).Count(&count)
+		if count == 0 {
+			return fmt.Errorf("structure not found for this customer")
+		}
+	}
+
+	if contact.ReportsToID == nil {
+		return nil
+	}
+	if *contact.ReportsToID == contact.ID {
+		return fmt.Errorf("contact cannot report to itself")
+	}
+
+	var contacts []entity.Contact
+	if err := db.Select("id", "reports_to_id").Where("customer_id = ?", contact.CustomerID).Find(&contacts).Error; err != nil {
+		return err
+	}
+	managerOf := make(map[string]*string, len(contacts))
+	for _, ct := range contacts {
+		managerOf[ct.ID] = ct.ReportsToID
+	}
+	if _, ok := managerOf[*contact.ReportsToID]; !ok {
+		return fmt.Errorf("reports_to contact not found for this customer")
+	}
+
+	// telusuri rantai atasan; jika kembali ke contact ini berarti siklus
+	visited := map[string]bool{}
+	for current := contact.ReportsToID; current != nil; current = managerOf[*current] {
+		if *current == contact.ID {
+			return fmt.Errorf("reporting line would create a cycle")
+		}
+		if visited[*current] {
+			break
+		}
+		visited[*current] = true
+	}
+	return nil
+}
+
+func toOrgChartPerson(contact entity.Contact, structureNames map[string]string) dto.OrgChartPerson {
+	person := dto.OrgChartPerson{
+		ID:          contact.ID,
+		Name:        contact.Name,
+		JobPosition: contact.JobPosition,
+		Position:    contact.Position,
+		Department:  contact.Department,
+		Email:       contact.Email,
+		Phone:       contact.Phone,
+		Mobile:      contact.Mobile,
+		Main:        contact.Main,
+		StructureID: contact.StructureID,
+		ReportsToID: contact.ReportsToID,
+	}
+	if contact.StructureID != nil {
+		person.StructureName = structureNames[*contact.StructureID]
+	}
+	return person
+}
+
+// buildReportingLines menyusun pohon atasan-bawahan; contact yang atasannya tidak aktif/hilang menjadi root
+func buildReportingLines(contacts []entity.Contact, structureNames map[string]string) []dto.OrgChartPerson {
+	exists := make(map[string]bool, len(contacts))
+	for _, ct := range contacts {
+		exists[ct.ID] = true
+	}
+	reports := make(map[string][]entity.Contact)
+	roots := []entity.Contact{}
+	for _, ct := range contacts {
+		if ct.ReportsToID != nil && exists[*ct.ReportsToID] && *ct.ReportsToID != ct.ID {
+			reports[*ct.ReportsToID] = append(reports[*ct.ReportsToID], ct)
+		} else {
+			roots = append(roots, ct)
+		}
+	}
+
+	visited := map[string]bool{}
+	var build func(ct entity.Contact) dto.OrgChartPerson
+	build = func(ct entity.Contact) dto.OrgChartPerson {
+		visited[ct.ID] = true
+		person := toOrgChartPerson(ct, structureNames)
+		for _, child := range reports[ct.ID] {
+			if !visited[child.ID] {
+				person.DirectReports = append(person.DirectReports, build(child))
+			}
+		}
+		return person
+	}
+
+	lines := []dto.OrgChartPerson{}
+	for _, root := range roots {
+		lines = append(lines, build(root))
+	}
+	// data lama yang membentuk siklus tetap ditampilkan
+	for _, ct := range contacts {
+		if !visited[ct.ID] {
+			lines = append(lines, build(ct))
+		}
+	}
+	return lines
+}
+
+// @Summary Get customer org chart
+// @Description Get the organizational units of a customer with the contacts placed in each unit, plus the reporting lines between contacts
+// @Tags Structures
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param include_inactive query bool false "Include inactive contacts"
+// @Success 200 {object} dto.OrgChartResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/org-chart [get]
+func GetCustomerOrgChart(c *gin.Context) {
+	customerID := c.Param("id")
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	structures, err := loadCustomerStructures(config.DB, customer.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
+		return
+	}
+
+	query := config.DB.Where("customer_id = ?", customer.ID)
+	if c.Query("include_inactive") != "true" {
+		query = query.Where("active = ?", true)
+	}
+	var contacts []entity.Contact
+	if err := query.Order("main DESC, name ASC").Find(&contacts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contacts"})
+		return
+	}
+
+	structureNames := make(map[string]string, len(structures))
+	for _, s := range structures {
+		structureNames[s.ID] = s.Name
+	}
+
+	people := make(map[string][]dto.OrgChartPerson)
+	unassigned := []dto.OrgChartPerson{}
+	for _, ct := range contacts {
+		if ct.StructureID != nil {
+			if _, ok := structureNames[*ct.StructureID]; ok {
+				people[*ct.StructureID] = append(people[*ct.StructureID], toOrgChartPerson(ct, structureNames))
+				continue
+			}
+		}
+		unassigned = append(unassigned, toOrgChartPerson(ct, structureNames))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Org chart fetched successfully",
+		"data": dto.OrgChartResponse{
+			CustomerID:     customer.ID,
+			Units:          attachOrgChartPeople(buildStructureTree(structures), people),
+			Unassigned:     unassigned,
+			ReportingLines: buildReportingLines(contacts, structureNames),
+		},
+	})
+}
+
+func attachOrgChartPeople(nodes []dto.StructureTreeNode, people map[string][]dto.OrgChartPerson) []dto.OrgChartNode {
+	units := make([]dto.OrgChartNode, 0, len(nodes))
+	for _, node := range nodes {
+		members := people[node.ID]
+		if members == nil {
+			members = []dto.OrgChartPerson{}
+		}
+		units = append(units, dto.OrgChartNode{
+			ID:       node.ID,
+			ParentID: node.ParentID,
+			Name:     node.Name,
+			Level:    node.Level,
+			Position: node.Position,
+			Active:   node.Active,
+			People:   members,
+			Children: attachOrgChartPeople(node.Children, people),
+		})
+	}
+	return units
+}
+
+// @Summary Update contact placement
+// @Description Attach a contact to an org unit (structure) and set who the contact reports to
+// @Tags Contacts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contact ID"
+// @Param placement body dto.ContactPlacementRequest true "Structure and reporting line"
+// @Success 200 {object} entity.Contact
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/contacts/{id}/placement [put]
+func UpdateContactPlacement(c *gin.Context) {
+	id := c.Param("id")
+
+	var contact entity.Contact
+	if err := config.DB.Where("id = ?", id).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	var req dto.ContactPlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contact.StructureID = req.StructureID
+	contact.ReportsToID = req.ReportsToID
+	if err := validateContactPlacement(config.DB, &contact); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := config.DB.Model(&contact).Updates(map[string]interface{}{
+		"structure_id":  contact.StructureID,
+		"reports_to_id": contact.ReportsToID,
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contact placement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// @Summary Get contact reporting chain
+// @Description Get the chain of managers above a contact, starting from the contact itself up to the top decision maker
+// @Tags Contacts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contact ID"
+// @Success 200 {array} dto.OrgChartPerson
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/contacts/{id}/reporting-chain [get]
+func GetContactReportingChain(c *gin.Context) {
+	id := c.Param("id")
+
+	var contact entity.Contact
+	if err := config.DB.Where("id = ?", id).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	var contacts []entity.Contact
+	if err := config.DB.Where("customer_id = ?", contact.CustomerID).Find(&contacts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contacts"})
+		return
+	}
+	structures, err := loadCustomerStructures(config.DB, contact.CustomerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
+		return
+	}
+
+	byID := make(map[string]entity.Contact, len(contacts))
+	for _, ct := range contacts {
+		byID[ct.ID] = ct
+	}
+	structureNames := make(map[string]string, len(structures))
+	for _, s := range structures {
+		structureNames[s.ID] = s.Name
+	}
+
+	chain := []dto.OrgChartPerson{}
+	visited := map[string]bool{}
+	current := contact
+	for !visited[current.ID] {
+		visited[current.ID] = true
+		chain = append(chain, toOrgChartPerson(current, structureNames))
+		if current.ReportsToID == nil {
+			break
+		}
+		manager, ok := byID[*current.ReportsToID]
+		if !ok {
+			break
+		}
+		current = manager
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reporting chain fetched successfully",
+		"data":    chain,
+	})
+}
@@ -240,7 +240,11 @@ func GetOthersByAttribute(c *gin.Context) {
 		return
 	}
 
-	query := config.DB.Where("attribute_name ILIKE ?", "%"+attributeName+"%")
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	query := policy.Filter(config.DB.Where("attribute_name ILIKE ?", "%"+attributeName+"%"), "customer_id")
 
 	// Filter by active status if provided
 	if activeParam := c.Query("active"); activeParam != "" {
@@ -260,7 +264,7 @@ func GetOthersByAttribute(c *gin.Context) {
 }
 
 // @Summary Create other
-// @Description Create a new other field
+// @Description Create a custom field value for a customer. The key must be defined in other-configs and the value is validated and normalized against its data type.
 // @Tags Others
 // @Accept json
 // @Produce json
@@ -269,6 +273,7 @@ func GetOthersByAttribute(c *gin.Context) {
 // @Success 201 {object} entity.Other
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/others [post]
 func CreateOther(c *gin.Context) {
@@ -278,12 +283,44 @@ func CreateOther(c *gin.Context) {
 		return
 	}
 
-	// Hapus validasi customer exists karena CustomerID tidak ada di request
+	if req.CustomerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "customer_id is required"})
+		return
+	}
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", req.CustomerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	// Validasi key dan nilai terhadap definisi custom field
+	fields, err := loadCustomFields(config.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load custom fields"})
+		return
+	}
+	field, ok := fields[req.Key]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown custom field '" + req.Key + "'"})
+		return
+	}
+	value, err := coerceCustomFieldValue(field, req.Value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existing entity.Other
+	if err := config.DB.Where("customer_id = ? AND key = ?", customer.ID, req.Key).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Customer already has a value for '" + req.Key + "'", "id": existing.ID})
+		return
+	}
+
 	other := entity.Other{
-		// CustomerID: req.CustomerID, // Akan diset sesuai kebutuhan
-		Key:    req.Key,
-		Value:  req.Value,
-		Active: req.Active,
+		CustomerID: customer.ID,
+		Key:        req.Key,
+		Value:      value,
+		Active:     req.Active,
 	}
 
 	result := config.DB.Create(&other)
@@ -2,38 +2,123 @@ package handler
 
 import (
 	"customer-api/internal/config"
+	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-type OtherConfig struct {
-	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	Name      string         `json:"name" gorm:"not null;unique"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
+// validateOtherConfigType memeriksa tipe data dan kecocokan pilihan enum
+func validateOtherConfigType(dataType string, options []dto.OthersConfigOptionRequest) string {
+	if !isFieldType(dataType) {
+		return "data_type must be one of: " + strings.Join(fieldTypes, ", ")
+	}
+	if dataType == fieldTypeEnum && len(options) == 0 {
+		return "Enum fields need at least one option"
+	}
+	if dataType != fieldTypeEnum && len(options) > 0 {
+		return "Only enum fields can have options"
+	}
+	seen := map[string]bool{}
+	for _, option := range options {
+		value := strings.ToLower(strings.TrimSpace(option.Value))
+		if value == "" {
+			return "Option value cannot be empty"
+		}
+		if seen[value] {
+			return "Duplicate option '" + option.Value + "'"
+		}
+		seen[value] = true
+	}
+	return ""
 }
 
-type OtherConfigDetail struct {
-	ID        string         `json:"id" gorm:"primaryKey;size:26"`
-	ConfigID  string         `json:"config_id" gorm:"index"`
-	Icon      string         `json:"icon"`
-	IsActive  bool           `json:"is_active"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
-
-	// relationships
-	Config OtherConfig `json:"config" gorm:"foreignKey:ConfigID;references:ID"`
+// replaceOtherConfigOptions mengganti semua pilihan enum milik config
+func replaceOtherConfigOptions(tx *gorm.DB, configID string, options []dto.OthersConfigOptionRequest) error {
+	if err := tx.Where("config_id = ?", configID).Delete(&entity.OthersConfigDetail{}).Error; err != nil {
+		return err
+	}
+	for i, option := range options {
+		detail := entity.OthersConfigDetail{
+			ConfigID:  configID,
+			Value:     strings.TrimSpace(option.Value),
+			Label:     option.Label,
+			Icon:      option.Icon,
+			SortOrder: option.SortOrder,
+			IsActive:  true,
+		}
+		if detail.Label == "" {
+			detail.Label = detail.Value
+		}
+		if detail.SortOrder == 0 {
+			detail.SortOrder = i + 1
+		}
+		if err := tx.Omit("Config").Create(&detail).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// findOtherConfig memuat config dari parameter :id beserta pilihan enum
+// revalidateOtherValues menilai ulang nilai tersimpan sebuah custom field terhadap tipe dan pilihannya
+// yang baru; mengembalikan nilai kanonik yang berubah per id dan nilai yang tidak lolos
+func revalidateOtherValues(db *gorm.DB, field entity.OthersConfig) (map[string]*string, []dto.CustomFieldValueConflict, error) {
+	var others []entity.Other
+	if err := db.Select("id", "customer_id", "value").Where("key = ? AND value IS NOT NULL AND value <> ''", field.Key).Find(&others).Error; err != nil {
+		return nil, nil, err
+	}
+	// nilai kosong tidak dinilai ulang, required hanya berlaku saat nilai diubah
+	field.Required = false
+	coerced := map[string]*string{}
+	conflicts := []dto.CustomFieldValueConflict{}
+	for _, other := range others {
+		value, err := coerceCustomFieldValue(field, other.Value)
+		if err != nil {
+			conflicts = append(conflicts, dto.CustomFieldValueConflict{
+				OtherID:    other.ID,
+				CustomerID: other.CustomerID,
+				Value:      *other.Value,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		if value != nil && *value != *other.Value {
+			coerced[other.ID] = value
+		}
+	}
+	return coerced, conflicts, nil
+}
+
+func findOtherConfig(c *gin.Context) (*entity.OthersConfig, bool) {
+	var field entity.OthersConfig
+	err := config.DB.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") }).
+		Where("id = ?", c.Param("id")).First(&field).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Config other not found"})
+		return nil, false
+	}
+	return &field, true
+}
+
+// @Summary Create custom field
+// @Description Define a custom customer field. Values are stored in others with the same key and validated against data_type (text, number, date, enum, boolean, url).
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param config body dto.OthersConfigRequest true "Custom field"
+// @Success 201 {object} entity.OthersConfig
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs [post]
 func CreateConfigOther(c *gin.Context) {
-	var input entity.GroupConfig
+	var input dto.OthersConfigRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -43,67 +128,136 @@ func CreateConfigOther(c *gin.Context) {
 		return
 	}
 
-	if result := config.DB.Create(&input); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "failed",
-			"message": "Gagal membuat config group",
+	input.Key = strings.TrimSpace(input.Key)
+	if !customFieldKeyRegex.MatchString(input.Key) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "key must be lowercase letters, digits and underscores, starting with a letter",
 			"data":    nil,
 		})
 		return
 	}
+	if message := validateOtherConfigType(input.DataType, input.Options); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": message, "data": nil})
+		return
+	}
 
-	// insert group config
+	var existing entity.OthersConfig
+	if err := config.DB.Unscoped().Where("key = ?", input.Key).First(&existing).Error; err == nil {
+		message := "Key sudah digunakan"
+		if existing.DeletedAt.Valid {
+			message = "Key pernah digunakan oleh field yang sudah dihapus"
+		}
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": message, "data": nil})
+		return
+	}
 
-	groupConfig := entity.GroupConfig{
-		Name: input.Name,
+	field := entity.OthersConfig{
+		Key:       input.Key,
+		Label:     input.Label,
+		DataType:  input.DataType,
+		Required:  input.Required,
+		Icon:      input.Icon,
+		SortOrder: input.SortOrder,
+		IsActive:  true,
 	}
-	if result := config.DB.Create(&groupConfig); result.Error != nil {
+	if input.IsActive != nil {
+		field.IsActive = *input.IsActive
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Options").Select("*").Create(&field).Error; err != nil {
+			return err
+		}
+		return replaceOtherConfigOptions(tx, field.ID, input.Options)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
-			"message": "Gagal membuat group config",
+			"message": "Gagal membuat config other",
 			"data":    nil,
 		})
 		return
 	}
+	config.DB.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") }).
+		Where("id = ?", field.ID).First(&field)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"status":  "success",
-		"message": "Config group berhasil dibuat",
-		"data":    groupConfig,
+		"message": "Config other berhasil dibuat",
+		"data":    field,
 	})
 }
 
+// @Summary Get custom fields
+// @Description Get custom field definitions with their enum options
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param active query bool false "Filter by active flag"
+// @Success 200 {array} entity.OthersConfig
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs [get]
 func GetConfigOther(c *gin.Context) {
-	var groups []entity.GroupConfig
-	if result := config.DB.Find(&groups); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data config groups"})
+	var fields []entity.OthersConfig
+	db := config.DB.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") })
+	if active := c.Query("active"); active != "" {
+		db = db.Where("is_active = ?", active == "true")
+	}
+	if result := db.Order("sort_order").Order("label").Find(&fields); result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data config other"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Data config groups berhasil diambil",
-		"data":    groups,
+		"message": "Data config other berhasil diambil",
+		"data":    fields,
 	})
 }
 
+// @Summary Get custom field
+// @Description Get a custom field definition with its enum options
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Success 200 {object} entity.OthersConfig
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id} [get]
 func GetConfigOtherDetail(c *gin.Context) {
-	id := c.Param("id")
-	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+	field, ok := findOtherConfig(c)
+	if !ok {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Config group fetched successfully",
-		"data":    group,
+		"message": "Config other fetched successfully",
+		"data":    field,
 	})
 }
 
+// @Summary Update custom field
+// @Description Update a custom field definition. The key cannot be changed; options, when given, replace all existing options. Changing data_type or options revalidates the stored values; the update is refused with 409 when any of them no longer fits.
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Param config body dto.UpdateOthersConfigRequest true "Custom field"
+// @Success 200 {object} entity.OthersConfig
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id} [put]
 func UpdateConfigOther(c *gin.Context) {
-	id := c.Param("id")
-	var input entity.GroupConfig
+	var input dto.UpdateOthersConfigRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -113,75 +267,255 @@ func UpdateConfigOther(c *gin.Context) {
 		return
 	}
 
-	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+	field, ok := findOtherConfig(c)
+	if !ok {
+		return
+	}
+
+	if input.Label != nil && *input.Label != "" {
+		field.Label = *input.Label
+	}
+	if input.DataType != nil {
+		field.DataType = *input.DataType
+	}
+	if input.Required != nil {
+		field.Required = *input.Required
+	}
+	if input.Icon != nil {
+		field.Icon = *input.Icon
+	}
+	if input.SortOrder != nil {
+		field.SortOrder = *input.SortOrder
+	}
+	if input.IsActive != nil {
+		field.IsActive = *input.IsActive
+	}
+
+	// validasi tipe terhadap pilihan baru, atau pilihan lama jika tidak dikirim
+	options := input.Options
+	if options == nil {
+		for _, option := range field.Options {
+			options = append(options, dto.OthersConfigOptionRequest{Value: option.Value})
+		}
+	}
+	if message := validateOtherConfigType(field.DataType, options); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": message, "data": nil})
 		return
 	}
 
-	if input.Name != "" {
-		group.Name = input.Name
+	// nilai yang sudah tersimpan harus tetap valid untuk tipe dan pilihan yang baru
+	coerced := map[string]*string{}
+	if input.DataType != nil || input.Options != nil {
+		candidate := *field
+		if input.Options != nil {
+			candidate.Options = nil
+			for _, option := range input.Options {
+				candidate.Options = append(candidate.Options, entity.OthersConfigDetail{Value: strings.TrimSpace(option.Value)})
+			}
+		}
+		var conflicts []dto.CustomFieldValueConflict
+		var err error
+		coerced, conflicts, err = revalidateOtherValues(config.DB, candidate)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "failed", "message": "Gagal memeriksa nilai custom field", "data": nil})
+			return
+		}
+		if len(conflicts) > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Existing values do not fit the new data_type or options",
+				"data":    conflicts,
+			})
+			return
+		}
 	}
 
-	if result := config.DB.Save(&group); result.Error != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Options").Save(field).Error; err != nil {
+			return err
+		}
+		for id, value := range coerced {
+			if err := tx.Model(&entity.Other{}).Where("id = ?", id).Update("value", value).Error; err != nil {
+				return err
+			}
+		}
+		if input.Options != nil {
+			return replaceOtherConfigOptions(tx, field.ID, input.Options)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
-			"message": "Gagal memperbarui config group",
+			"message": "Gagal memperbarui config other",
 			"data":    nil,
 		})
 		return
 	}
+	field, _ = findOtherConfig(c)
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Config group updated successfully",
-		"data":    group,
+		"message": "Config other updated successfully",
+		"data":    field,
 	})
 }
 
+// @Summary Delete custom field
+// @Description Delete a custom field definition and its options. Existing values in others are kept.
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id} [delete]
 func DeleteConfigOther(c *gin.Context) {
-	id := c.Param("id")
-	var group entity.GroupConfig
-	if result := config.DB.Where("id = ?", id).First(&group); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+	field, ok := findOtherConfig(c)
+	if !ok {
 		return
 	}
 
-	if result := config.DB.Delete(&group); result.Error != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("config_id = ?", field.ID).Delete(&entity.OthersConfigDetail{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", field.ID).Delete(&entity.OthersConfig{}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
-			"message": "Gagal menghapus config group",
+			"message": "Gagal menghapus config other",
 			"data":    nil,
 		})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Config group deleted successfully",
+		"message": "Config other deleted successfully",
 		"data":    nil,
 	})
 }
 
-// CreateConfigOtherDetail function has been removed as it's handled by CreateConfigGroupDetail in groupConfig.go
-
+// @Summary Get custom field options
+// @Description Get the enum options of a custom field
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Success 200 {array} entity.OthersConfigDetail
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id}/details [get]
 func GetConfigOtherDetails(c *gin.Context) {
-	var details []entity.GroupConfigDetail
-	if result := config.DB.Find(&details); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data config other details"})
+	field, ok := findOtherConfig(c)
+	if !ok {
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Data config other details berhasil diambil",
-		"data":    details,
+		"data":    field.Options,
 	})
 }
 
-func GetConfigOthersDetail(c *gin.Context) {
-	id := c.Param("id")
+// @Summary Add custom field option
+// @Description Add an option to an enum custom field
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Param option body dto.OthersConfigOptionRequest true "Option"
+// @Success 201 {object} entity.OthersConfigDetail
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id}/details [post]
+func CreateConfigOtherDetail(c *gin.Context) {
+	field, ok := findOtherConfig(c)
+	if !ok {
+		return
+	}
+
+	var input dto.OthersConfigOptionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error(), "data": nil})
+		return
+	}
+	if field.DataType != fieldTypeEnum {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Only enum fields can have options", "data": nil})
+		return
+	}
+	for _, option := range field.Options {
+		if strings.EqualFold(option.Value, strings.TrimSpace(input.Value)) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Duplicate option '" + input.Value + "'", "data": nil})
+			return
+		}
+	}
+
+	detail := entity.OthersConfigDetail{
+		ConfigID:  field.ID,
+		Value:     strings.TrimSpace(input.Value),
+		Label:     input.Label,
+		Icon:      input.Icon,
+		SortOrder: input.SortOrder,
+		IsActive:  true,
+	}
+	if detail.Label == "" {
+		detail.Label = detail.Value
+	}
+	if detail.SortOrder == 0 {
+		detail.SortOrder = len(field.Options) + 1
+	}
+	if result := config.DB.Omit("Config").Create(&detail); result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal membuat config other detail",
+			"data":    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Config other detail berhasil dibuat",
+		"data":    detail,
+	})
+}
+
+// findOtherConfigDetail memuat pilihan enum dari parameter :id dan :detail_id
+func findOtherConfigDetail(c *gin.Context) (*entity.OthersConfigDetail, bool) {
 	var detail entity.OthersConfigDetail
-	if result := config.DB.Where("id = ?", id).First(&detail); result.Error != nil {
+	err := config.DB.Where("id = ? AND config_id = ?", c.Param("detail_id"), c.Param("id")).First(&detail).Error
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Config other detail not found"})
+		return nil, false
+	}
+	return &detail, true
+}
+
+// @Summary Get custom field option
+// @Description Get one enum option of a custom field
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Param detail_id path string true "Option ID"
+// @Success 200 {object} entity.OthersConfigDetail
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id}/details/{detail_id} [get]
+func GetConfigOthersDetail(c *gin.Context) {
+	detail, ok := findOtherConfigDetail(c)
+	if !ok {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -191,9 +525,23 @@ func GetConfigOthersDetail(c *gin.Context) {
 	})
 }
 
+// @Summary Update custom field option
+// @Description Update one enum option of a custom field. Changing value does not rewrite existing others.
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Param detail_id path string true "Option ID"
+// @Param option body dto.OthersConfigOptionRequest true "Option"
+// @Success 200 {object} entity.OthersConfigDetail
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id}/details/{detail_id} [put]
 func UpdateConfigOtherDetail(c *gin.Context) {
-	id := c.Param("id")
-	var input entity.OthersConfigDetail
+	var input dto.OthersConfigOptionRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -202,18 +550,27 @@ func UpdateConfigOtherDetail(c *gin.Context) {
 		})
 		return
 	}
-	var detail entity.GroupConfigDetail
-	if result := config.DB.Where("id = ?", id).First(&detail); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config other detail not found"})
+	detail, ok := findOtherConfigDetail(c)
+	if !ok {
 		return
 	}
-	if input.ConfigID != "" {
-		detail.Name = input.ConfigID
+
+	var duplicate entity.OthersConfigDetail
+	if err := config.DB.Where("config_id = ? AND id <> ? AND LOWER(value) = LOWER(?)", detail.ConfigID, detail.ID, strings.TrimSpace(input.Value)).
+		First(&duplicate).Error; err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Duplicate option '" + input.Value + "'", "data": nil})
+		return
 	}
-	if input.IsActive != detail.IsActive {
-		detail.IsActive = input.IsActive
+
+	detail.Value = strings.TrimSpace(input.Value)
+	if input.Label != "" {
+		detail.Label = input.Label
 	}
-	if result := config.DB.Save(&detail); result.Error != nil {
+	detail.Icon = input.Icon
+	if input.SortOrder != 0 {
+		detail.SortOrder = input.SortOrder
+	}
+	if result := config.DB.Omit("Config").Save(detail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal memperbarui config other detail",
@@ -228,14 +585,34 @@ func UpdateConfigOtherDetail(c *gin.Context) {
 	})
 }
 
+// @Summary Delete custom field option
+// @Description Delete one enum option. An enum field must keep at least one option.
+// @Tags OtherConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Config ID"
+// @Param detail_id path string true "Option ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/other-configs/{id}/details/{detail_id} [delete]
 func DeleteConfigOtherDetail(c *gin.Context) {
-	id := c.Param("id")
-	var detail entity.OthersConfigDetail
-	if result := config.DB.Where("id = ?", id).First(&detail); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Config other detail not found"})
+	detail, ok := findOtherConfigDetail(c)
+	if !ok {
 		return
 	}
-	if result := config.DB.Delete(&detail); result.Error != nil {
+
+	var remaining int64
+	config.DB.Model(&entity.OthersConfigDetail{}).Where("config_id = ? AND id <> ?", detail.ConfigID, detail.ID).Count(&remaining)
+	if remaining == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "An enum field must keep at least one option", "data": nil})
+		return
+	}
+
+	if result := config.DB.Delete(detail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal menghapus config other detail",
@@ -70,7 +70,7 @@ func CreatePayment(c *gin.Context) {
 
 	// Check if invoice exists
 	var invoice entity.Invoice
-	if err := db.First(&invoice, req.InvoiceID).Error; err != nil {
+	if err := db.Where("id = ?", req.InvoiceID).First(&invoice).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice not found"})
 		return
 	}
@@ -90,9 +90,10 @@ func CreatePayment(c *gin.Context) {
 	// Update invoice paid amount
 	invoice.PaidAmount += req.Amount
 	db.Save(&invoice)
+	refreshHealthScore(invoice.CustomerID, "payment")
 
 	// Load invoice for response
-	db.Preload("Invoice").First(&payment, payment.ID)
+	db.Preload("Invoice").Where("id = ?", payment.ID).First(&payment)
 
 	response := convertToPaymentResponse(payment)
 	c.JSON(http.StatusCreated, gin.H{"data": response})
@@ -104,7 +105,7 @@ func GetPayment(c *gin.Context) {
 	var payment entity.Payment
 
 	db := config.DB
-	if err := db.Preload("Invoice").First(&payment, id).Error; err != nil {
+	if err := db.Preload("Invoice").Where("id = ?", id).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
 		} else {
@@ -128,7 +129,7 @@ func UpdatePayment(c *gin.Context) {
 
 	db := config.DB
 	var payment entity.Payment
-	if err := db.First(&payment, id).Error; err != nil {
+	if err := db.Where("id = ?", id).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
 		} else {
@@ -156,14 +157,15 @@ func UpdatePayment(c *gin.Context) {
 	// Update invoice paid amount if amount changed
 	if req.Amount != nil {
 		var invoice entity.Invoice
-		if err := db.First(&invoice, payment.InvoiceID).Error; err == nil {
+		if err := db.Where("id = ?", payment.InvoiceID).First(&invoice).Error; err == nil {
 			invoice.PaidAmount = invoice.PaidAmount - oldAmount + payment.Amount
 			db.Save(&invoice)
+			refreshHealthScore(invoice.CustomerID, "payment")
 		}
 	}
 
 	// Load invoice for response
-	db.Preload("Invoice").First(&payment, payment.ID)
+	db.Preload("Invoice").Where("id = ?", payment.ID).First(&payment)
 
 	response := convertToPaymentResponse(payment)
 	c.JSON(http.StatusOK, gin.H{"data": response})
@@ -175,7 +177,7 @@ func DeletePayment(c *gin.Context) {
 	var payment entity.Payment
 
 	db := config.DB
-	if err := db.First(&payment, id).Error; err != nil {
+	if err := db.Where("id = ?", id).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
 		} else {
@@ -186,7 +188,7 @@ func DeletePayment(c *gin.Context) {
 
 	// Update invoice paid amount
 	var invoice entity.Invoice
-	if err := db.First(&invoice, payment.InvoiceID).Error; err == nil {
+	if err := db.Where("id = ?", payment.InvoiceID).First(&invoice).Error; err == nil {
 		invoice.PaidAmount -= payment.Amount
 		db.Save(&invoice)
 	}
@@ -195,6 +197,7 @@ func DeletePayment(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payment"})
 		return
 	}
+	refreshHealthScore(invoice.CustomerID, "payment")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Payment deleted successfully"})
 }
@@ -229,18 +232,13 @@ func CreateInvoicePayment(c *gin.Context) {
 	}
 
 	// Override invoice ID from URL
-	invoiceIDUint, err := strconv.ParseUint(invoiceID, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
-		return
-	}
-	req.InvoiceID = uint(invoiceIDUint)
+	req.InvoiceID = invoiceID
 
 	db := config.DB
 
 	// Check if invoice exists
 	var invoice entity.Invoice
-	if err := db.First(&invoice, req.InvoiceID).Error; err != nil {
+	if err := db.Where("id = ?", req.InvoiceID).First(&invoice).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice not found"})
 		return
 	}
@@ -260,9 +258,10 @@ func CreateInvoicePayment(c *gin.Context) {
 	// Update invoice paid amount
 	invoice.PaidAmount += req.Amount
 	db.Save(&invoice)
+	refreshHealthScore(invoice.CustomerID, "payment")
 
 	// Load invoice for response
-	db.Preload("Invoice").First(&payment, payment.ID)
+	db.Preload("Invoice").Where("id = ?", payment.ID).First(&payment)
 
 	response := convertToPaymentResponse(payment)
 	c.JSON(http.StatusCreated, gin.H{"data": response})
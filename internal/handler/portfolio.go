@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"crypto/rand"
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// samePtr membandingkan dua ID yang boleh nil (account manager, team, ...)
+func samePtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// recordOwnershipChange mencatat perpindahan account manager customer jika memang berubah
+func recordOwnershipChange(tx *gorm.DB, customerID string, from, to *string, userID, reason, batchID string) error {
+	if samePtr(from, to) {
+		return nil
+	}
+	return tx.Create(&entity.CustomerOwnership{
+		CustomerID:           customerID,
+		FromAccountManagerID: from,
+		ToAccountManagerID:   to,
+		ChangedBy:            userID,
+		Reason:               reason,
+		BatchID:              batchID,
+	}).Error
+}
+
+// portfolioRow adalah agregat customer per account manager dan status
+type portfolioRow struct {
+	AccountManagerID *string
+	Status           string
+	CustomerCount    int64
+	TotalAverageCost float64
+}
+
+// loadPortfolios menghitung portofolio per account manager; amID kosong berarti semua account manager
+// (termasuk yang belum punya customer) ditambah baris customer tanpa account manager.
+// Hanya customer yang terlihat oleh policy user yang ikut dihitung.
+func loadPortfolios(amID string, policy *access.Context) ([]dto.AccountManagerPortfolio, error) {
+	query := policy.Filter(config.DB.Model(&entity.Customer{}), "id").
+		Select("account_manager_id, status, COUNT(*) AS customer_count, COALESCE(SUM(average_cost), 0) AS total_average_cost").
+		Group("account_manager_id, status")
+	if amID != "" {
+		query = query.Where("account_manager_id = ?", amID)
+	}
+	var rows []portfolioRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var managers []entity.AccountManager
+	managerQuery := config.DB.Order("manager_name")
+	if amID != "" {
+		managerQuery = managerQuery.Where("id = ?", amID)
+	}
+	if err := managerQuery.Find(&managers).Error; err != nil {
+		return nil, err
+	}
+
+	portfolios := []dto.AccountManagerPortfolio{}
+	index := map[string]int{}
+	for _, am := range managers {
+		id := am.ID
+		index[id] = len(portfolios)
+		portfolios = append(portfolios, dto.AccountManagerPortfolio{
+			AccountManagerID:   &id,
+			AccountManagerName: am.ManagerName,
+			StatusBreakdown:    []dto.PortfolioStatusBreakdown{},
+		})
+	}
+
+	for _, row := range rows {
+		key := ""
+		if row.AccountManagerID != nil {
+			key = *row.AccountManagerID
+		}
+		i, ok := index[key]
+		if !ok {
+			// customer tanpa account manager (atau account manager yang sudah dihapus)
+			portfolio := dto.AccountManagerPortfolio{
+				AccountManagerID: row.AccountManagerID,
+				StatusBreakdown:  []dto.PortfolioStatusBreakdown{},
+			}
+			if row.AccountManagerID == nil {
+				portfolio.AccountManagerName = "Unassigned"
+			}
+			i = len(portfolios)
+			index[key] = i
+			portfolios = append(portfolios, portfolio)
+		}
+		portfolio := &portfolios[i]
+		portfolio.CustomerCount += row.CustomerCount
+		portfolio.TotalAverageCost += row.TotalAverageCost
+		portfolio.StatusBreakdown = append(portfolio.StatusBreakdown, dto.PortfolioStatusBreakdown{
+			Status:           row.Status,
+			CustomerCount:    row.CustomerCount,
+			TotalAverageCost: row.TotalAverageCost,
+		})
+	}
+
+	for i := range portfolios {
+		breakdown := portfolios[i].StatusBreakdown
+		sort.Slice(breakdown, func(a, b int) bool {
+			if breakdown[a].CustomerCount != breakdown[b].CustomerCount {
+				return breakdown[a].CustomerCount > breakdown[b].CustomerCount
+			}
+			return breakdown[a].Status < breakdown[b].Status
+		})
+	}
+	return portfolios, nil
+}
+
+// @Summary Get account manager portfolios
+// @Description Get customer count, total average cost and status breakdown for every account manager, plus unassigned customers
+// @Tags AccountManager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.AccountManagerPortfolio
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/account-managers/portfolio [get]
+func GetAccountManagerPortfolios(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	portfolios, err := loadPortfolios("", policy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil portofolio account manager"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Portfolios fetched successfully",
+		"data":    portfolios,
+	})
+}
+
+// @Summary Get account manager portfolio
+// @Description Get customer count, total average cost and status breakdown for one account manager
+// @Tags AccountManager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account manager ID"
+// @Success 200 {object} dto.AccountManagerPortfolio
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/account-managers/{id}/portfolio [get]
+func GetAccountManagerPortfolio(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var accountManager entity.AccountManager
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&accountManager).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Account manager tidak ditemukan"})
+		return
+	}
+
+	portfolios, err := loadPortfolios(accountManager.ID, policy)
+	if err != nil || len(portfolios) == 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil portofolio account manager"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Portfolio fetched successfully",
+		"data":    portfolios[0],
+	})
+}
+
+// @Summary Reassign customers to another account manager
+// @Description Move all (or filtered) customers of account manager {id} to to_account_manager_id. Each move is recorded in the customer's ownership history under one batch ID. Use dry_run to preview.
+// @Tags AccountManager
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Current account manager ID"
+// @Param request body dto.ReassignCustomersRequest true "Reassignment request"
+// @Success 200 {object} dto.ReassignCustomersResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/account-managers/{id}/reassign [post]
+func ReassignAccountManagerCustomers(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var from entity.AccountManager
+	if err := config.DB.Unscoped().Where("id = ?", c.Param("id")).First(&from).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Account manager tidak ditemukan"})
+		return
+	}
+
+	var req dto.ReassignCustomersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ToAccountManagerID == from.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account manager tujuan harus berbeda dari account manager asal"})
+		return
+	}
+	var to entity.AccountManager
+	if err := config.DB.Where("id = ?", req.ToAccountManagerID).First(&to).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account manager tujuan tidak ditemukan"})
+		return
+	}
+	if !to.IsActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Account manager tujuan tidak aktif"})
+		return
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	// hanya customer yang terlihat oleh user yang dipindahkan
+	query := policy.Filter(config.DB.Model(&entity.Customer{}).Where("account_manager_id = ?", from.ID), "id")
+	if len(req.CustomerIDs) > 0 {
+		query = query.Where("id IN ?", req.CustomerIDs)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+	if req.GroupID != "" {
+		query = query.Where("id IN (SELECT customer_id FROM customer_groups WHERE group_id = ?)", req.GroupID)
+	}
+
+	customerIDs := []string{}
+	if err := query.Order("name").Pluck("id", &customerIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data customer"})
+		return
+	}
+
+	result := dto.ReassignCustomersResult{
+		FromAccountManagerID: from.ID,
+		ToAccountManagerID:   to.ID,
+		Reassigned:           len(customerIDs),
+		CustomerIDs:          customerIDs,
+		DryRun:               req.DryRun,
+	}
+	if req.DryRun || len(customerIDs) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Reassignment preview",
+			"data":    result,
+		})
+		return
+	}
+
+	batchID := ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+	reason := req.Reason
+	if reason == "" {
+		reason = "Bulk reassignment from " + from.ManagerName + " to " + to.ManagerName
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Customer{}).Where("id IN ?", customerIDs).
+			Update("account_manager_id", to.ID).Error; err != nil {
+			return err
+		}
+		history := make([]entity.CustomerOwnership, 0, len(customerIDs))
+		for _, customerID := range customerIDs {
+			history = append(history, entity.CustomerOwnership{
+				CustomerID:           customerID,
+				FromAccountManagerID: &from.ID,
+				ToAccountManagerID:   &to.ID,
+				ChangedBy:            userID,
+				Reason:               reason,
+				BatchID:              batchID,
+			})
+		}
+		return tx.CreateInBatches(&history, 100).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memindahkan customer"})
+		return
+	}
+
+	result.BatchID = batchID
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Customers reassigned successfully",
+		"data":    result,
+	})
+}
+
+// @Summary Get customer ownership history
+// @Description Get the account manager changes of a customer, newest first
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {array} entity.CustomerOwnership
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/ownership-history [get]
+func GetCustomerOwnershipHistory(c *gin.Context) {
+	var customer entity.Customer
+	if err := config.DB.Unscoped().Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	var history []entity.CustomerOwnership
+	err := config.DB.
+		Preload("FromAccountManager", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ToAccountManager", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("customer_id = ?", customer.ID).
+		Order("created_at DESC").
+		Find(&history).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ownership history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ownership history fetched successfully",
+		"data":    history,
+	})
+}
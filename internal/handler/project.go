@@ -1,26 +1,88 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"customer-api/internal/config"
 	"customer-api/internal/entity"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"customer-api/internal/dto"
 )
 
+// checkCustomerProject memastikan project ada dan dimiliki customer yang sama
+func checkCustomerProject(db *gorm.DB, projectID, customerID string) error {
+	var project entity.Project
+	if err := db.Select("id", "customer_id").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return errors.New("project not found")
+	}
+	if project.CustomerID != customerID {
+		return errors.New("project belongs to another customer")
+	}
+	return nil
+}
+
+// findProject mengambil project berdasarkan param :id, mengirim 404 jika tidak ada
+func findProject(c *gin.Context) (entity.Project, bool) {
+	var project entity.Project
+	if result := config.DB.Where("id = ?", c.Param("id")).First(&project); result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data project"})
+		}
+		return project, false
+	}
+	return project, true
+}
+
+// requireProjectManager mengirim 403 jika user bukan owner, manager project, atau admin
+func requireProjectManager(c *gin.Context, project entity.Project, message string) bool {
+	policy, ok := accessScope(c)
+	if !ok {
+		return false
+	}
+	if policy.IsAdmin {
+		return true
+	}
+	switch projectRoleOf(config.DB, project, policy.UserID) {
+	case "owner", "manager":
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": message})
+	return false
+}
+
+// parseProjectDate membaca tanggal opsional; string kosong berarti tanggal dikosongkan
+func parseProjectDate(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := parseDocumentDate(value)
+	if err != nil {
+		return nil, errors.New(field + ": " + err.Error())
+	}
+	return date, nil
+}
+
 // @Summary Get all Projects
-// @Description Get list of all projects
+// @Description Get list of projects of visible customers
 // @Tags Projects
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Limit"
 // @Param page query int false "Page"
+// @Param customer_id query string false "Filter by customer"
+// @Param owner_id query string false "Filter by owner"
+// @Param member_id query string false "Filter by member user ID"
+// @Param status query string false "planned, active, on_hold, completed or cancelled"
 // @Success 200 {array} entity.Project
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -35,10 +97,24 @@ func ReadProjects(c *gin.Context) {
 		page = 1 // Default page
 	}
 
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	db := policy.Filter(config.DB.Preload("Customer").Preload("Owner"), "customer_id")
+	for _, param := range []string{"customer_id", "owner_id", "status"} {
+		if value := c.Query(param); value != "" {
+			db = db.Where(param+" = ?", value)
+		}
+	}
+	if memberID := c.Query("member_id"); memberID != "" {
+		db = db.Where("id IN (SELECT project_id FROM project_members WHERE user_id = ?)", memberID)
+	}
+
 	var projects []entity.Project
 	offset := (page - 1) * limit
 
-	if result := config.DB.Limit(limit).Offset(offset).Find(&projects); result.Error != nil {
+	if result := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&projects); result.Error != nil {
 		c.JSON(http.StatusNotFound, dto.Response{
 			Status:  http.StatusNotFound,
 			Message: "No projects found",
@@ -64,25 +140,84 @@ func ReadProjects(c *gin.Context) {
 }
 
 // @Summary Create a new Project
-// @Description Create a new project
+// @Description Create a project for a customer. The owner (default: current user) is added as project manager.
 // @Tags Projects
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param project body entity.Project true "Project"
+// @Param project body dto.CreateProjectRequest true "Project"
 // @Success 201 {object} entity.Project
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/projects [post]
 func CreateProject(c *gin.Context) {
-	var project entity.Project
-	if err := c.ShouldBindJSON(&project); err != nil {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req dto.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := config.DB.Create(&project).Error; err != nil {
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", req.CustomerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Customer not found"})
+		return
+	}
+	ownerID := userID
+	if req.OwnerID != nil && *req.OwnerID != "" {
+		ownerID = *req.OwnerID
+	}
+	var owner entity.User
+	if err := config.DB.Where("id = ?", ownerID).First(&owner).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Owner not found"})
+		return
+	}
+
+	project := entity.Project{
+		Name:        req.Name,
+		Description: req.Description,
+		CustomerID:  customer.ID,
+		OwnerID:     &owner.ID,
+		Budget:      req.Budget,
+		HourlyRate:  req.HourlyRate,
+		Status:      req.Status,
+		IsActive:    true,
+	}
+	if project.Status == "" {
+		project.Status = "planned"
+	}
+	var err error
+	if req.StartDate != nil {
+		if project.StartDate, err = parseProjectDate("start_date", *req.StartDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.EndDate != nil {
+		if project.EndDate, err = parseProjectDate("end_date", *req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if project.StartDate != nil && project.EndDate != nil && project.EndDate.Before(*project.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&project).Error; err != nil {
+			return err
+		}
+		// owner otomatis menjadi anggota dengan peran manager
+		return tx.Create(&entity.ProjectMember{ProjectID: project.ID, UserID: owner.ID, Role: "manager"}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data project"})
 		return
 	}
@@ -91,7 +226,7 @@ func CreateProject(c *gin.Context) {
 }
 
 // @Summary Get a Project by ID
-// @Description Get a project by ID
+// @Description Get a project with its customer, owner, members and milestones
 // @Tags Projects
 // @Accept json
 // @Produce json
@@ -105,7 +240,10 @@ func CreateProject(c *gin.Context) {
 func ReadOneProject(c *gin.Context) {
 	var project entity.Project
 	id := c.Param("id")
-	if result := config.DB.Where("id = ?", id).First(&project); result.Error != nil {
+	result := config.DB.Preload("Customer").Preload("Owner").Preload("Members.User").
+		Preload("Milestones", func(db *gorm.DB) *gorm.DB { return db.Order("due_date NULLS LAST, created_at") }).
+		Where("id = ?", id).First(&project)
+	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		} else {
@@ -117,41 +255,91 @@ func ReadOneProject(c *gin.Context) {
 }
 
 // @Summary Update a Project by ID
-// @Description Update a project by ID
+// @Description Update a project by ID. The customer of a project cannot be changed.
 // @Tags Projects
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Project ID"
-// @Param project body entity.Project true "Project"
+// @Param project body dto.UpdateProjectRequest true "Project"
 // @Success 200 {object} entity.Project
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/projects/{id} [put]
 func UpdateProject(c *gin.Context) {
-	var project entity.Project
-	id := c.Param("id")
-
 	// First, find the existing project
-	if result := config.DB.Where("id = ?", id).First(&project); result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data project"})
-		}
+	project, ok := findProject(c)
+	if !ok {
 		return
 	}
 
 	// Bind the new data
-	if err := c.ShouldBindJSON(&project); err != nil {
+	var req dto.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	currentOwnerID := ""
+	if project.OwnerID != nil {
+		currentOwnerID = *project.OwnerID
+	}
+	if req.OwnerID != nil && *req.OwnerID != currentOwnerID {
+		if !requireProjectManager(c, project, "Only the project owner or a manager can change the owner") {
+			return
+		}
+		if *req.OwnerID == "" {
+			project.OwnerID = nil
+		} else {
+			var owner entity.User
+			if err := config.DB.Where("id = ?", *req.OwnerID).First(&owner).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Owner not found"})
+				return
+			}
+			project.OwnerID = &owner.ID
+		}
+	}
+	if req.Budget != nil {
+		project.Budget = *req.Budget
+	}
+	if req.HourlyRate != nil {
+		project.HourlyRate = *req.HourlyRate
+	}
+	var err error
+	if req.StartDate != nil {
+		if project.StartDate, err = parseProjectDate("start_date", *req.StartDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.EndDate != nil {
+		if project.EndDate, err = parseProjectDate("end_date", *req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if project.StartDate != nil && project.EndDate != nil && project.EndDate.Before(*project.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	if req.IsActive != nil {
+		project.IsActive = *req.IsActive
+	}
+
 	// Save the updated project
-	if err := config.DB.Save(&project).Error; err != nil {
+	if err := config.DB.Omit(clause.Associations).Save(&project).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data project"})
 		return
 	}
@@ -169,24 +357,383 @@ func UpdateProject(c *gin.Context) {
 // @Success 204
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/projects/{id} [delete]
 func DeleteProject(c *gin.Context) {
-	var project entity.Project
-	id := c.Param("id")
-	if result := config.DB.Where("id = ?", id).First(&project); result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data project"})
-		}
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	// jam kerja dan invoice tetap merujuk project, jadi project tidak boleh hilang di bawahnya
+	var timeEntries, invoices, invoiceLines int64
+	config.DB.Model(&entity.TimeEntry{}).Where("project_id = ?", project.ID).Count(&timeEntries)
+	config.DB.Model(&entity.Invoice{}).Where("project_id = ?", project.ID).Count(&invoices)
+	config.DB.Model(&entity.InvoiceLine{}).Where("project_id = ?", project.ID).Count(&invoiceLines)
+	if timeEntries > 0 || invoices > 0 || invoiceLines > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Project still has time entries or invoices"})
 		return
 	}
 
-	if err := config.DB.Delete(&project).Error; err != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", project.ID).Delete(&entity.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", project.ID).Delete(&entity.ProjectMilestone{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&project).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus data project"})
 		return
 	}
 
 	c.JSON(http.StatusNoContent, gin.H{})
 }
+
+// @Summary Get project members
+// @Description List the members of a project with their project role
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} entity.ProjectMember
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/members [get]
+func GetProjectMembers(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	members := []entity.ProjectMember{}
+	if err := config.DB.Preload("User").Where("project_id = ?", project.ID).Order("created_at").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project members fetched successfully",
+		"data":    members,
+	})
+}
+
+// @Summary Add project member
+// @Description Add a user to a project with a role (manager, member or viewer)
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param member body dto.ProjectMemberRequest true "Member"
+// @Success 201 {object} entity.ProjectMember
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/members [post]
+func AddProjectMember(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+	if !requireProjectManager(c, project, "Only the project owner or a manager can manage project members") {
+		return
+	}
+
+	var req dto.ProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var user entity.User
+	if err := config.DB.Where("id = ?", req.UserID).First(&user).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+		return
+	}
+	var existing int64
+	config.DB.Model(&entity.ProjectMember{}).Where("project_id = ? AND user_id = ?", project.ID, user.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a member of this project"})
+		return
+	}
+
+	member := entity.ProjectMember{ProjectID: project.ID, UserID: user.ID, Role: req.Role}
+	if member.Role == "" {
+		member.Role = "member"
+	}
+	if err := config.DB.Omit(clause.Associations).Create(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add project member"})
+		return
+	}
+	member.User = &user
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Project member added successfully",
+		"data":    member,
+	})
+}
+
+// @Summary Update project member
+// @Description Change the project role of a member
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param member_id path string true "Project member ID"
+// @Param member body dto.UpdateProjectMemberRequest true "Role"
+// @Success 200 {object} entity.ProjectMember
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/members/{member_id} [put]
+func UpdateProjectMember(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+	if !requireProjectManager(c, project, "Only the project owner or a manager can manage project members") {
+		return
+	}
+
+	var member entity.ProjectMember
+	if err := config.DB.Where("id = ? AND project_id = ?", c.Param("member_id"), project.ID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project member not found"})
+		return
+	}
+
+	var req dto.UpdateProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	member.Role = req.Role
+	if err := config.DB.Save(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project member updated successfully",
+		"data":    member,
+	})
+}
+
+// @Summary Remove project member
+// @Description Remove a member from a project
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param member_id path string true "Project member ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/members/{member_id} [delete]
+func RemoveProjectMember(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+	if !requireProjectManager(c, project, "Only the project owner or a manager can manage project members") {
+		return
+	}
+
+	var member entity.ProjectMember
+	if err := config.DB.Where("id = ? AND project_id = ?", c.Param("member_id"), project.ID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project member not found"})
+		return
+	}
+	if err := config.DB.Delete(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove project member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project member removed successfully"})
+}
+
+// @Summary Get project milestones
+// @Description List the milestones of a project ordered by due date
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} entity.ProjectMilestone
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/milestones [get]
+func GetProjectMilestones(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	milestones := []entity.ProjectMilestone{}
+	if err := config.DB.Where("project_id = ?", project.ID).Order("due_date NULLS LAST, created_at").Find(&milestones).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project milestones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project milestones fetched successfully",
+		"data":    milestones,
+	})
+}
+
+// @Summary Create project milestone
+// @Description Add a milestone to a project
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param milestone body dto.ProjectMilestoneRequest true "Milestone"
+// @Success 201 {object} entity.ProjectMilestone
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/milestones [post]
+func CreateProjectMilestone(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ProjectMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	milestone := entity.ProjectMilestone{
+		ProjectID:   project.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Amount:      req.Amount,
+		Status:      "pending",
+	}
+	if req.DueDate != nil {
+		dueDate, err := parseProjectDate("due_date", *req.DueDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		milestone.DueDate = dueDate
+	}
+
+	if err := config.DB.Create(&milestone).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project milestone"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Project milestone created successfully",
+		"data":    milestone,
+	})
+}
+
+// @Summary Update project milestone
+// @Description Update a milestone. Setting the status to completed records the completion time.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Param milestone body dto.UpdateProjectMilestoneRequest true "Milestone"
+// @Success 200 {object} entity.ProjectMilestone
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/milestones/{milestone_id} [put]
+func UpdateProjectMilestone(c *gin.Context) {
+	var milestone entity.ProjectMilestone
+	if err := config.DB.Where("id = ? AND project_id = ?", c.Param("milestone_id"), c.Param("id")).First(&milestone).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project milestone not found"})
+		return
+	}
+
+	var req dto.UpdateProjectMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name != nil {
+		milestone.Name = *req.Name
+	}
+	if req.Description != nil {
+		milestone.Description = *req.Description
+	}
+	if req.DueDate != nil {
+		dueDate, err := parseProjectDate("due_date", *req.DueDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		milestone.DueDate = dueDate
+	}
+	if req.Amount != nil {
+		milestone.Amount = *req.Amount
+	}
+	if req.Status != nil && *req.Status != milestone.Status {
+		milestone.Status = *req.Status
+		milestone.CompletedAt = nil
+		if milestone.Status == "completed" {
+			now := time.Now()
+			milestone.CompletedAt = &now
+		}
+	}
+
+	if err := config.DB.Save(&milestone).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project milestone"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project milestone updated successfully",
+		"data":    milestone,
+	})
+}
+
+// @Summary Delete project milestone
+// @Description Delete a milestone of a project
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/milestones/{milestone_id} [delete]
+func DeleteProjectMilestone(c *gin.Context) {
+	var milestone entity.ProjectMilestone
+	if err := config.DB.Where("id = ? AND project_id = ?", c.Param("milestone_id"), c.Param("id")).First(&milestone).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project milestone not found"})
+		return
+	}
+	if err := config.DB.Delete(&milestone).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project milestone"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project milestone deleted successfully"})
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get project events
+// @Description List the events of a project, newest first
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} entity.Event
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/events [get]
+func GetProjectEvents(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	events := []entity.Event{}
+	if err := config.DB.Preload("ActivityType").Preload("Attendees").Where("project_id = ?", project.ID).
+		Order("scheduled_at DESC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project events fetched successfully",
+		"data":    events,
+	})
+}
+
+// @Summary Get project activities
+// @Description List the activities linked to a project, newest first
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} dto.ActivityResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/activities [get]
+func GetProjectActivities(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	var activities []entity.Activity
+	if err := config.DB.Where("project_id = ?", project.ID).Order("start_time DESC").Find(&activities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project activities"})
+		return
+	}
+	responses := make([]dto.ActivityResponse, 0, len(activities))
+	for _, activity := range activities {
+		responses = append(responses, toActivityResponse(activity))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project activities fetched successfully",
+		"data":    responses,
+	})
+}
+
+// @Summary Get project invoices
+// @Description List the invoices of a project, newest first
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} dto.InvoiceResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/invoices [get]
+func GetProjectInvoices(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	var invoices []entity.Invoice
+	if err := config.DB.Where("project_id = ?", project.ID).Order("issued_date DESC").Find(&invoices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project invoices"})
+		return
+	}
+	responses := make([]dto.InvoiceResponse, 0, len(invoices))
+	for _, invoice := range invoices {
+		responses = append(responses, convertToInvoiceResponse(invoice))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project invoices fetched successfully",
+		"data":    responses,
+	})
+}
+
+// @Summary Get project budget
+// @Description Compare the project budget with the amount invoiced and paid, with milestone progress
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} dto.ProjectBudgetResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/budget [get]
+func GetProjectBudget(c *gin.Context) {
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+
+	var invoiced struct {
+		Count  int64
+		Amount float64
+		Paid   float64
+	}
+	err := config.DB.Model(&entity.Invoice{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount, COALESCE(SUM(paid_amount), 0) AS paid").
+		Where("project_id = ?", project.ID).Scan(&invoiced).Error
+	var milestones struct {
+		Count     int64
+		Completed int64
+		Amount    float64
+	}
+	if err == nil {
+		err = config.DB.Model(&entity.ProjectMilestone{}).
+			Select("COUNT(*) AS count, COUNT(*) FILTER (WHERE status = 'completed') AS completed, COALESCE(SUM(amount), 0) AS amount").
+			Where("project_id = ?", project.ID).Scan(&milestones).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute project budget"})
+		return
+	}
+
+	response := dto.ProjectBudgetResponse{
+		ProjectID:          project.ID,
+		Budget:             project.Budget,
+		Invoiced:           invoiced.Amount,
+		Paid:               invoiced.Paid,
+		Outstanding:        invoiced.Amount - invoiced.Paid,
+		Remaining:          project.Budget - invoiced.Amount,
+		OverBudget:         project.Budget > 0 && invoiced.Amount > project.Budget,
+		InvoiceCount:       invoiced.Count,
+		MilestoneCount:     milestones.Count,
+		MilestoneCompleted: milestones.Completed,
+		MilestoneAmount:    milestones.Amount,
+	}
+	if project.Budget > 0 {
+		response.InvoicedPercent = invoiced.Amount / project.Budget * 100
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project budget fetched successfully",
+		"data":    response,
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"customer-api/internal/region"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultRegionSearchLimit = 20
+	maxRegionSearchLimit     = 100
+)
+
+// @Summary Get provinces
+// @Description Get all Indonesian provinces
+// @Tags Regions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} region.Region
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/regions/provinces [get]
+func GetProvinces(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Provinces fetched successfully",
+		"data":    region.Children(""),
+	})
+}
+
+// getRegionChildren mengembalikan anak dari wilayah :code jika wilayahnya berada di level parentLevel
+func getRegionChildren(c *gin.Context, parentLevel, message string) {
+	code := c.Param("code")
+	parent, ok := region.Find(code)
+	if !ok || parent.Level != parentLevel {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Region not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"parent":  parent,
+		"data":    region.Children(parent.Code),
+	})
+}
+
+// @Summary Get regencies of a province
+// @Description Get regencies/cities in a province
+// @Tags Regions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Province code" example(31)
+// @Success 200 {array} region.Region
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/regions/provinces/{code}/regencies [get]
+func GetRegencies(c *gin.Context) {
+	getRegionChildren(c, region.LevelProvince, "Regencies fetched successfully")
+}
+
+// @Summary Get districts of a regency
+// @Description Get districts (kecamatan) in a regency/city
+// @Tags Regions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Regency code" example(31.71)
+// @Success 200 {array} region.Region
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/regions/regencies/{code}/districts [get]
+func GetDistricts(c *gin.Context) {
+	getRegionChildren(c, region.LevelRegency, "Districts fetched successfully")
+}
+
+// @Summary Get villages of a district
+// @Description Get villages (desa/kelurahan) in a district, including postal codes when known
+// @Tags Regions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "District code" example(31.71.02)
+// @Success 200 {array} region.Region
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/regions/districts/{code}/villages [get]
+func GetVillages(c *gin.Context) {
+	getRegionChildren(c, region.LevelDistrict, "Villages fetched successfully")
+}
+
+// @Summary Get region by code
+// @Description Look up a region code and return its full path from province down
+// @Tags Regions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Region code" example(31.71.02.1002)
+// @Success 200 {object} region.Path
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/regions/lookup/{code} [get]
+func GetRegion(c *gin.Context) {
+	r, ok := region.Find(c.Param("code"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Region not found"})
+		return
+	}
+	path, _ := region.PathOf(r.Code)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Region fetched successfully",
+		"data":    r,
+		"path":    path,
+	})
+}
+
+// @Summary Search regions
+// @Description Autocomplete regions by name, optionally limited to a level and a parent region
+// @Tags Regions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Name to search"
+// @Param level query string false "province, regency, district or village"
+// @Param parent query string false "Only regions under this code"
+// @Param limit query int false "Max results (default 20, max 100)"
+// @Success 200 {array} region.Region
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/regions/search [get]
+func SearchRegions(c *gin.Context) {
+	level := c.Query("level")
+	switch level {
+	case "", region.LevelProvince, region.LevelRegency, region.LevelDistrict, region.LevelVillage:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid level (must be province, regency, district or village)"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRegionSearchLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	if limit > maxRegionSearchLimit {
+		limit = maxRegionSearchLimit
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Regions fetched successfully",
+		"data":    region.Search(c.Query("q"), level, c.Query("parent"), limit),
+	})
+}
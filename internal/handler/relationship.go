@@ -0,0 +1,639 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// relationshipType menjelaskan arti relasi dari sisi "from" (Label) dan sisi "to" (Inverse)
+type relationshipType struct {
+	Label     string
+	Inverse   string
+	Symmetric bool
+}
+
+var relationshipTypes = map[string]relationshipType{
+	"partner":     {Label: "partner", Inverse: "partner", Symmetric: true},
+	"reseller":    {Label: "reseller of", Inverse: "resold by"},
+	"competitor":  {Label: "competitor", Inverse: "competitor", Symmetric: true},
+	"supplier-of": {Label: "supplier of", Inverse: "supplied by"},
+	"referred-by": {Label: "referred by", Inverse: "referred"},
+}
+
+const (
+	maxRelationshipDepth = 3
+	maxGraphNodes        = 500
+)
+
+// relationshipActive bernilai true jika tanggal berada dalam masa berlaku relasi
+func relationshipActive(relationship entity.CustomerRelationship, day time.Time) bool {
+	if relationship.StartDate != nil && day.Before(*relationship.StartDate) {
+		return false
+	}
+	if relationship.EndDate != nil && day.After(relationship.EndDate.Add(24*time.Hour-time.Nanosecond)) {
+		return false
+	}
+	return true
+}
+
+// relatedCustomer mengubah customer menjadi ringkasan, disamarkan jika di luar akses user
+func relatedCustomer(customer entity.Customer, visible bool) dto.RelatedCustomer {
+	if !visible {
+		return dto.RelatedCustomer{ID: customer.ID, Restricted: true}
+	}
+	return dto.RelatedCustomer{ID: customer.ID, Name: customer.Name, Code: customer.Code, Status: customer.Status}
+}
+
+// visibleCustomers memuat customer berdasarkan id beserta penanda apakah terlihat oleh user
+func visibleCustomers(policy *access.Context, ids []string) (map[string]entity.Customer, map[string]bool, error) {
+	customers := map[string]entity.Customer{}
+	visible := map[string]bool{}
+	if len(ids) == 0 {
+		return customers, visible, nil
+	}
+	var rows []entity.Customer
+	if err := config.DB.Select("id", "name", "code", "status").Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		customers[row.ID] = row
+	}
+	var visibleIDs []string
+	if err := policy.Filter(config.DB.Model(&entity.Customer{}).Where("id IN ?", ids), "id").Pluck("id", &visibleIDs).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+	return customers, visible, nil
+}
+
+// applyRelationshipFilters menerapkan filter type (boleh beberapa, dipisah koma) dan active
+func applyRelationshipFilters(c *gin.Context, db *gorm.DB) (*gorm.DB, bool) {
+	if typeParam := c.Query("type"); typeParam != "" {
+		types := strings.Split(typeParam, ",")
+		for _, t := range types {
+			if _, ok := relationshipTypes[t]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown relationship type: " + t})
+				return nil, false
+			}
+		}
+		db = db.Where("type IN ?", types)
+	}
+	if activeParam := c.Query("active"); activeParam != "" {
+		active, err := strconv.ParseBool(activeParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "active must be true or false"})
+			return nil, false
+		}
+		today := time.Now().Format("2006-01-02")
+		condition := "(start_date IS NULL OR start_date <= ?) AND (end_date IS NULL OR end_date >= ?)"
+		if active {
+			db = db.Where(condition, today, today)
+		} else {
+			db = db.Where("NOT ("+condition+")", today, today)
+		}
+	}
+	return db, true
+}
+
+// bindRelationship memvalidasi request dan mengisi relasi; mengirim response error jika gagal
+func bindRelationship(c *gin.Context, policy *access.Context, relationship *entity.CustomerRelationship) bool {
+	var req dto.CustomerRelationshipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	if _, ok := relationshipTypes[req.Type]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of partner, reseller, competitor, supplier-of, referred-by"})
+		return false
+	}
+	if req.FromCustomerID == req.ToCustomerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A customer cannot have a relationship with itself"})
+		return false
+	}
+
+	for _, id := range []string{req.FromCustomerID, req.ToCustomerID} {
+		var customer entity.Customer
+		if err := config.DB.Where("id = ?", id).First(&customer).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Customer " + id + " not found"})
+			return false
+		}
+		if !policy.CanWrite(customer) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Customer " + id + " not found"})
+			return false
+		}
+	}
+
+	var startDate, endDate *time.Time
+	var err error
+	if req.StartDate != nil && *req.StartDate != "" {
+		if startDate, err = parseDocumentDate(*req.StartDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date: " + err.Error()})
+			return false
+		}
+	}
+	if req.EndDate != nil && *req.EndDate != "" {
+		if endDate, err = parseDocumentDate(*req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end_date: " + err.Error()})
+			return false
+		}
+	}
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return false
+	}
+
+	// Relasi yang sama (untuk tipe simetris: dari arah mana pun) tidak boleh tumpang tindih periodenya
+	duplicate := config.DB.Model(&entity.CustomerRelationship{}).Where("type = ? AND id <> ?", req.Type, relationship.ID)
+	if relationshipTypes[req.Type].Symmetric {
+		duplicate = duplicate.Where("((from_customer_id = ? AND to_customer_id = ?) OR (from_customer_id = ? AND to_customer_id = ?))",
+			req.FromCustomerID, req.ToCustomerID, req.ToCustomerID, req.FromCustomerID)
+	} else {
+		duplicate = duplicate.Where("from_customer_id = ? AND to_customer_id = ?", req.FromCustomerID, req.ToCustomerID)
+	}
+	if endDate != nil {
+		duplicate = duplicate.Where("(start_date IS NULL OR start_date <= ?)", *endDate)
+	}
+	if startDate != nil {
+		duplicate = duplicate.Where("(end_date IS NULL OR end_date >= ?)", *startDate)
+	}
+	var duplicates int64
+	duplicate.Count(&duplicates)
+	if duplicates > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "An overlapping relationship of this type already exists between these customers"})
+		return false
+	}
+
+	relationship.FromCustomerID = req.FromCustomerID
+	relationship.ToCustomerID = req.ToCustomerID
+	relationship.Type = req.Type
+	relationship.StartDate = startDate
+	relationship.EndDate = endDate
+	relationship.Note = req.Note
+	return true
+}
+
+// maskRelationshipCustomers mengosongkan data customer di luar akses user, hanya id yang ditampilkan
+func maskRelationshipCustomers(policy *access.Context, relationship *entity.CustomerRelationship) {
+	for _, side := range []**entity.Customer{&relationship.FromCustomer, &relationship.ToCustomer} {
+		if *side == nil {
+			continue
+		}
+		if visible, _ := policy.CanSee(**side); !visible {
+			*side = &entity.Customer{ID: (*side).ID}
+		}
+	}
+}
+
+// findWritableRelationship mengambil relasi yang kedua customer-nya boleh diubah user,
+// mengirim 404 jika tidak ada atau salah satu sisi di luar akses
+func findWritableRelationship(c *gin.Context, policy *access.Context) (entity.CustomerRelationship, bool) {
+	relationship, ok := findRelationship(c)
+	if !ok {
+		return relationship, false
+	}
+	cache := map[string]bool{}
+	for _, id := range []string{relationship.FromCustomerID, relationship.ToCustomerID} {
+		if !canWriteCustomer(config.DB, policy, cache, id) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Relationship not found"})
+			return relationship, false
+		}
+	}
+	return relationship, true
+}
+
+// findRelationship mengambil relasi berdasarkan param :id, mengirim 404 jika tidak ada
+func findRelationship(c *gin.Context) (entity.CustomerRelationship, bool) {
+	var relationship entity.CustomerRelationship
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&relationship).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relationship not found"})
+		return relationship, false
+	}
+	return relationship, true
+}
+
+// @Summary Get relationship types
+// @Description List the relationship types with how each reads from both sides
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/relationships/types [get]
+func GetRelationshipTypes(c *gin.Context) {
+	codes := make([]string, 0, len(relationshipTypes))
+	for code := range relationshipTypes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	types := make([]gin.H, 0, len(codes))
+	for _, code := range codes {
+		t := relationshipTypes[code]
+		types = append(types, gin.H{"type": code, "label": t.Label, "inverse": t.Inverse, "symmetric": t.Symmetric})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Relationship types fetched successfully",
+		"data":    types,
+	})
+}
+
+// @Summary Get relationships
+// @Description List customer relationships involving at least one visible customer
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Relationship types, comma separated"
+// @Param active query bool false "Only relationships active (true) or inactive (false) today"
+// @Success 200 {array} entity.CustomerRelationship
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/relationships [get]
+func GetRelationships(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	db := config.DB.Preload("FromCustomer").Preload("ToCustomer")
+	if policy.Restricted() {
+		visible := policy.Filter(config.DB.Model(&entity.Customer{}).Select("id"), "id")
+		db = db.Where("from_customer_id IN (?) OR to_customer_id IN (?)", visible, visible)
+	}
+	db, ok = applyRelationshipFilters(c, db)
+	if !ok {
+		return
+	}
+
+	relationships := []entity.CustomerRelationship{}
+	if err := db.Order("created_at DESC").Find(&relationships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch relationships"})
+		return
+	}
+	for i := range relationships {
+		maskRelationshipCustomers(policy, &relationships[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Relationships fetched successfully",
+		"data":    relationships,
+	})
+}
+
+// @Summary Create relationship
+// @Description Create a typed relationship, read as "from <type> to" (e.g. from referred-by to means from was referred by to)
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param relationship body dto.CustomerRelationshipRequest true "Relationship"
+// @Success 201 {object} entity.CustomerRelationship
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/relationships [post]
+func CreateRelationship(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	relationship := entity.CustomerRelationship{CreatedBy: userID}
+	if !bindRelationship(c, policy, &relationship) {
+		return
+	}
+	if err := config.DB.Omit(clause.Associations).Create(&relationship).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create relationship"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Relationship created successfully",
+		"data":    relationship,
+	})
+}
+
+// @Summary Get relationship
+// @Description Get a relationship with both customers
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Relationship ID"
+// @Success 200 {object} entity.CustomerRelationship
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/relationships/{id} [get]
+func GetRelationship(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var relationship entity.CustomerRelationship
+	if err := config.DB.Preload("FromCustomer").Preload("ToCustomer").Where("id = ?", c.Param("id")).First(&relationship).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relationship not found"})
+		return
+	}
+	maskRelationshipCustomers(policy, &relationship)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Relationship fetched successfully",
+		"data":    relationship,
+	})
+}
+
+// @Summary Update relationship
+// @Description Replace a relationship
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Relationship ID"
+// @Param relationship body dto.CustomerRelationshipRequest true "Relationship"
+// @Success 200 {object} entity.CustomerRelationship
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/relationships/{id} [put]
+func UpdateRelationship(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	relationship, ok := findWritableRelationship(c, policy)
+	if !ok {
+		return
+	}
+	if !bindRelationship(c, policy, &relationship) {
+		return
+	}
+	if err := config.DB.Omit(clause.Associations).Save(&relationship).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update relationship"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Relationship updated successfully",
+		"data":    relationship,
+	})
+}
+
+// @Summary Delete relationship
+// @Description Delete a relationship
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Relationship ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/relationships/{id} [delete]
+func DeleteRelationship(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	relationship, ok := findWritableRelationship(c, policy)
+	if !ok {
+		return
+	}
+	if err := config.DB.Delete(&relationship).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete relationship"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Relationship deleted successfully"})
+}
+
+// @Summary Get customer relationships
+// @Description List the relationships of a customer from its own point of view. direction=incoming gives reverse lookups, e.g. type=referred-by&direction=incoming lists the customers this customer referred.
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param type query string false "Relationship types, comma separated"
+// @Param direction query string false "outgoing, incoming or both (default)"
+// @Param active query bool false "Only relationships active (true) or inactive (false) today"
+// @Success 200 {array} dto.CustomerRelationshipView
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/relationships [get]
+func GetCustomerRelationships(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	db := config.DB.Model(&entity.CustomerRelationship{})
+	switch c.DefaultQuery("direction", "both") {
+	case "outgoing":
+		db = db.Where("from_customer_id = ?", customer.ID)
+	case "incoming":
+		db = db.Where("to_customer_id = ?", customer.ID)
+	case "both":
+		db = db.Where("from_customer_id = ? OR to_customer_id = ?", customer.ID, customer.ID)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "direction must be outgoing, incoming or both"})
+		return
+	}
+	db, ok = applyRelationshipFilters(c, db)
+	if !ok {
+		return
+	}
+
+	var relationships []entity.CustomerRelationship
+	if err := db.Order("type, created_at").Find(&relationships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch relationships"})
+		return
+	}
+
+	otherIDs := make([]string, 0, len(relationships))
+	for _, relationship := range relationships {
+		otherID := relationship.ToCustomerID
+		if otherID == customer.ID {
+			otherID = relationship.FromCustomerID
+		}
+		otherIDs = append(otherIDs, otherID)
+	}
+	customers, visible, err := visibleCustomers(policy, otherIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related customers"})
+		return
+	}
+
+	now := time.Now()
+	views := make([]dto.CustomerRelationshipView, 0, len(relationships))
+	for i, relationship := range relationships {
+		relType := relationshipTypes[relationship.Type]
+		view := dto.CustomerRelationshipView{
+			ID:        relationship.ID,
+			Type:      relationship.Type,
+			Direction: "outgoing",
+			Label:     relType.Label,
+			Customer:  relatedCustomer(customers[otherIDs[i]], visible[otherIDs[i]]),
+			StartDate: relationship.StartDate,
+			EndDate:   relationship.EndDate,
+			Active:    relationshipActive(relationship, now),
+		}
+		if relationship.FromCustomerID != customer.ID {
+			view.Direction = "incoming"
+			view.Label = relType.Inverse
+		}
+		if visible[otherIDs[i]] {
+			view.Note = relationship.Note
+		}
+		views = append(views, view)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Customer relationships fetched successfully",
+		"data":    views,
+	})
+}
+
+// @Summary Get customer relationship graph
+// @Description Get the N-hop neighbourhood of a customer in the relationship graph, as nodes and edges for visualisation. Customers hidden by the access policy appear as restricted nodes.
+// @Tags Relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param depth query int false "Number of hops (1-3, default 1)"
+// @Param type query string false "Relationship types, comma separated"
+// @Param active query bool false "Only relationships active (true) or inactive (false) today"
+// @Success 200 {object} dto.RelationshipGraph
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/relationship-graph [get]
+func GetCustomerRelationshipGraph(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "1"))
+	if err != nil || depth < 1 || depth > maxRelationshipDepth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "depth must be between 1 and " + strconv.Itoa(maxRelationshipDepth)})
+		return
+	}
+
+	graph := dto.RelationshipGraph{CustomerID: customer.ID, Depth: depth}
+	hops := map[string]int{customer.ID: 0}
+	order := []string{customer.ID}
+	edges := map[string]entity.CustomerRelationship{}
+	frontier := []string{customer.ID}
+
+	// BFS per tingkat: ambil semua relasi yang menyentuh frontier sekaligus
+	for hop := 1; hop <= depth && len(frontier) > 0 && !graph.Truncated; hop++ {
+		query, ok := applyRelationshipFilters(c, config.DB.Model(&entity.CustomerRelationship{}).
+			Where("from_customer_id IN ? OR to_customer_id IN ?", frontier, frontier))
+		if !ok {
+			return
+		}
+		var relationships []entity.CustomerRelationship
+		if err := query.Order("created_at").Find(&relationships).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch relationships"})
+			return
+		}
+
+		next := []string{}
+		for _, relationship := range relationships {
+			for _, id := range []string{relationship.FromCustomerID, relationship.ToCustomerID} {
+				if _, seen := hops[id]; seen {
+					continue
+				}
+				if len(order) >= maxGraphNodes {
+					graph.Truncated = true
+					continue
+				}
+				hops[id] = hop
+				order = append(order, id)
+				next = append(next, id)
+			}
+			_, fromIn := hops[relationship.FromCustomerID]
+			_, toIn := hops[relationship.ToCustomerID]
+			if fromIn && toIn {
+				edges[relationship.ID] = relationship
+			}
+		}
+		frontier = next
+	}
+
+	customers, visible, err := visibleCustomers(policy, order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related customers"})
+		return
+	}
+
+	graph.Nodes = make([]dto.RelationshipGraphNode, 0, len(order))
+	for _, id := range order {
+		graph.Nodes = append(graph.Nodes, dto.RelationshipGraphNode{
+			RelatedCustomer: relatedCustomer(customers[id], visible[id]),
+			Hops:            hops[id],
+		})
+	}
+
+	now := time.Now()
+	graph.Edges = make([]dto.RelationshipGraphEdge, 0, len(edges))
+	for _, relationship := range edges {
+		graph.Edges = append(graph.Edges, dto.RelationshipGraphEdge{
+			ID:        relationship.ID,
+			From:      relationship.FromCustomerID,
+			To:        relationship.ToCustomerID,
+			Type:      relationship.Type,
+			Directed:  !relationshipTypes[relationship.Type].Symmetric,
+			StartDate: relationship.StartDate,
+			EndDate:   relationship.EndDate,
+			Active:    relationshipActive(relationship, now),
+		})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool { return graph.Edges[i].ID < graph.Edges[j].ID })
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Relationship graph fetched successfully",
+		"data":    graph,
+	})
+}
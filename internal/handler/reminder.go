@@ -0,0 +1,722 @@
+package handler
+
+import (
+	"bytes"
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	reminderTypeBirthday      = "birthday"
+	defaultReminderDays       = 30
+	defaultDigestDays         = 7
+	defaultDigestHour         = 7
+	maxReminderDays           = 366
+	reminderDigestHTTPTimeout = 10 * time.Second
+	defaultSMTPPort           = "587"
+)
+
+// jakartaLocation adalah zona waktu tetap untuk mencocokkan tanggal (bulan dan hari) agar hasilnya
+// tidak bergantung pada zona waktu server
+var jakartaLocation = loadJakartaLocation()
+
+func loadJakartaLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Jakarta"); err == nil {
+		return loc
+	}
+	return time.FixedZone("WIB", 7*60*60)
+}
+
+// reminderFilter berisi filter untuk pencarian tanggal penting contact
+type reminderFilter struct {
+	AccountManagerID string
+	GroupID          string
+	CustomerID       string
+	Type             string
+	Access           *access.Context // nil untuk digest yang dikirim sistem
+}
+
+// reminderRow adalah hasil join contact, customer dan account manager
+type reminderRow struct {
+	ContactID          string
+	ContactName        string
+	JobPosition        string
+	CustomerID         string
+	CustomerName       string
+	AccountManagerID   *string
+	AccountManagerName string
+	Birthdate          *time.Time
+	ContactDateID      string
+	Type               string
+	Label              string
+	Date               time.Time
+	Recurring          bool
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// anniversaryIn mengembalikan tanggal ulang tahun di tahun tertentu; 29 Februari jatuh ke 28 Februari di tahun non-kabisat
+func anniversaryIn(date time.Time, year int, loc *time.Location) time.Time {
+	month, day := date.Month(), date.Day()
+	if month == time.February && day == 29 {
+		if leap := year%4 == 0 && (year%100 != 0 || year%400 == 0); !leap {
+			day = 28
+		}
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// nextOccurrence mengembalikan ulang tahun berikutnya dari date, dihitung mulai hari from
+func nextOccurrence(date, from time.Time) time.Time {
+	next := anniversaryIn(date, from.Year(), from.Location())
+	if next.Before(from) {
+		next = anniversaryIn(date, from.Year()+1, from.Location())
+	}
+	return next
+}
+
+func parseReminderFilter(c *gin.Context) reminderFilter {
+	return reminderFilter{
+		AccountManagerID: c.Query("account_manager_id"),
+		GroupID:          c.Query("group_id"),
+		CustomerID:       c.Query("customer_id"),
+		Type:             c.Query("type"),
+	}
+}
+
+func parseReminderDays(c *gin.Context, fallback int) (int, error) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(fallback)))
+	if err != nil || days < 0 || days > maxReminderDays {
+		return 0, fmt.Errorf("days must be between 0 and %d", maxReminderDays)
+	}
+	return days, nil
+}
+
+// reminderBaseQuery menyiapkan join contact aktif ke customer dan account manager beserta filternya
+func reminderBaseQuery(filter reminderFilter) *gorm.DB {
+	query := config.DB.Table("contacts").
+		Joins("JOIN customers ON customers.id = contacts.customer_id AND customers.deleted_at IS NULL").
+		Joins("LEFT JOIN account_managers ON account_managers.id = customers.account_manager_id").
+		Where("contacts.deleted_at IS NULL AND contacts.active = ?", true)
+
+	if filter.AccountManagerID != "" {
+		query = query.Where("customers.account_manager_id = ?", filter.AccountManagerID)
+	}
+	if filter.GroupID != "" {
+		query = query.Where("customers.id IN (SELECT customer_id FROM customer_groups WHERE group_id = ?)", filter.GroupID)
+	}
+	if filter.CustomerID != "" {
+		query = query.Where("customers.id = ?", filter.CustomerID)
+	}
+	if filter.Access != nil {
+		query = filter.Access.Filter(query, "customers.id")
+	}
+	return query
+}
+
+const reminderSelect = "contacts.id AS contact_id, contacts.name AS contact_name, contacts.job_position, " +
+	"customers.id AS customer_id, customers.name AS customer_name, customers.account_manager_id, " +
+	"account_managers.manager_name AS account_manager_name"
+
+// collectReminders mengumpulkan ulang tahun dan tanggal relasi custom yang jatuh dalam rentang days hari mulai from.
+// Bulan dan hari dibandingkan di zona Asia/Jakarta.
+func collectReminders(filter reminderFilter, from time.Time, days int) ([]dto.UpcomingReminder, error) {
+	from = startOfDay(from.In(jakartaLocation))
+	until := from.AddDate(0, 0, days)
+	reminders := []dto.UpcomingReminder{}
+
+	add := func(row reminderRow, date time.Time, recurring bool) {
+		next := startOfDay(date.In(jakartaLocation))
+		years := 0
+		if recurring {
+			next = nextOccurrence(next, from)
+			years = next.Year() - date.Year()
+		}
+		if next.Before(from) || next.After(until) {
+			return
+		}
+		reminders = append(reminders, dto.UpcomingReminder{
+			ContactID:          row.ContactID,
+			ContactName:        row.ContactName,
+			JobPosition:        row.JobPosition,
+			CustomerID:         row.CustomerID,
+			CustomerName:       row.CustomerName,
+			AccountManagerID:   row.AccountManagerID,
+			AccountManagerName: row.AccountManagerName,
+			ContactDateID:      row.ContactDateID,
+			Type:               row.Type,
+			Label:              row.Label,
+			Date:               date,
+			NextDate:           next.Format("2006-01-02"),
+			DaysUntil:          int((next.Sub(from) + 12*time.Hour) / (24 * time.Hour)),
+			Years:              years,
+		})
+	}
+
+	if filter.Type == "" || filter.Type == reminderTypeBirthday {
+		var rows []reminderRow
+		err := reminderBaseQuery(filter).
+			Select(reminderSelect + ", contacts.birthdate").
+			Where("contacts.birthdate IS NOT NULL").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			row.Type = reminderTypeBirthday
+			row.Label = "Birthday"
+			add(row, *row.Birthdate, true)
+		}
+	}
+
+	if filter.Type != reminderTypeBirthday {
+		query := reminderBaseQuery(filter).
+			Select(reminderSelect + ", contact_dates.id AS contact_date_id, contact_dates.type, contact_dates.label, contact_dates.date, contact_dates.recurring").
+			Joins("JOIN contact_dates ON contact_dates.contact_id = contacts.id AND contact_dates.deleted_at IS NULL")
+		if filter.Type != "" {
+			query = query.Where("contact_dates.type = ?", filter.Type)
+		}
+		var rows []reminderRow
+		if err := query.Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row.Label == "" {
+				row.Label = row.Type
+			}
+			add(row, row.Date, row.Recurring)
+		}
+	}
+
+	sort.SliceStable(reminders, func(i, j int) bool {
+		if reminders[i].DaysUntil != reminders[j].DaysUntil {
+			return reminders[i].DaysUntil < reminders[j].DaysUntil
+		}
+		return reminders[i].ContactName < reminders[j].ContactName
+	})
+	return reminders, nil
+}
+
+// buildReminderDigests mengelompokkan reminder per account manager; policy nil berarti tanpa batasan (scheduler)
+func buildReminderDigests(accountManagerID string, policy *access.Context, from time.Time, days int) ([]dto.ReminderDigest, error) {
+	reminders, err := collectReminders(reminderFilter{AccountManagerID: accountManagerID, Access: policy}, from, days)
+	if err != nil {
+		return nil, err
+	}
+
+	from = startOfDay(from.In(jakartaLocation))
+	digests := []dto.ReminderDigest{}
+	index := make(map[string]int)
+	for _, reminder := range reminders {
+		// contact tanpa account manager tidak punya penerima digest
+		if reminder.AccountManagerID == nil || *reminder.AccountManagerID == "" {
+			continue
+		}
+		amID := *reminder.AccountManagerID
+		i, ok := index[amID]
+		if !ok {
+			i = len(digests)
+			index[amID] = i
+			digests = append(digests, dto.ReminderDigest{
+				AccountManagerID:   amID,
+				AccountManagerName: reminder.AccountManagerName,
+				From:               from.Format("2006-01-02"),
+				To:                 from.AddDate(0, 0, days).Format("2006-01-02"),
+				Reminders:          []dto.UpcomingReminder{},
+			})
+		}
+		digests[i].Reminders = append(digests[i].Reminders, reminder)
+	}
+
+	// email account manager sebagai penerima digest
+	if len(index) > 0 {
+		ids := make([]string, 0, len(index))
+		for id := range index {
+			ids = append(ids, id)
+		}
+		var managers []entity.AccountManager
+		if err := config.DB.Select("id", "email").Where("id IN ?", ids).Find(&managers).Error; err != nil {
+			return nil, err
+		}
+		for _, manager := range managers {
+			digests[index[manager.ID]].AccountManagerEmail = manager.Email
+		}
+	}
+	return digests, nil
+}
+
+// reminderDigestLines memformat isi digest satu baris per reminder
+func reminderDigestLines(digest dto.ReminderDigest) []string {
+	lines := make([]string, 0, len(digest.Reminders))
+	for _, r := range digest.Reminders {
+		lines = append(lines, fmt.Sprintf("%s %s - %s (%s)", r.NextDate, r.Label, r.ContactName, r.CustomerName))
+	}
+	return lines
+}
+
+// sendReminderDigest mengirim digest lewat email ke account manager (jika SMTP_HOST diatur dan
+// account manager punya email) dan ke REMINDER_DIGEST_WEBHOOK_URL (jika diatur). Jika tidak ada
+// kanal yang bisa dipakai, digest ditulis ke log.
+func sendReminderDigest(digest dto.ReminderDigest) error {
+	delivered := false
+	if os.Getenv("SMTP_HOST") != "" && digest.AccountManagerEmail != "" {
+		if err := sendReminderDigestEmail(digest); err != nil {
+			return err
+		}
+		delivered = true
+	}
+	if os.Getenv("REMINDER_DIGEST_WEBHOOK_URL") != "" {
+		if err := postReminderDigest(digest); err != nil {
+			return err
+		}
+		delivered = true
+	}
+	if !delivered {
+		log.Printf("reminder digest for %s (%s): %s", digest.AccountManagerName, digest.AccountManagerID, strings.Join(reminderDigestLines(digest), "; "))
+	}
+	return nil
+}
+
+// sendReminderDigestEmail mengirim digest sebagai email teks ke account manager lewat SMTP_HOST,
+// SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD dan SMTP_FROM
+func sendReminderDigestEmail(digest dto.ReminderDigest) error {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = defaultSMTPPort
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	var message strings.Builder
+	message.WriteString("From: " + from + "\r\n")
+	message.WriteString("To: " + strings.NewReplacer("\r", "", "\n", "").Replace(digest.AccountManagerEmail) + "\r\n")
+	message.WriteString("Subject: Reminder " + digest.From + " - " + digest.To + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString("Halo " + digest.AccountManagerName + ",\r\n\r\n")
+	message.WriteString("Tanggal penting contact anda sampai " + digest.To + ":\r\n\r\n")
+	for _, line := range reminderDigestLines(digest) {
+		message.WriteString("- " + line + "\r\n")
+	}
+
+	return smtp.SendMail(host+":"+port, auth, from, []string{digest.AccountManagerEmail}, []byte(message.String()))
+}
+
+// postReminderDigest mengirim digest sebagai JSON ke REMINDER_DIGEST_WEBHOOK_URL
+func postReminderDigest(digest dto.ReminderDigest) error {
+	webhook := os.Getenv("REMINDER_DIGEST_WEBHOOK_URL")
+	body, err := json.Marshal(digest)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: reminderDigestHTTPTimeout}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func reminderDigestDays() int {
+	if v, err := strconv.Atoi(os.Getenv("REMINDER_DIGEST_DAYS")); err == nil && v >= 0 && v <= maxReminderDays {
+		return v
+	}
+	return defaultDigestDays
+}
+
+// runReminderDigest membuat dan mengirim digest untuk semua (atau satu) account manager
+func runReminderDigest(accountManagerID string) (int, error) {
+	digests, err := buildReminderDigests(accountManagerID, nil, time.Now(), reminderDigestDays())
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, digest := range digests {
+		if err := sendReminderDigest(digest); err != nil {
+			log.Printf("failed to send reminder digest to %s: %v", digest.AccountManagerID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// StartReminderDigestScheduler menjalankan digest harian pada jam REMINDER_DIGEST_HOUR WIB (default 07:00).
+// Set REMINDER_DIGEST_ENABLED=false untuk menonaktifkan.
+func StartReminderDigestScheduler() {
+	if enabled, err := strconv.ParseBool(os.Getenv("REMINDER_DIGEST_ENABLED")); err == nil && !enabled {
+		return
+	}
+	hour := defaultDigestHour
+	if v, err := strconv.Atoi(os.Getenv("REMINDER_DIGEST_HOUR")); err == nil && v >= 0 && v < 24 {
+		hour = v
+	}
+
+	go func() {
+		for {
+			now := time.Now().In(jakartaLocation)
+			next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, jakartaLocation)
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			time.Sleep(time.Until(next))
+
+			sent, err := runReminderDigest("")
+			if err != nil {
+				log.Printf("reminder digest failed: %v", err)
+				continue
+			}
+			log.Printf("reminder digest sent to %d account managers", sent)
+		}
+	}()
+}
+
+// @Summary Get upcoming birthdays
+// @Description Get contacts whose birthday falls within the next N days, optionally filtered by account manager, group or customer
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Window in days (default 30)"
+// @Param account_manager_id query string false "Filter by account manager"
+// @Param group_id query string false "Filter by customer group"
+// @Param customer_id query string false "Filter by customer"
+// @Success 200 {array} dto.UpcomingReminder
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/reminders/birthdays [get]
+func GetUpcomingBirthdays(c *gin.Context) {
+	days, err := parseReminderDays(c, defaultReminderDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	filter := parseReminderFilter(c)
+	filter.Type = reminderTypeBirthday
+	filter.Access = policy
+	reminders, err := collectReminders(filter, time.Now(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upcoming birthdays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Upcoming birthdays fetched successfully",
+		"data":    reminders,
+	})
+}
+
+// @Summary Get upcoming relationship dates
+// @Description Get birthdays and custom relationship dates (work anniversary, first deal date, ...) within the next N days
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Window in days (default 30)"
+// @Param type query string false "Filter by type (birthday, work_anniversary, first_deal, ...)"
+// @Param account_manager_id query string false "Filter by account manager"
+// @Param group_id query string false "Filter by customer group"
+// @Param customer_id query string false "Filter by customer"
+// @Success 200 {array} dto.UpcomingReminder
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/reminders/upcoming [get]
+func GetUpcomingReminders(c *gin.Context) {
+	days, err := parseReminderDays(c, defaultReminderDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	filter := parseReminderFilter(c)
+	filter.Access = policy
+	reminders, err := collectReminders(filter, time.Now(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upcoming reminders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Upcoming reminders fetched successfully",
+		"data":    reminders,
+	})
+}
+
+// @Summary Preview reminder digest
+// @Description Preview the daily digest grouped per responsible account manager
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param account_manager_id query string false "Only build the digest for this account manager"
+// @Param days query int false "Window in days (default REMINDER_DIGEST_DAYS or 7)"
+// @Success 200 {array} dto.ReminderDigest
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/reminders/digest [get]
+func GetReminderDigest(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	days, err := parseReminderDays(c, reminderDigestDays())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	digests, err := buildReminderDigests(c.Query("account_manager_id"), policy, time.Now(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build reminder digest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reminder digest fetched successfully",
+		"data":    digests,
+	})
+}
+
+// @Summary Send reminder digest
+// @Description Send the reminder digest now instead of waiting for the daily schedule. Admin only.
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param account_manager_id query string false "Only send the digest for this account manager"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/reminders/digest/send [post]
+func SendReminderDigest(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	sent, err := runReminderDigest(c.Query("account_manager_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send reminder digest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reminder digest sent successfully",
+		"sent":    sent,
+	})
+}
+
+// @Summary Get contact relationship dates
+// @Description Get custom relationship dates of a contact
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contact ID"
+// @Success 200 {array} entity.ContactDate
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/contacts/{id}/dates [get]
+func GetContactDates(c *gin.Context) {
+	contactID := c.Param("id")
+
+	var contact entity.Contact
+	if err := config.DB.Where("id = ?", contactID).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	var dates []entity.ContactDate
+	if err := config.DB.Where("contact_id = ?", contactID).Order("date ASC").Find(&dates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contact dates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dates)
+}
+
+// @Summary Add contact relationship date
+// @Description Add a custom relationship date (work anniversary, first deal date, ...) to a contact
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contact ID"
+// @Param date body dto.ContactDateRequest true "Relationship date"
+// @Success 201 {object} entity.ContactDate
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/contacts/{id}/dates [post]
+func CreateContactDate(c *gin.Context) {
+	contactID := c.Param("id")
+
+	var req dto.ContactDateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var contact entity.Contact
+	if err := config.DB.Where("id = ?", contactID).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		return
+	}
+	if strings.TrimSpace(req.Type) == reminderTypeBirthday {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Birthday is stored on the contact birthdate field"})
+		return
+	}
+
+	contactDate := entity.ContactDate{
+		ContactID: contact.ID,
+		Type:      strings.TrimSpace(req.Type),
+		Label:     req.Label,
+		Date:      date,
+		Recurring: true,
+		Notes:     req.Notes,
+	}
+	if req.Recurring != nil {
+		contactDate.Recurring = *req.Recurring
+	}
+
+	// Select("*") agar recurring=false tetap tersimpan walau ada default:true
+	if err := config.DB.Select("*").Create(&contactDate).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contact date"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, contactDate)
+}
+
+// @Summary Update contact relationship date
+// @Description Update a custom relationship date
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contact date ID"
+// @Param date body dto.ContactDateRequest true "Relationship date"
+// @Success 200 {object} entity.ContactDate
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/contact-dates/{id} [put]
+func UpdateContactDate(c *gin.Context) {
+	id := c.Param("id")
+
+	var contactDate entity.ContactDate
+	if err := config.DB.Where("id = ?", id).First(&contactDate).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact date not found"})
+		return
+	}
+
+	var req dto.ContactDateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		return
+	}
+	if strings.TrimSpace(req.Type) == reminderTypeBirthday {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Birthday is stored on the contact birthdate field"})
+		return
+	}
+
+	contactDate.Type = strings.TrimSpace(req.Type)
+	contactDate.Label = req.Label
+	contactDate.Date = date
+	contactDate.Notes = req.Notes
+	if req.Recurring != nil {
+		contactDate.Recurring = *req.Recurring
+	}
+
+	if err := config.DB.Save(&contactDate).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contact date"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contactDate)
+}
+
+// @Summary Delete contact relationship date
+// @Description Delete a custom relationship date
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contact date ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/contact-dates/{id} [delete]
+func DeleteContactDate(c *gin.Context) {
+	id := c.Param("id")
+
+	result := config.DB.Where("id = ?", id).Delete(&entity.ContactDate{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact date"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact date not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contact date deleted successfully"})
+}
@@ -16,6 +16,10 @@ type RoleInput struct {
 
 // CreateRole - Hapus swagger annotations
 func CreateRole(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	var input RoleInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -24,7 +28,7 @@ func CreateRole(c *gin.Context) {
 
 	// Check if role name already exists
 	var existingRole entity.Role
-	if result := config.DB.Where("role_name = ?", input.RoleName).First(&existingRole); result.Error == nil {
+	if result := config.DB.Where("LOWER(role_name) = LOWER(?)", input.RoleName).First(&existingRole); result.Error == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Role name sudah digunakan"})
 		return
 	}
@@ -89,6 +93,10 @@ func GetRole(c *gin.Context) {
 
 // UpdateRole - Hapus swagger annotations
 func UpdateRole(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	id := c.Param("id")
 	var role entity.Role
 
@@ -105,7 +113,7 @@ func UpdateRole(c *gin.Context) {
 
 	// Check if role name already exists (exclude current role)
 	var existingRole entity.Role
-	if result := config.DB.Where("role_name = ? AND id != ?", input.RoleName, id).First(&existingRole); result.Error == nil {
+	if result := config.DB.Where("LOWER(role_name) = LOWER(?) AND id != ?", input.RoleName, id).First(&existingRole); result.Error == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Role name sudah digunakan"})
 		return
 	}
@@ -126,6 +134,10 @@ func UpdateRole(c *gin.Context) {
 
 // DeleteRole - Hapus swagger annotations
 func DeleteRole(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	id := c.Param("id")
 	var role entity.Role
 
@@ -142,9 +154,9 @@ func DeleteRole(c *gin.Context) {
 		return
 	}
 
-	// Prevent deletion of default roles (ID 1 and 2)
+	// Prevent deletion of default roles (ID 1 and 2) and the admin role
 	roleID, _ := strconv.Atoi(id)
-	if roleID == 1 || roleID == 2 {
+	if roleID == 1 || roleID == 2 || role.IsAdmin {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Role default tidak dapat dihapus"})
 		return
 	}
@@ -161,13 +173,13 @@ func DeleteRole(c *gin.Context) {
 func SetupDefaultRoles(c *gin.Context) {
 	// Create default roles if they don't exist
 	defaultRoles := []entity.Role{
-		{ID: "1", RoleName: "Admin"},
+		{ID: "1", RoleName: "Admin", IsAdmin: true},
 		{ID: "2", RoleName: "User"},
 	}
 
 	for _, role := range defaultRoles {
 		var existingRole entity.Role
-		if result := config.DB.First(&existingRole, role.ID); result.Error != nil {
+		if result := config.DB.Where("is_admin = ? AND LOWER(role_name) = LOWER(?)", role.IsAdmin, role.RoleName).First(&existingRole); result.Error != nil {
 			config.DB.Create(&role)
 		}
 	}
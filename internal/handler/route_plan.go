@@ -0,0 +1,454 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxRouteStops          = 200
+	maxTwoOptPasses        = 50
+	defaultVisitMinutes    = 60
+	defaultTravelSpeedKmh  = 30.0
+	defaultVisitActivity   = "Visit"
+	routeSlotRoundingMins  = 5
+	noRouteCoordinatesText = "no address with coordinates"
+)
+
+// geoPoint adalah satu titik koordinat
+type geoPoint struct {
+	Lat, Lng float64
+}
+
+// routeCandidate adalah satu kunjungan yang akan diurutkan
+type routeCandidate struct {
+	Customer entity.Customer
+	Activity *entity.Activity
+	Address  entity.Address
+	Point    geoPoint
+}
+
+// planVisitOrder mengurutkan titik kunjungan dengan nearest-neighbour lalu diperbaiki dengan 2-opt.
+// Index 0 pada matriks jarak adalah titik awal; hasilnya urutan index points (berbasis 0).
+func planVisitOrder(start geoPoint, points []geoPoint, returnToStart bool) []int {
+	n := len(points)
+	if n == 0 {
+		return []int{}
+	}
+
+	all := append([]geoPoint{start}, points...)
+	dist := make([][]float64, n+1)
+	for i := range dist {
+		dist[i] = make([]float64, n+1)
+		for j := range dist[i] {
+			if i != j {
+				dist[i][j] = haversineKm(all[i].Lat, all[i].Lng, all[j].Lat, all[j].Lng)
+			}
+		}
+	}
+
+	// Nearest neighbour dari titik awal
+	route := make([]int, 0, n+1)
+	route = append(route, 0)
+	visited := make([]bool, n+1)
+	visited[0] = true
+	for len(route) <= n {
+		last := route[len(route)-1]
+		next, best := -1, math.MaxFloat64
+		for j := 1; j <= n; j++ {
+			if !visited[j] && dist[last][j] < best {
+				next, best = j, dist[last][j]
+			}
+		}
+		visited[next] = true
+		route = append(route, next)
+	}
+
+	// 2-opt: balik segmen route[i..j] selama total jarak berkurang. Titik awal tidak ikut dibalik;
+	// jika tidak kembali ke titik awal, ujung rute bebas (tidak ada edge setelah titik terakhir).
+	legKm := func(from, to int) float64 {
+		if to < 0 {
+			return 0
+		}
+		return dist[from][to]
+	}
+	after := func(pos int) int {
+		if pos < n {
+			return route[pos+1]
+		}
+		if returnToStart {
+			return 0
+		}
+		return -1
+	}
+	for pass := 0; pass < maxTwoOptPasses; pass++ {
+		improved := false
+		for i := 1; i < n; i++ {
+			for j := i + 1; j <= n; j++ {
+				next := after(j)
+				before := dist[route[i-1]][route[i]] + legKm(route[j], next)
+				reversed := dist[route[i-1]][route[j]] + legKm(route[i], next)
+				if reversed < before-1e-9 {
+					for l, r := i, j; l < r; l, r = l+1, r-1 {
+						route[l], route[r] = route[r], route[l]
+					}
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	order := make([]int, n)
+	for i := 1; i <= n; i++ {
+		order[i-1] = route[i] - 1
+	}
+	return order
+}
+
+// visitAddresses mengembalikan alamat kunjungan per customer: alamat aktif berkoordinat,
+// alamat utama didahulukan
+func visitAddresses(customerIDs []string) (map[string]entity.Address, error) {
+	result := map[string]entity.Address{}
+	if len(customerIDs) == 0 {
+		return result, nil
+	}
+	var addresses []entity.Address
+	err := config.DB.
+		Where("customer_id IN ? AND active = ? AND latitude IS NOT NULL AND longitude IS NOT NULL", customerIDs, true).
+		Order("main DESC").Order("created_at").
+		Find(&addresses).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		if _, ok := result[address.CustomerID]; !ok {
+			result[address.CustomerID] = address
+		}
+	}
+	return result, nil
+}
+
+// loadCustomerRouteCandidates menyiapkan kandidat kunjungan dari daftar customer ID
+func loadCustomerRouteCandidates(customerIDs []string) ([]routeCandidate, []dto.RouteSkipped, error) {
+	skipped := []dto.RouteSkipped{}
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, id := range customerIDs {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var customers []entity.Customer
+	if err := config.DB.Where("id IN ?", ids).Find(&customers).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := map[string]entity.Customer{}
+	for _, customer := range customers {
+		byID[customer.ID] = customer
+	}
+
+	addresses, err := visitAddresses(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := []routeCandidate{}
+	for _, id := range ids {
+		customer, ok := byID[id]
+		if !ok {
+			skipped = append(skipped, dto.RouteSkipped{CustomerID: id, Reason: "customer not found"})
+			continue
+		}
+		address, ok := addresses[id]
+		if !ok {
+			skipped = append(skipped, dto.RouteSkipped{CustomerID: id, Reason: noRouteCoordinatesText})
+			continue
+		}
+		candidates = append(candidates, routeCandidate{
+			Customer: customer,
+			Address:  address,
+			Point:    geoPoint{*address.Latitude, *address.Longitude},
+		})
+	}
+	return candidates, skipped, nil
+}
+
+// loadActivityRouteCandidates menyiapkan kandidat kunjungan dari activity user pada satu hari.
+// Koordinat diambil dari alamat activity, atau alamat kunjungan customer jika activity tidak punya alamat.
+func loadActivityRouteCandidates(userID string, day time.Time) ([]routeCandidate, []dto.RouteSkipped, error) {
+	skipped := []dto.RouteSkipped{}
+	var activities []entity.Activity
+	err := config.DB.Preload("Customer").
+		Where("start_time >= ? AND start_time < ?", day, day.AddDate(0, 0, 1)).
+		Where("LOWER(status) NOT IN ?", []string{"completed", "cancelled", "canceled"}).
+		Where("created_by = ? OR id IN (SELECT activity_id FROM activity_attendees WHERE user_id = ?)", userID, userID).
+		Order("start_time").
+		Find(&activities).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	customerIDs := []string{}
+	addressIDs := []string{}
+	for _, activity := range activities {
+		customerIDs = append(customerIDs, activity.CustomerID)
+		if activity.AddressID != nil {
+			addressIDs = append(addressIDs, *activity.AddressID)
+		}
+	}
+	fallback, err := visitAddresses(customerIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	explicit := map[string]entity.Address{}
+	if len(addressIDs) > 0 {
+		var addresses []entity.Address
+		if err := config.DB.Where("id IN ?", addressIDs).Find(&addresses).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, address := range addresses {
+			explicit[address.ID] = address
+		}
+	}
+
+	candidates := []routeCandidate{}
+	for i := range activities {
+		activity := &activities[i]
+		address, ok := entity.Address{}, false
+		if activity.AddressID != nil {
+			address, ok = explicit[*activity.AddressID]
+			ok = ok && address.Latitude != nil && address.Longitude != nil
+		}
+		if !ok {
+			address, ok = fallback[activity.CustomerID]
+		}
+		if !ok {
+			skipped = append(skipped, dto.RouteSkipped{CustomerID: activity.CustomerID, ActivityID: activity.ID, Reason: noRouteCoordinatesText})
+			continue
+		}
+		candidates = append(candidates, routeCandidate{
+			Customer: activity.Customer,
+			Activity: activity,
+			Address:  address,
+			Point:    geoPoint{*address.Latitude, *address.Longitude},
+		})
+	}
+	return candidates, skipped, nil
+}
+
+// roundUpToMinutes membulatkan waktu ke atas ke kelipatan menit tertentu
+func roundUpToMinutes(t time.Time, minutes int) time.Time {
+	step := time.Duration(minutes) * time.Minute
+	rounded := t.Truncate(step)
+	if rounded.Before(t) {
+		rounded = rounded.Add(step)
+	}
+	return rounded
+}
+
+// @Summary Plan a visit route
+// @Description Propose a visit order that minimizes travel distance from a start point (nearest-neighbour + 2-opt on straight-line distances). Plan either customer_ids, or the planned activities of a user on a date. With start_time the stops get time slots; with create_activities (customer mode only) a visit activity is created for each slot.
+// @Tags Activities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param plan body dto.RoutePlanRequest true "Route plan request"
+// @Success 200 {object} dto.RoutePlanResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/activities/route-plan [post]
+func PlanVisitRoute(c *gin.Context) {
+	var req dto.RoutePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateCoordinates(req.StartLatitude, req.StartLongitude); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	customerMode := len(req.CustomerIDs) > 0
+	if customerMode == (req.Date != "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide either customer_ids or date"})
+		return
+	}
+	if req.CreateActivities && !customerMode {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "create_activities is only available when planning customer_ids"})
+		return
+	}
+
+	var slotStart time.Time
+	if req.StartTime != "" {
+		t, err := time.Parse(time.RFC3339, req.StartTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_time format. Use RFC3339 format (e.g., 2024-01-15T09:00:00+07:00)"})
+			return
+		}
+		slotStart = t
+	} else if req.CreateActivities {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_time is required to create activities"})
+		return
+	}
+	if req.VisitMinutes < 0 || req.TravelSpeedKmh < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visit_minutes and travel_speed_kmh must not be negative"})
+		return
+	}
+	if req.VisitMinutes == 0 {
+		req.VisitMinutes = defaultVisitMinutes
+	}
+	if req.TravelSpeedKmh == 0 {
+		req.TravelSpeedKmh = defaultTravelSpeedKmh
+	}
+
+	var candidates []routeCandidate
+	var skipped []dto.RouteSkipped
+	var err error
+	if customerMode {
+		candidates, skipped, err = loadCustomerRouteCandidates(req.CustomerIDs)
+	} else {
+		day, parseErr := time.ParseInLocation("2006-01-02", req.Date, jakartaLocation)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		// hanya admin yang boleh merencanakan kunjungan user lain
+		planUserID := userID
+		if req.UserID != "" && req.UserID != userID {
+			policy, ok := accessScope(c)
+			if !ok {
+				return
+			}
+			if !policy.IsAdmin {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can plan visits of another user"})
+				return
+			}
+			planUserID = req.UserID
+		}
+		candidates, skipped, err = loadActivityRouteCandidates(planUserID, day)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load visits"})
+		return
+	}
+	if len(candidates) > maxRouteStops {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many stops to plan at once"})
+		return
+	}
+
+	start := geoPoint{*req.StartLatitude, *req.StartLongitude}
+	points := make([]geoPoint, len(candidates))
+	for i, candidate := range candidates {
+		points[i] = candidate.Point
+	}
+	order := planVisitOrder(start, points, req.ReturnToStart)
+
+	response := dto.RoutePlanResponse{Stops: []dto.RouteStop{}, Skipped: skipped}
+	prev := start
+	cursor := slotStart
+	total := 0.0
+	for seq, index := range order {
+		candidate := candidates[index]
+		leg := haversineKm(prev.Lat, prev.Lng, candidate.Point.Lat, candidate.Point.Lng)
+		total += leg
+		stop := dto.RouteStop{
+			Sequence:      seq + 1,
+			CustomerID:    candidate.Customer.ID,
+			CustomerName:  candidate.Customer.Name,
+			AddressID:     candidate.Address.ID,
+			AddressName:   candidate.Address.Name,
+			Address:       candidate.Address.Address,
+			Latitude:      candidate.Point.Lat,
+			Longitude:     candidate.Point.Lng,
+			LegDistanceKm: roundKm(leg),
+			CumulativeKm:  roundKm(total),
+		}
+		if candidate.Activity != nil {
+			stop.ActivityID = candidate.Activity.ID
+		}
+		if !slotStart.IsZero() {
+			travel := time.Duration(leg / req.TravelSpeedKmh * float64(time.Hour))
+			cursor = roundUpToMinutes(cursor.Add(travel), routeSlotRoundingMins)
+			stop.PlannedStart = cursor.Format(time.RFC3339)
+			cursor = cursor.Add(time.Duration(req.VisitMinutes) * time.Minute)
+			stop.PlannedEnd = cursor.Format(time.RFC3339)
+		}
+		response.Stops = append(response.Stops, stop)
+		prev = candidate.Point
+	}
+	if req.ReturnToStart && len(order) > 0 {
+		response.ReturnDistanceKm = roundKm(haversineKm(prev.Lat, prev.Lng, start.Lat, start.Lng))
+		total += response.ReturnDistanceKm
+	}
+	response.TotalDistanceKm = roundKm(total)
+
+	if req.CreateActivities && len(response.Stops) > 0 {
+		activityType := req.ActivityType
+		if activityType == "" {
+			activityType = defaultVisitActivity
+		}
+		created := []entity.Activity{}
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			for _, stop := range response.Stops {
+				startTime, _ := time.Parse(time.RFC3339, stop.PlannedStart)
+				endTime, _ := time.Parse(time.RFC3339, stop.PlannedEnd)
+				title := req.Title
+				if title == "" {
+					title = "Visit " + stop.CustomerName
+				}
+				addressID := stop.AddressID
+				activity := entity.Activity{
+					CustomerID:   stop.CustomerID,
+					AddressID:    &addressID,
+					Title:        title,
+					Type:         activityType,
+					StartTime:    startTime,
+					EndTime:      endTime,
+					LocationName: stop.AddressName,
+					Status:       "planned",
+					CreatedBy:    userID,
+				}
+				if err := tx.Create(&activity).Error; err != nil {
+					return err
+				}
+				created = append(created, activity)
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activities"})
+			return
+		}
+		for i, activity := range created {
+			response.Stops[i].ActivityID = activity.ID
+			response.CreatedActivities = append(response.CreatedActivities, toActivityResponse(activity))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Route planned successfully",
+		"data":    response,
+	})
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// segmentOtherPrefix menandai GroupConfig.Field yang mengacu ke custom field (Other.Key)
+const segmentOtherPrefix = "other:"
+
+// segmentColumns adalah kolom customer yang dapat disegmentasi; true berarti kolom angka
+var segmentColumns = map[string]bool{
+	"status":             false,
+	"category":           false,
+	"rating":             true,
+	"average_cost":       true,
+	"team_id":            false,
+	"account_manager_id": false,
+}
+
+// resolveSegmentField memvalidasi GroupConfig.Field dan mengembalikan apakah nilainya angka
+// serta key custom field jika field mengacu ke Other
+func resolveSegmentField(field string) (bool, string, error) {
+	if strings.HasPrefix(field, segmentOtherPrefix) {
+		key := strings.TrimPrefix(field, segmentOtherPrefix)
+		var definition entity.OthersConfig
+		if err := config.DB.Where("key = ?", key).First(&definition).Error; err != nil {
+			return false, "", fmt.Errorf("custom field '%s' is not defined", key)
+		}
+		return definition.DataType == fieldTypeNumber, key, nil
+	}
+	numeric, ok := segmentColumns[field]
+	if !ok {
+		columns := make([]string, 0, len(segmentColumns))
+		for column := range segmentColumns {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		return false, "", fmt.Errorf("field must be one of %s or %s<custom field key>", strings.Join(columns, ", "), segmentOtherPrefix)
+	}
+	return numeric, "", nil
+}
+
+// validateSegmentBucket memastikan bucket punya nilai atau rentang yang sesuai dengan tipe field
+func validateSegmentBucket(numeric bool, req dto.CreateGroupConfigDetailRequest) error {
+	hasRange := req.MinValue != nil || req.MaxValue != nil
+	if len(req.Values) == 0 && !hasRange {
+		return fmt.Errorf("bucket needs values or a min_value/max_value range")
+	}
+	if hasRange && !numeric {
+		return fmt.Errorf("ranges can only be used on numeric fields")
+	}
+	if req.MinValue != nil && req.MaxValue != nil && *req.MinValue >= *req.MaxValue {
+		return fmt.Errorf("min_value must be less than max_value")
+	}
+	return nil
+}
+
+// segmentRow adalah jumlah customer per bucket; Bucket -1 berarti tidak masuk bucket manapun
+type segmentRow struct {
+	Bucket int
+	Count  int64
+	Total  float64
+}
+
+// segmentBucketCase menyusun ekspresi CASE yang mengembalikan index bucket pertama yang cocok
+// (nilai sama tanpa membedakan huruf, atau angka dalam rentang [MinValue, MaxValue)), atau -1
+func segmentBucketCase(details []entity.GroupConfigDetail, valueExpr, numberExpr string, numeric bool) (string, []interface{}) {
+	var sql strings.Builder
+	args := []interface{}{}
+	for i, detail := range details {
+		conditions := []string{}
+		if len(detail.Values) > 0 {
+			values := make([]string, 0, len(detail.Values))
+			for _, v := range detail.Values {
+				values = append(values, strings.ToLower(strings.TrimSpace(v)))
+			}
+			conditions = append(conditions, "LOWER("+valueExpr+") IN ?")
+			args = append(args, values)
+		}
+		if numeric && (detail.MinValue != nil || detail.MaxValue != nil) {
+			bounds := []string{}
+			if detail.MinValue != nil {
+				bounds = append(bounds, numberExpr+" >= ?")
+				args = append(args, *detail.MinValue)
+			}
+			if detail.MaxValue != nil {
+				bounds = append(bounds, numberExpr+" < ?")
+				args = append(args, *detail.MaxValue)
+			}
+			conditions = append(conditions, "("+strings.Join(bounds, " AND ")+")")
+		}
+		if len(conditions) == 0 {
+			continue
+		}
+		sql.WriteString(" WHEN " + strings.Join(conditions, " OR ") + " THEN " + strconv.Itoa(i))
+	}
+	if sql.Len() == 0 {
+		return "-1", args
+	}
+	return "CASE" + sql.String() + " ELSE -1 END", args
+}
+
+// buildSegmentReport menghitung jumlah customer dan total AverageCost per bucket; bucket
+// ditentukan di SQL sehingga field angka tidak dikelompokkan per nilai mentah.
+// Customer yang tidak terlihat oleh user (access policy) tidak dihitung.
+func buildSegmentReport(groupConfig entity.GroupConfig, numeric bool, otherKey string, policy *access.Context) (dto.SegmentReport, error) {
+	report := dto.SegmentReport{
+		GroupConfigID: groupConfig.ID,
+		Name:          groupConfig.Name,
+		Field:         groupConfig.Field,
+		Buckets:       []dto.SegmentBucket{},
+		Unmatched:     dto.SegmentBucket{Name: "Lainnya"},
+	}
+
+	details := append([]entity.GroupConfigDetail{}, groupConfig.Details...)
+	sort.SliceStable(details, func(i, j int) bool {
+		if details[i].SortOrder != details[j].SortOrder {
+			return details[i].SortOrder < details[j].SortOrder
+		}
+		return details[i].Name < details[j].Name
+	})
+	for _, detail := range details {
+		report.Buckets = append(report.Buckets, dto.SegmentBucket{DetailID: detail.ID, Name: detail.Name, Icon: detail.Icon})
+	}
+
+	query := config.DB.Table("customers").Where("customers.deleted_at IS NULL")
+	var valueExpr, numberExpr string
+	if otherKey != "" {
+		query = query.Joins(`LEFT JOIN (SELECT DISTINCT ON (customer_id) customer_id, value FROM others
+			WHERE key = ? AND active = ? AND deleted_at IS NULL ORDER BY customer_id, updated_at DESC) o
+			ON o.customer_id = customers.id`, otherKey, true)
+		valueExpr = "o.value"
+		numberExpr = "CASE WHEN o.value ~ '^-{0,1}[0-9]+(\\.[0-9]+){0,1}$' THEN CAST(o.value AS numeric) END" // tanpa '?' agar tidak dianggap placeholder
+	} else {
+		// nama kolom berasal dari segmentColumns sehingga aman disisipkan
+		column := "customers." + groupConfig.Field
+		valueExpr = "CAST(" + column + " AS text)"
+		numberExpr = column
+	}
+	bucketExpr, args := segmentBucketCase(details, valueExpr, numberExpr, numeric)
+	query = query.Select(bucketExpr+" AS bucket, COUNT(*) AS count, COALESCE(SUM(customers.average_cost), 0) AS total", args...).
+		Group("bucket")
+
+	var rows []segmentRow
+	if err := policy.Filter(query, "customers.id").Scan(&rows).Error; err != nil {
+		return report, err
+	}
+
+	for _, row := range rows {
+		report.Total += row.Count
+		report.TotalAverageCost += row.Total
+
+		bucket := &report.Unmatched
+		if row.Bucket >= 0 && row.Bucket < len(report.Buckets) {
+			bucket = &report.Buckets[row.Bucket]
+		}
+		bucket.Count += row.Count
+		bucket.TotalAverageCost += row.Total
+	}
+
+	roundCost := func(v float64) float64 { return math.Round(v*100) / 100 }
+	for i := range report.Buckets {
+		if report.Buckets[i].Count > 0 {
+			report.Buckets[i].AverageCost = roundCost(report.Buckets[i].TotalAverageCost / float64(report.Buckets[i].Count))
+		}
+	}
+	if report.Unmatched.Count > 0 {
+		report.Unmatched.AverageCost = roundCost(report.Unmatched.TotalAverageCost / float64(report.Unmatched.Count))
+	}
+	return report, nil
+}
+
+// @Summary Get customer segments
+// @Description Count customers and total AverageCost per bucket of a group config. Customers matching no bucket are reported as unmatched.
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group config ID"
+// @Success 200 {object} dto.SegmentReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/{id}/segments [get]
+func GetGroupConfigSegments(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var groupConfig entity.GroupConfig
+	if err := config.DB.Preload("Details", "is_active = ?", true).Where("id = ?", c.Param("id")).First(&groupConfig).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Config group not found"})
+		return
+	}
+
+	numeric, otherKey, err := resolveSegmentField(groupConfig.Field)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := buildSegmentReport(groupConfig, numeric, otherKey, policy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build segment report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Segments fetched successfully",
+		"data":    report,
+	})
+}
+
+// @Summary Get segment dashboard
+// @Description Segment reports for every active group config, for dashboard tiles
+// @Tags GroupConfigs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.SegmentReport
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/group-configs/segments [get]
+func GetSegmentDashboard(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var groupConfigs []entity.GroupConfig
+	if err := config.DB.Preload("Details", "is_active = ?", true).Where("is_active = ?", true).Order("name").Find(&groupConfigs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data config groups"})
+		return
+	}
+
+	reports := make([]dto.SegmentReport, 0, len(groupConfigs))
+	for _, groupConfig := range groupConfigs {
+		numeric, otherKey, err := resolveSegmentField(groupConfig.Field)
+		if err != nil {
+			// config dengan field yang tidak valid (mis. custom field dihapus) dilewati
+			continue
+		}
+		report, err := buildSegmentReport(groupConfig, numeric, otherKey, policy)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung segment"})
+			return
+		}
+		reports = append(reports, report)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Segment dashboard fetched successfully",
+		"data":    reports,
+	})
+}
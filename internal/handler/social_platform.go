@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"customer-api/internal/entity"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// socialPlatform mendeskripsikan aturan handle dan URL profil satu platform
+type socialPlatform struct {
+	Key     string   // kunci internal (lowercase)
+	Name    string   // nama platform yang disimpan di Sosmed.Platform
+	Aliases []string // nama lain yang diterima dari input
+	Hosts   []string // domain yang dikenali saat input berupa URL
+	AtStyle bool     // handle ditampilkan dengan awalan @
+	// parse mengubah path URL (tanpa slash depan) menjadi username, "" jika tidak valid
+	parse func(path string, query url.Values) string
+	// validate mengembalikan error jika username tidak sesuai aturan platform
+	validate func(username string) error
+	// profileURL membentuk URL profil kanonik
+	profileURL func(username string) string
+}
+
+var (
+	instagramHandlePattern = regexp.MustCompile(`^[a-z0-9._]{1,30}$`)
+	tiktokHandlePattern    = regexp.MustCompile(`^[a-z0-9._]{2,24}$`)
+	xHandlePattern         = regexp.MustCompile(`^[a-z0-9_]{1,15}$`)
+	facebookHandlePattern  = regexp.MustCompile(`^[a-z0-9.]{5,50}$`)
+	facebookIDPattern      = regexp.MustCompile(`^[0-9]{5,20}$`)
+	linkedinSlugPattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,99}$`)
+	youtubeHandlePattern   = regexp.MustCompile(`^[a-z0-9._-]{3,30}$`)
+	youtubeChannelPattern  = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+)
+
+// firstPathSegment mengambil segmen pertama dari path URL
+func firstPathSegment(path string) string {
+	return strings.SplitN(strings.Trim(path, "/"), "/", 2)[0]
+}
+
+// Registry platform sosmed yang didukung
+var socialPlatforms = []socialPlatform{
+	{
+		Key:     "instagram",
+		Name:    "Instagram",
+		Aliases: []string{"ig", "insta"},
+		Hosts:   []string{"instagram.com", "instagr.am"},
+		AtStyle: true,
+		parse:   func(path string, _ url.Values) string { return firstPathSegment(path) },
+		validate: func(u string) error {
+			if !instagramHandlePattern.MatchString(u) || strings.HasPrefix(u, ".") || strings.HasSuffix(u, ".") || strings.Contains(u, "..") {
+				return fmt.Errorf("Instagram username must be 1-30 letters, numbers, periods or underscores and cannot start/end with a period")
+			}
+			return nil
+		},
+		profileURL: func(u string) string { return "https://www.instagram.com/" + u + "/" },
+	},
+	{
+		Key:     "tiktok",
+		Name:    "TikTok",
+		Aliases: []string{"tik tok", "tt"},
+		Hosts:   []string{"tiktok.com"},
+		AtStyle: true,
+		parse: func(path string, _ url.Values) string {
+			segment := firstPathSegment(path)
+			if !strings.HasPrefix(segment, "@") {
+				return ""
+			}
+			return strings.TrimPrefix(segment, "@")
+		},
+		validate: func(u string) error {
+			if !tiktokHandlePattern.MatchString(u) || strings.HasSuffix(u, ".") {
+				return fmt.Errorf("TikTok username must be 2-24 letters, numbers, periods or underscores and cannot end with a period")
+			}
+			return nil
+		},
+		profileURL: func(u string) string { return "https://www.tiktok.com/@" + u },
+	},
+	{
+		Key:     "x",
+		Name:    "X",
+		Aliases: []string{"twitter", "x (twitter)", "x/twitter"},
+		Hosts:   []string{"x.com", "twitter.com"},
+		AtStyle: true,
+		parse:   func(path string, _ url.Values) string { return firstPathSegment(path) },
+		validate: func(u string) error {
+			if !xHandlePattern.MatchString(u) {
+				return fmt.Errorf("X username must be 1-15 letters, numbers or underscores")
+			}
+			return nil
+		},
+		profileURL: func(u string) string { return "https://x.com/" + u },
+	},
+	{
+		Key:     "facebook",
+		Name:    "Facebook",
+		Aliases: []string{"fb"},
+		Hosts:   []string{"facebook.com", "fb.com"},
+		parse: func(path string, query url.Values) string {
+			segment := firstPathSegment(path)
+			if segment == "profile.php" {
+				return query.Get("id")
+			}
+			return segment
+		},
+		validate: func(u string) error {
+			if !facebookHandlePattern.MatchString(u) && !facebookIDPattern.MatchString(u) {
+				return fmt.Errorf("Facebook username must be 5-50 letters, numbers or periods, or a numeric profile ID")
+			}
+			return nil
+		},
+		profileURL: func(u string) string {
+			if facebookIDPattern.MatchString(u) {
+				return "https://www.facebook.com/profile.php?id=" + u
+			}
+			return "https://www.facebook.com/" + u
+		},
+	},
+	{
+		// Username LinkedIn disimpan sebagai "company/<slug>" atau "in/<slug>"; handle polos dianggap halaman perusahaan
+		Key:   "linkedin",
+		Name:  "LinkedIn",
+		Hosts: []string{"linkedin.com"},
+		parse: func(path string, _ url.Values) string {
+			parts := strings.Split(strings.Trim(path, "/"), "/")
+			if len(parts) < 2 {
+				return ""
+			}
+			switch parts[0] {
+			case "company", "in", "school", "showcase":
+				return parts[0] + "/" + parts[1]
+			}
+			return ""
+		},
+		validate: func(u string) error {
+			parts := strings.SplitN(u, "/", 2)
+			if len(parts) != 2 || !linkedinSlugPattern.MatchString(parts[1]) {
+				return fmt.Errorf("LinkedIn handle must be a company or profile slug of 2-100 letters, numbers, hyphens or underscores")
+			}
+			return nil
+		},
+		profileURL: func(u string) string { return "https://www.linkedin.com/" + u + "/" },
+	},
+	{
+		// Username YouTube berupa "@handle" atau "channel/<channel id>"
+		Key:     "youtube",
+		Name:    "YouTube",
+		Aliases: []string{"yt"},
+		Hosts:   []string{"youtube.com", "youtu.be"},
+		parse: func(path string, _ url.Values) string {
+			parts := strings.Split(strings.Trim(path, "/"), "/")
+			if strings.HasPrefix(parts[0], "@") {
+				return parts[0]
+			}
+			if parts[0] == "channel" && len(parts) > 1 {
+				return "channel/" + parts[1]
+			}
+			return ""
+		},
+		validate: func(u string) error {
+			if strings.HasPrefix(u, "channel/") {
+				if !youtubeChannelPattern.MatchString(strings.TrimPrefix(u, "channel/")) {
+					return fmt.Errorf("YouTube channel ID must start with UC followed by 22 characters")
+				}
+				return nil
+			}
+			if !strings.HasPrefix(u, "@") || !youtubeHandlePattern.MatchString(strings.TrimPrefix(u, "@")) {
+				return fmt.Errorf("YouTube handle must be @ followed by 3-30 letters, numbers, periods, hyphens or underscores")
+			}
+			return nil
+		},
+		profileURL: func(u string) string { return "https://www.youtube.com/" + u },
+	},
+}
+
+// socialAccount adalah hasil normalisasi input akun sosmed
+type socialAccount struct {
+	Platform string // nama platform kanonik
+	Handle   string // bentuk tampilan, mis. @digiinno_id
+	Username string // bentuk kanonik untuk pencocokan duplikat
+	URL      string // URL profil kanonik
+}
+
+func supportedSocialPlatforms() string {
+	names := make([]string, 0, len(socialPlatforms))
+	for _, p := range socialPlatforms {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// findSocialPlatform mencari platform berdasarkan nama atau alias (tidak case-sensitive)
+func findSocialPlatform(name string) *socialPlatform {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i := range socialPlatforms {
+		p := &socialPlatforms[i]
+		if p.Key == name || strings.ToLower(p.Name) == name {
+			return p
+		}
+		for _, alias := range p.Aliases {
+			if alias == name {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// socialPlatformForHost mencari platform dari domain URL
+func socialPlatformForHost(host string) *socialPlatform {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	for i := range socialPlatforms {
+		for _, h := range socialPlatforms[i].Hosts {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				return &socialPlatforms[i]
+			}
+		}
+	}
+	return nil
+}
+
+// parseSocialURL mengurai input yang berbentuk URL; ok=false jika input bukan URL
+func parseSocialURL(input string) (*url.URL, bool) {
+	lower := strings.ToLower(input)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		// dukung input tanpa skema, mis. instagram.com/digiinno_id
+		if socialPlatformForHost(strings.SplitN(lower, "/", 2)[0]) == nil {
+			return nil, false
+		}
+		input = "https://" + input
+	}
+	u, err := url.Parse(input)
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// normalizeSocialAccount memvalidasi platform dan handle, menerima @handle, handle polos maupun URL profil,
+// lalu menghasilkan handle, username dan URL kanonik
+func normalizeSocialAccount(platformName, handle, profileURL string) (socialAccount, error) {
+	input := strings.TrimSpace(handle)
+	if input == "" {
+		input = strings.TrimSpace(profileURL)
+	}
+	if input == "" {
+		return socialAccount{}, fmt.Errorf("handle or url is required")
+	}
+
+	platform := findSocialPlatform(platformName)
+	if strings.TrimSpace(platformName) != "" && platform == nil {
+		return socialAccount{}, fmt.Errorf("unsupported platform '%s' (supported: %s)", platformName, supportedSocialPlatforms())
+	}
+
+	var username string
+	if u, ok := parseSocialURL(input); ok {
+		hostPlatform := socialPlatformForHost(u.Host)
+		if hostPlatform == nil {
+			return socialAccount{}, fmt.Errorf("unsupported profile url host '%s' (supported: %s)", u.Host, supportedSocialPlatforms())
+		}
+		if platform != nil && platform.Key != hostPlatform.Key {
+			return socialAccount{}, fmt.Errorf("profile url belongs to %s, not %s", hostPlatform.Name, platform.Name)
+		}
+		platform = hostPlatform
+		username = platform.parse(u.Path, u.Query())
+		if username == "" {
+			return socialAccount{}, fmt.Errorf("could not find a %s account in url '%s'", platform.Name, input)
+		}
+	} else {
+		if platform == nil {
+			return socialAccount{}, fmt.Errorf("platform is required (supported: %s)", supportedSocialPlatforms())
+		}
+		username = input
+		switch platform.Key {
+		case "linkedin":
+			username = strings.Trim(username, "/")
+			if !strings.Contains(username, "/") {
+				username = "company/" + username
+			}
+		case "youtube":
+			if !strings.HasPrefix(username, "channel/") && !strings.HasPrefix(username, "@") {
+				username = "@" + username
+			}
+		default:
+			username = strings.TrimPrefix(username, "@")
+		}
+	}
+
+	// ID channel YouTube case-sensitive, selain itu username tidak membedakan huruf besar/kecil
+	if !(platform.Key == "youtube" && strings.HasPrefix(username, "channel/")) {
+		username = strings.ToLower(username)
+	}
+	if err := platform.validate(username); err != nil {
+		return socialAccount{}, err
+	}
+
+	account := socialAccount{
+		Platform: platform.Name,
+		Handle:   username,
+		Username: username,
+		URL:      platform.profileURL(username),
+	}
+	if platform.AtStyle {
+		account.Handle = "@" + username
+	}
+	return account, nil
+}
+
+// findSosmedAccount mencari akun dengan platform + username yang sama milik customer, selain excludeID.
+// Data lama yang belum dinormalisasi ikut dinormalisasi saat dibandingkan.
+func findSosmedAccount(db *gorm.DB, customerID string, account socialAccount, excludeID string) (*entity.Sosmed, error) {
+	var sosmeds []entity.Sosmed
+	query := db.Where("customer_id = ?", customerID)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Find(&sosmeds).Error; err != nil {
+		return nil, err
+	}
+
+	for i, s := range sosmeds {
+		existing, err := normalizeSocialAccount(s.Platform, s.Handle, s.URL)
+		if err != nil {
+			continue
+		}
+		if existing.Platform == account.Platform && existing.Username == account.Username {
+			return &sosmeds[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -2,48 +2,88 @@ package handler
 
 import (
 	"customer-api/internal/config"
+	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // @Summary Create sosmed for customer
-// @Description Create a new social media account for specific customer
+// @Description Create a new social media account for specific customer. The handle may be given as @handle, plain handle or profile URL; it is validated per platform and the canonical profile URL is derived.
 // @Tags Social Media
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Customer ID"
-// @Param sosmed body entity.Sosmed true "Sosmed data"
+// @Param id path string true "Customer ID"
+// @Param sosmed body dto.CreateSocialRequest true "Sosmed data"
 // @Success 201 {object} entity.Sosmed
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Router /api/customers/{id}/sosmeds [post]
-// Hapus seluruh fungsi CreateSosmed (baris 11-50)
-// Fungsi ini sudah tidak diperlukan karena endpoint POST-nya dihapus
 func CreateSosmed(c *gin.Context) {
 	customerID := c.Param("id")
 
 	// Check if customer exists
 	var customer entity.Customer
-	if err := config.DB.First(&customer, customerID).Error; err != nil {
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
 		return
 	}
 
-	var sosmed entity.Sosmed
-	if err := c.ShouldBindJSON(&sosmed); err != nil {
+	var req dto.CreateSocialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Set customer ID
-	sosmed.CustomerID = customer.ID
+	account, err := normalizeSocialAccount(req.Platform, req.Handle, req.URL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	result := config.DB.Create(&sosmed)
-	if result.Error != nil {
+	duplicate, err := findSosmedAccount(config.DB, customer.ID, account, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing sosmeds"})
+		return
+	}
+	if duplicate != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       account.Platform + " account " + account.Handle + " already exists for this customer",
+			"existing_id": duplicate.ID,
+		})
+		return
+	}
+
+	name := req.Name
+	if name == "" {
+		name = account.Platform + " " + account.Handle
+	}
+	sosmed := entity.Sosmed{
+		CustomerID: customer.ID,
+		Name:       name,
+		Platform:   account.Platform,
+		Handle:     account.Handle,
+		Username:   account.Username,
+		URL:        account.URL,
+		Followers:  req.Followers,
+		Active:     req.Active,
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sosmed).Error; err != nil {
+			return err
+		}
+		// snapshot awal sebagai baseline pertumbuhan followers
+		_, err := recordSosmedSnapshot(tx, sosmed.ID, sosmed.Followers, sosmed.CreatedAt, "create", "")
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sosmed"})
 		return
 	}
@@ -90,7 +130,7 @@ func GetSosmed(c *gin.Context) {
 	id := c.Param("id")
 
 	var sosmed entity.Sosmed
-	result := config.DB.First(&sosmed, id)
+	result := config.DB.Where("id = ?", id).First(&sosmed)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Sosmed not found"})
 		return
@@ -116,18 +156,63 @@ func UpdateSosmed(c *gin.Context) {
 	id := c.Param("id")
 
 	var sosmed entity.Sosmed
-	result := config.DB.First(&sosmed, id)
+	result := config.DB.Where("id = ?", id).First(&sosmed)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Sosmed not found"})
 		return
 	}
 
+	previousFollowers, customerID := sosmed.Followers, sosmed.CustomerID
+	previousHandle, previousURL := sosmed.Handle, sosmed.URL
 	if err := c.ShouldBindJSON(&sosmed); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	sosmed.ID, sosmed.CustomerID = id, customerID
 
-	config.DB.Save(&sosmed)
+	// Handle/URL baru dinormalisasi ulang; handle didahulukan, jadi jika hanya URL yang
+	// berubah handle lama diabaikan agar akun diturunkan dari URL baru
+	handle := sosmed.Handle
+	if sosmed.URL != previousURL && sosmed.Handle == previousHandle {
+		handle = ""
+	}
+	account, err := normalizeSocialAccount(sosmed.Platform, handle, sosmed.URL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	duplicate, err := findSosmedAccount(config.DB, sosmed.CustomerID, account, sosmed.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing sosmeds"})
+		return
+	}
+	if duplicate != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       account.Platform + " account " + account.Handle + " already exists for this customer",
+			"existing_id": duplicate.ID,
+		})
+		return
+	}
+	sosmed.Platform = account.Platform
+	sosmed.Handle = account.Handle
+	sosmed.Username = account.Username
+	sosmed.URL = account.URL
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&sosmed).Error; err != nil {
+			return err
+		}
+		// Perubahan followers disimpan sebagai snapshot agar riwayat tidak hilang
+		if sosmed.Followers != previousFollowers {
+			_, err := recordSosmedSnapshot(tx, sosmed.ID, sosmed.Followers, time.Now(), "update", "")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update sosmed"})
+		return
+	}
 	c.JSON(http.StatusOK, sosmed)
 }
 
@@ -145,7 +230,7 @@ func UpdateSosmed(c *gin.Context) {
 func DeleteSosmed(c *gin.Context) {
 	id := c.Param("id")
 
-	result := config.DB.Delete(&entity.Sosmed{}, id)
+	result := config.DB.Where("id = ?", id).Delete(&entity.Sosmed{})
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sosmed"})
 		return
@@ -239,3 +324,29 @@ func GetCustomerWithAddressesAndSosmeds(c *gin.Context) {
 
 	c.JSON(http.StatusOK, customer)
 }
+
+// @Summary Get supported social platforms
+// @Description Get the social media platforms accepted for customer sosmed accounts
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/social-platforms [get]
+func GetSocialPlatforms(c *gin.Context) {
+	platforms := make([]gin.H, 0, len(socialPlatforms))
+	for _, p := range socialPlatforms {
+		platforms = append(platforms, gin.H{
+			"key":     p.Key,
+			"name":    p.Name,
+			"aliases": p.Aliases,
+			"hosts":   p.Hosts,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Social platforms fetched successfully",
+		"data":    platforms,
+	})
+}
@@ -0,0 +1,796 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultGrowthDays       = 30
+	defaultTopGrowersLimit  = 10
+	sosmedSnapshotMaxImport = 5 << 20 // 5 MB
+)
+
+// snapshotPoint adalah satu titik followers dari tabel sosmed_snapshots
+type snapshotPoint struct {
+	SosmedID   string
+	Followers  int
+	RecordedAt time.Time
+}
+
+// sosmedAccount adalah akun sosmed beserta nama customer-nya
+type sosmedAccount struct {
+	ID           string
+	CustomerID   string
+	CustomerName string
+	Platform     string
+	Handle       string
+}
+
+// recordSosmedSnapshot menyimpan snapshot followers (upsert per waktu) dan memperbarui
+// Sosmed.Followers jika snapshot ini adalah yang terbaru
+func recordSosmedSnapshot(tx *gorm.DB, sosmedID string, followers int, recordedAt time.Time, source, note string) (bool, error) {
+	created := false
+
+	var snapshot entity.SosmedSnapshot
+	err := tx.Where("sosmed_id = ? AND recorded_at = ?", sosmedID, recordedAt).First(&snapshot).Error
+	switch {
+	case err == nil:
+		snapshot.Followers = followers
+		snapshot.Source = source
+		if note != "" {
+			snapshot.Note = note
+		}
+		if err := tx.Save(&snapshot).Error; err != nil {
+			return false, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		snapshot = entity.SosmedSnapshot{
+			SosmedID:   sosmedID,
+			Followers:  followers,
+			RecordedAt: recordedAt,
+			Source:     source,
+			Note:       note,
+		}
+		if err := tx.Create(&snapshot).Error; err != nil {
+			return false, err
+		}
+		created = true
+	default:
+		return false, err
+	}
+
+	return created, syncSosmedFollowers(tx, sosmedID)
+}
+
+// syncSosmedFollowers menyamakan Sosmed.Followers dengan snapshot terbaru
+func syncSosmedFollowers(tx *gorm.DB, sosmedID string) error {
+	var latest entity.SosmedSnapshot
+	err := tx.Where("sosmed_id = ?", sosmedID).Order("recorded_at DESC").First(&latest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Model(&entity.Sosmed{}).Where("id = ?", sosmedID).Update("followers", latest.Followers).Error
+}
+
+// snapshotsAt mengambil snapshot terakhir per akun pada atau sebelum waktu before
+func snapshotsAt(db *gorm.DB, sosmedIDs []string, before time.Time) (map[string]snapshotPoint, error) {
+	var points []snapshotPoint
+	err := db.Table("sosmed_snapshots").
+		Select("DISTINCT ON (sosmed_id) sosmed_id, followers, recorded_at").
+		Where("sosmed_id IN ? AND recorded_at <= ?", sosmedIDs, before).
+		Order("sosmed_id, recorded_at DESC").
+		Scan(&points).Error
+	return indexSnapshotPoints(points), err
+}
+
+// firstSnapshotsBetween mengambil snapshot pertama per akun di dalam rentang waktu
+func firstSnapshotsBetween(db *gorm.DB, sosmedIDs []string, from, to time.Time) (map[string]snapshotPoint, error) {
+	var points []snapshotPoint
+	err := db.Table("sosmed_snapshots").
+		Select("DISTINCT ON (sosmed_id) sosmed_id, followers, recorded_at").
+		Where("sosmed_id IN ? AND recorded_at >= ? AND recorded_at <= ?", sosmedIDs, from, to).
+		Order("sosmed_id, recorded_at ASC").
+		Scan(&points).Error
+	return indexSnapshotPoints(points), err
+}
+
+func indexSnapshotPoints(points []snapshotPoint) map[string]snapshotPoint {
+	byID := make(map[string]snapshotPoint, len(points))
+	for _, p := range points {
+		byID[p.SosmedID] = p
+	}
+	return byID
+}
+
+func percentChange(start, end int) *float64 {
+	if start == 0 {
+		return nil
+	}
+	pct := math.Round(float64(end-start)/float64(start)*10000) / 100
+	return &pct
+}
+
+// loadSosmedAccounts mengambil akun sosmed aktif beserta nama customer, terbatas pada customer yang terlihat oleh user
+func loadSosmedAccounts(policy *access.Context, customerID, sosmedID, platform string) ([]sosmedAccount, error) {
+	query := config.DB.Table("sosmeds").
+		Select("sosmeds.id, sosmeds.customer_id, customers.name AS customer_name, sosmeds.platform, sosmeds.handle").
+		Joins("JOIN customers ON customers.id = sosmeds.customer_id AND customers.deleted_at IS NULL").
+		Where("sosmeds.deleted_at IS NULL")
+	query = policy.Filter(query, "sosmeds.customer_id")
+	if customerID != "" {
+		query = query.Where("sosmeds.customer_id = ?", customerID)
+	}
+	if sosmedID != "" {
+		query = query.Where("sosmeds.id = ?", sosmedID)
+	}
+	if platform != "" {
+		query = query.Where("LOWER(sosmeds.platform) = ?", strings.ToLower(platform))
+	}
+
+	var accounts []sosmedAccount
+	err := query.Scan(&accounts).Error
+	return accounts, err
+}
+
+// computeSosmedGrowth menghitung perubahan followers tiap akun antara from dan to.
+// Titik awal adalah snapshot terakhir sebelum from, atau snapshot pertama di dalam window jika akun baru dilacak.
+func computeSosmedGrowth(accounts []sosmedAccount, from, to time.Time) ([]dto.SosmedGrowth, error) {
+	growth := []dto.SosmedGrowth{}
+	if len(accounts) == 0 {
+		return growth, nil
+	}
+
+	ids := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		ids = append(ids, a.ID)
+	}
+
+	ends, err := snapshotsAt(config.DB, ids, to)
+	if err != nil {
+		return nil, err
+	}
+	starts, err := snapshotsAt(config.DB, ids, from)
+	if err != nil {
+		return nil, err
+	}
+	firsts, err := firstSnapshotsBetween(config.DB, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range accounts {
+		end, ok := ends[a.ID]
+		if !ok {
+			continue
+		}
+		start, ok := starts[a.ID]
+		if !ok {
+			start = firsts[a.ID]
+		}
+		startAt, endAt := start.RecordedAt, end.RecordedAt
+		growth = append(growth, dto.SosmedGrowth{
+			SosmedID:       a.ID,
+			CustomerID:     a.CustomerID,
+			CustomerName:   a.CustomerName,
+			Platform:       a.Platform,
+			Handle:         a.Handle,
+			StartFollowers: start.Followers,
+			EndFollowers:   end.Followers,
+			Change:         end.Followers - start.Followers,
+			PercentChange:  percentChange(start.Followers, end.Followers),
+			StartAt:        &startAt,
+			EndAt:          &endAt,
+		})
+	}
+	return growth, nil
+}
+
+// parseGrowthWindow membaca from/to atau days (default 30 hari terakhir)
+func parseGrowthWindow(c *gin.Context) (time.Time, time.Time, error) {
+	to := time.Now()
+	if v := c.Query("to"); v != "" {
+		t, err := parseDocumentDate(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		// tanggal tanpa jam dianggap sampai akhir hari
+		to = t.Add(24*time.Hour - time.Nanosecond)
+		if len(v) > len("2006-01-02") {
+			to = *t
+		}
+	}
+
+	if v := c.Query("from"); v != "" {
+		from, err := parseDocumentDate(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if from.After(to) {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+		}
+		return *from, to, nil
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultGrowthDays)))
+	if err != nil || days <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid days parameter")
+	}
+	return to.AddDate(0, 0, -days), to, nil
+}
+
+// @Summary Get sosmed follower snapshots
+// @Description Get the follower history of a social media account
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sosmed ID"
+// @Param from query string false "Start date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "End date (YYYY-MM-DD or RFC3339)"
+// @Success 200 {array} entity.SosmedSnapshot
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sosmeds/{id}/snapshots [get]
+func GetSosmedSnapshots(c *gin.Context) {
+	id := c.Param("id")
+
+	var sosmed entity.Sosmed
+	if err := config.DB.Where("id = ?", id).First(&sosmed).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sosmed not found"})
+		return
+	}
+
+	query := config.DB.Where("sosmed_id = ?", sosmed.ID)
+	if v := c.Query("from"); v != "" {
+		from, err := parseDocumentDate(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query = query.Where("recorded_at >= ?", *from)
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := parseDocumentDate(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query = query.Where("recorded_at <= ?", *to)
+	}
+
+	var snapshots []entity.SosmedSnapshot
+	if err := query.Order("recorded_at ASC").Find(&snapshots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// @Summary Add sosmed follower snapshot
+// @Description Record the follower count of a social media account at a point in time. A snapshot at the same time is overwritten.
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sosmed ID"
+// @Param snapshot body dto.SosmedSnapshotRequest true "Snapshot data"
+// @Success 201 {object} entity.SosmedSnapshot
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sosmeds/{id}/snapshots [post]
+func CreateSosmedSnapshot(c *gin.Context) {
+	id := c.Param("id")
+
+	var sosmed entity.Sosmed
+	if err := config.DB.Where("id = ?", id).First(&sosmed).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sosmed not found"})
+		return
+	}
+
+	var req dto.SosmedSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recordedAt := time.Now()
+	if req.RecordedAt != "" {
+		t, err := parseDocumentDate(req.RecordedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		recordedAt = *t
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := recordSosmedSnapshot(tx, sosmed.ID, *req.Followers, recordedAt, "manual", req.Note)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save snapshot"})
+		return
+	}
+
+	var snapshot entity.SosmedSnapshot
+	config.DB.Where("sosmed_id = ? AND recorded_at = ?", sosmed.ID, recordedAt).First(&snapshot)
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// @Summary Delete sosmed follower snapshot
+// @Description Delete a wrongly recorded follower snapshot
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Snapshot ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sosmed-snapshots/{id} [delete]
+func DeleteSosmedSnapshot(c *gin.Context) {
+	id := c.Param("id")
+
+	var snapshot entity.SosmedSnapshot
+	if err := config.DB.Where("id = ?", id).First(&snapshot).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Snapshot not found"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&snapshot).Error; err != nil {
+			return err
+		}
+		return syncSosmedFollowers(tx, snapshot.SosmedID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete snapshot"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Snapshot deleted successfully"})
+}
+
+// @Summary Import sosmed follower snapshots
+// @Description Bulk import follower snapshots from CSV (field "file"). Header columns: followers, recorded_at, note (optional) and either sosmed_id or customer_code + platform + handle.
+// @Tags Social Media
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Success 200 {object} dto.SosmedSnapshotImportResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sosmed-snapshots/import [post]
+func ImportSosmedSnapshots(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	if file.Size > sosmedSnapshotMaxImport {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File must not be larger than %d MB", sosmedSnapshotMaxImport>>20)})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer src.Close()
+
+	reader := csv.NewReader(src)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header is required"})
+		return
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	_, hasSosmedID := columns["sosmed_id"]
+	_, hasCode := columns["customer_code"]
+	_, hasPlatform := columns["platform"]
+	_, hasHandle := columns["handle"]
+	if _, ok := columns["followers"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV must have a followers column"})
+		return
+	}
+	if !hasSosmedID && !(hasCode && hasPlatform && hasHandle) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV must have sosmed_id or customer_code, platform and handle columns"})
+		return
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	result := dto.SosmedSnapshotImportResult{Errors: []string{}}
+	resolved := make(map[string]string) // key lookup -> sosmed ID ("" jika tidak ditemukan)
+	writable := make(map[string]bool)   // customer ID -> boleh diubah user
+	now := time.Now()
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		line := 1
+		for {
+			record, err := reader.Read()
+			line++
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				// baris rusak dilewati; error lain (gagal baca file) tidak akan pulih jadi import dihentikan
+				var parseErr *csv.ParseError
+				if !errors.As(err, &parseErr) {
+					return err
+				}
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+
+			sosmedID, err := resolveSnapshotSosmed(tx, resolved, field(record, "sosmed_id"),
+				field(record, "customer_code"), field(record, "platform"), field(record, "handle"))
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+			// akun milik customer di luar scope user diperlakukan seperti tidak ada
+			var customerIDs []string
+			tx.Model(&entity.Sosmed{}).Where("id = ?", sosmedID).Pluck("customer_id", &customerIDs)
+			if len(customerIDs) == 0 || !canWriteCustomer(tx, policy, writable, customerIDs[0]) {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: sosmed account not found", line))
+				continue
+			}
+
+			followers, err := strconv.Atoi(strings.ReplaceAll(field(record, "followers"), ",", ""))
+			if err != nil || followers < 0 {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: invalid followers value", line))
+				continue
+			}
+
+			recordedAt := now
+			if v := field(record, "recorded_at"); v != "" {
+				t, err := parseDocumentDate(v)
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+					continue
+				}
+				recordedAt = *t
+			}
+
+			created, err := recordSosmedSnapshot(tx, sosmedID, followers, recordedAt, "csv", field(record, "note"))
+			if err != nil {
+				return err
+			}
+			if created {
+				result.Imported++
+			} else {
+				result.Updated++
+			}
+		}
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Snapshots imported",
+		"data":    result,
+	})
+}
+
+// resolveSnapshotSosmed mencari akun sosmed dari sosmed_id atau kombinasi customer_code, platform dan handle
+func resolveSnapshotSosmed(tx *gorm.DB, cache map[string]string, sosmedID, customerCode, platform, handle string) (string, error) {
+	key := sosmedID
+	if key == "" {
+		key = strings.ToLower(customerCode + "|" + platform + "|" + strings.TrimPrefix(handle, "@"))
+	}
+	if id, ok := cache[key]; ok {
+		if id == "" {
+			return "", fmt.Errorf("sosmed account not found")
+		}
+		return id, nil
+	}
+
+	var sosmed entity.Sosmed
+	var err error
+	if sosmedID != "" {
+		err = tx.Where("id = ?", sosmedID).First(&sosmed).Error
+	} else {
+		err = findSnapshotSosmedByHandle(tx, customerCode, platform, handle, &sosmed)
+	}
+	if err != nil {
+		cache[key] = ""
+		return "", err
+	}
+	cache[key] = sosmed.ID
+	return sosmed.ID, nil
+}
+
+func findSnapshotSosmedByHandle(tx *gorm.DB, customerCode, platform, handle string, sosmed *entity.Sosmed) error {
+	account, err := normalizeSocialAccount(platform, handle, "")
+	if err != nil {
+		return err
+	}
+	var customer entity.Customer
+	if err := tx.Where("code = ?", customerCode).First(&customer).Error; err != nil {
+		return fmt.Errorf("customer code '%s' not found", customerCode)
+	}
+	found, err := findSosmedAccount(tx, customer.ID, account, "")
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		return fmt.Errorf("sosmed account not found")
+	}
+	*sosmed = *found
+	return nil
+}
+
+// @Summary Get sosmed growth
+// @Description Get the absolute and percent follower change of a social media account over a window
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sosmed ID"
+// @Param days query int false "Window in days ending at 'to' (default 30)"
+// @Param from query string false "Window start (overrides days)"
+// @Param to query string false "Window end (default now)"
+// @Success 200 {object} dto.SosmedGrowth
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sosmeds/{id}/growth [get]
+func GetSosmedGrowth(c *gin.Context) {
+	from, to, err := parseGrowthWindow(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := loadSosmedAccounts(policy, "", c.Param("id"), "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sosmed"})
+		return
+	}
+	if len(accounts) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sosmed not found"})
+		return
+	}
+
+	growth, err := computeSosmedGrowth(accounts, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute growth"})
+		return
+	}
+	if len(growth) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No snapshots recorded for this account in the window"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sosmed growth fetched successfully",
+		"data":    growth[0],
+	})
+}
+
+// @Summary Get customer sosmed growth
+// @Description Get the follower change of every social media account of a customer over a window, plus the customer total
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param days query int false "Window in days ending at 'to' (default 30)"
+// @Param from query string false "Window start (overrides days)"
+// @Param to query string false "Window end (default now)"
+// @Success 200 {array} dto.SosmedGrowth
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/sosmeds/growth [get]
+func GetCustomerSosmedGrowth(c *gin.Context) {
+	from, to, err := parseGrowthWindow(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := loadSosmedAccounts(policy, c.Param("id"), "", "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sosmeds"})
+		return
+	}
+	growth, err := computeSosmedGrowth(accounts, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute growth"})
+		return
+	}
+
+	totals := aggregateCustomerGrowth(growth)
+	var total *dto.CustomerSosmedGrowth
+	if len(totals) > 0 {
+		total = &totals[0]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Customer sosmed growth fetched successfully",
+		"data":     growth,
+		"total":    total,
+		"from":     from,
+		"to":       to,
+		"accounts": len(accounts),
+	})
+}
+
+// aggregateCustomerGrowth menjumlahkan followers semua akun per customer
+func aggregateCustomerGrowth(growth []dto.SosmedGrowth) []dto.CustomerSosmedGrowth {
+	totals := []dto.CustomerSosmedGrowth{}
+	index := make(map[string]int)
+	for _, g := range growth {
+		i, ok := index[g.CustomerID]
+		if !ok {
+			i = len(totals)
+			index[g.CustomerID] = i
+			totals = append(totals, dto.CustomerSosmedGrowth{CustomerID: g.CustomerID, CustomerName: g.CustomerName})
+		}
+		totals[i].Accounts++
+		totals[i].StartFollowers += g.StartFollowers
+		totals[i].EndFollowers += g.EndFollowers
+	}
+	for i := range totals {
+		totals[i].Change = totals[i].EndFollowers - totals[i].StartFollowers
+		totals[i].PercentChange = percentChange(totals[i].StartFollowers, totals[i].EndFollowers)
+	}
+	return totals
+}
+
+// growthLess mengurutkan berdasarkan persen (default) atau perubahan absolut, terbesar dulu
+func growthLess(sortBy string, changeA, changeB int, pctA, pctB *float64) bool {
+	if sortBy == "absolute" {
+		return changeA > changeB
+	}
+	if pctA == nil || pctB == nil {
+		if pctA == nil && pctB == nil {
+			return changeA > changeB
+		}
+		return pctB == nil
+	}
+	if *pctA != *pctB {
+		return *pctA > *pctB
+	}
+	return changeA > changeB
+}
+
+// @Summary Get top sosmed growers
+// @Description Rank customers (or single accounts) by follower growth over a window
+// @Tags Social Media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Window in days ending at 'to' (default 30)"
+// @Param from query string false "Window start (overrides days)"
+// @Param to query string false "Window end (default now)"
+// @Param platform query string false "Only count accounts on this platform"
+// @Param level query string false "customer (default) or account"
+// @Param sort query string false "percent (default) or absolute"
+// @Param limit query int false "Number of results (default 10)"
+// @Success 200 {array} dto.CustomerSosmedGrowth
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sosmed-growth/top [get]
+func GetTopSosmedGrowers(c *gin.Context) {
+	from, to, err := parseGrowthWindow(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTopGrowersLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	level := c.DefaultQuery("level", "customer")
+	if level != "customer" && level != "account" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid level (must be 'customer' or 'account')"})
+		return
+	}
+	sortBy := c.DefaultQuery("sort", "percent")
+	if sortBy != "percent" && sortBy != "absolute" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort (must be 'percent' or 'absolute')"})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := loadSosmedAccounts(policy, "", "", c.Query("platform"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sosmeds"})
+		return
+	}
+	growth, err := computeSosmedGrowth(accounts, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute growth"})
+		return
+	}
+
+	var data interface{}
+	if level == "account" {
+		sort.SliceStable(growth, func(i, j int) bool {
+			return growthLess(sortBy, growth[i].Change, growth[j].Change, growth[i].PercentChange, growth[j].PercentChange)
+		})
+		if len(growth) > limit {
+			growth = growth[:limit]
+		}
+		data = growth
+	} else {
+		totals := aggregateCustomerGrowth(growth)
+		sort.SliceStable(totals, func(i, j int) bool {
+			return growthLess(sortBy, totals[i].Change, totals[j].Change, totals[i].PercentChange, totals[j].PercentChange)
+		})
+		if len(totals) > limit {
+			totals = totals[:limit]
+		}
+		data = totals
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Top growers fetched successfully",
+		"data":    data,
+		"from":    from,
+		"to":      to,
+	})
+}
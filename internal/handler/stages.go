@@ -11,7 +11,9 @@ import (
 
 
 type StageInput struct {
-	Name string `json:"name" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Sequence    *int     `json:"sequence"`
+	Probability *float64 `json:"probability" binding:"omitempty,min=0,max=100"` // default peluang menang (%)
 }
 
 type StagesDetail struct {
@@ -47,6 +49,12 @@ func CreateStage(c *gin.Context) {
 	stage := entity.Stages{
 		Name: input.Name,
 	}
+	if input.Sequence != nil {
+		stage.Sequence = *input.Sequence
+	}
+	if input.Probability != nil {
+		stage.Probability = *input.Probability
+	}
 
 	if result := config.DB.Create(&stage); result.Error != nil {
 	c.JSON(http.StatusInternalServerError, gin.H{
@@ -66,7 +74,7 @@ func CreateStage(c *gin.Context) {
 
 func GetStages(c *gin.Context) {
 	var stages []entity.Stages
-	if result := config.DB.Find(&stages); result.Error != nil {
+	if result := config.DB.Order("sequence, name").Find(&stages); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data stages"})
 		return
 	}
@@ -129,6 +137,12 @@ func UpdateStage(c *gin.Context) {
 	}
 
 	stage.Name = input.Name
+	if input.Sequence != nil {
+		stage.Sequence = *input.Sequence
+	}
+	if input.Probability != nil {
+		stage.Probability = *input.Probability
+	}
 
 	if result := config.DB.Save(&stage); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui stage"})
@@ -154,6 +168,14 @@ func DeleteStage(c *gin.Context) {
 		return
 	}
 
+	// Stage yang masih dipakai opportunity tidak boleh dihapus
+	var used int64
+	config.DB.Model(&entity.Opportunity{}).Where("stage_id = ?", stage.ID).Count(&used)
+	if used > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stage is still used by opportunities"})
+		return
+	}
+
 	if result := config.DB.Delete(&stage); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus stage"})
 		return
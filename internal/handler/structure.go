@@ -5,6 +5,7 @@ import (
 	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -43,15 +44,46 @@ func CreateStructure(c *gin.Context) {
 		return
 	}
 
-	// Hapus validasi customer exists karena CustomerID tidak ada di request
+	// Customer ID diambil dari path (/customers/:id/structures) atau dari body (/structures)
+	customerID := c.Param("id")
+	if customerID == "" {
+		customerID = req.CustomerID
+	}
+	if customerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "customer_id is required"})
+		return
+	}
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
 	structure := entity.Structure{
-		// CustomerID: customer.ID, // Akan diset sesuai kebutuhan
-		Name:    req.Name,
-		Level:   req.Level,
-		Address: req.Address,
-		Active:  req.Active,
+		CustomerID: customer.ID,
+		Name:       req.Name,
+		Level:      1,
+		Address:    req.Address,
+		Active:     req.Active,
 	}
 
+	// Level dihitung dari parent, bukan dari request
+	if req.ParentID != nil && *req.ParentID != "" {
+		var parent entity.Structure
+		if err := config.DB.Where("id = ? AND customer_id = ?", *req.ParentID, customer.ID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent structure not found for this customer"})
+			return
+		}
+		structure.ParentID = &parent.ID
+		structure.Level = parent.Level + 1
+	}
+
+	// Node baru ditaruh di urutan terakhir di antara saudaranya
+	var siblingCount int64
+	structureSiblingsQuery(config.DB.Model(&entity.Structure{}), customer.ID, structure.ParentID).Count(&siblingCount)
+	structure.Position = int(siblingCount)
+
 	result := config.DB.Create(&structure)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create structure"})
@@ -76,7 +108,7 @@ func GetCustomerStructures(c *gin.Context) {
 	customerID := c.Param("id")
 
 	var structures []entity.Structure
-	result := config.DB.Where("customer_id = ?", customerID).Order("level ASC, name ASC").Find(&structures)
+	result := config.DB.Where("customer_id = ?", customerID).Order("level ASC, position ASC, name ASC").Find(&structures)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
 		return
@@ -85,6 +117,39 @@ func GetCustomerStructures(c *gin.Context) {
 	c.JSON(http.StatusOK, structures)
 }
 
+// @Summary Get customer structure tree
+// @Description Get the nested organizational chart for a specific customer
+// @Tags Structures
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {array} dto.StructureTreeNode
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/structures/tree [get]
+func GetCustomerStructureTree(c *gin.Context) {
+	customerID := c.Param("id")
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	structures, err := loadCustomerStructures(config.DB, customer.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Structure tree fetched successfully",
+		"data":    buildStructureTree(structures),
+	})
+}
+
 // @Summary Get structures by level
 // @Description Get organizational structures filtered by level for specific customer
 // @Tags Structures
@@ -137,7 +202,9 @@ func GetStructure(c *gin.Context) {
 	id := c.Param("id")
 
 	var structure entity.Structure
-	result := config.DB.Preload("Parent").Preload("Children").First(&structure, id)
+	result := config.DB.Preload("Parent").Preload("Children", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("id = ?", id).First(&structure)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Structure not found"})
 		return
@@ -163,47 +230,436 @@ func UpdateStructure(c *gin.Context) {
 	id := c.Param("id")
 
 	var structure entity.Structure
-	result := config.DB.First(&structure, id)
+	result := config.DB.Where("id = ?", id).First(&structure)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Structure not found"})
 		return
 	}
 
+	// Posisi di hierarki hanya boleh diubah lewat endpoint move
+	customerID, parentID, level, position := structure.CustomerID, structure.ParentID, structure.Level, structure.Position
+
 	if err := c.ShouldBindJSON(&structure); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	config.DB.Save(&structure)
+	structure.ID = id
+	structure.CustomerID = customerID
+	structure.ParentID = parentID
+	structure.Level = level
+	structure.Position = position
+	structure.Parent = nil
+	structure.Children = nil
+
+	config.DB.Omit("Parent", "Children", "Customer").Save(&structure)
 	c.JSON(http.StatusOK, structure)
 }
 
+// @Summary Move structure subtree
+// @Description Move a structure (with all of its children) under a new parent and/or to a new position among its siblings. Levels of the whole subtree are recalculated.
+// @Tags Structures
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Structure ID"
+// @Param move body dto.MoveStructureRequest true "New parent (null for root) and optional position"
+// @Success 200 {array} dto.StructureTreeNode
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/structures/{id}/move [post]
+func MoveStructure(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.MoveStructureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var structure entity.Structure
+	if err := config.DB.Where("id = ?", id).First(&structure).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Structure not found"})
+		return
+	}
+
+	structures, err := loadCustomerStructures(config.DB, structure.CustomerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
+		return
+	}
+	byID := make(map[string]entity.Structure, len(structures))
+	for _, s := range structures {
+		byID[s.ID] = s
+	}
+
+	var newParentID *string
+	newLevel := 1
+	if req.ParentID != nil && *req.ParentID != "" {
+		parent, ok := byID[*req.ParentID]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent structure not found for this customer"})
+			return
+		}
+		// Cegah cycle: parent baru tidak boleh node itu sendiri atau turunannya
+		for _, descendantID := range structureSubtreeIDs(structures, structure.ID) {
+			if descendantID == parent.ID {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot move a structure under itself or one of its descendants"})
+				return
+			}
+		}
+		newParentID = &parent.ID
+		newLevel = parent.Level + 1
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Hitung ulang level seluruh subtree
+		if delta := newLevel - structure.Level; delta != 0 {
+			if err := tx.Model(&entity.Structure{}).Where("id IN ?", structureSubtreeIDs(structures, structure.ID)).
+				Update("level", gorm.Expr("level + ?", delta)).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&entity.Structure{}).Where("id = ?", structure.ID).Update("parent_id", newParentID).Error; err != nil {
+			return err
+		}
+
+		// Rapikan urutan saudara lama, lalu sisipkan ke saudara baru
+		oldSiblings := structureSiblingIDs(structures, structure.ParentID, structure.ID)
+		newSiblings := structureSiblingIDs(structures, newParentID, structure.ID)
+		position := len(newSiblings)
+		if req.Position != nil && *req.Position >= 0 && *req.Position < position {
+			position = *req.Position
+		}
+		newSiblings = append(newSiblings[:position], append([]string{structure.ID}, newSiblings[position:]...)...)
+
+		if !sameStructureParent(structure.ParentID, newParentID) {
+			if err := applyStructurePositions(tx, byID, oldSiblings); err != nil {
+				return err
+			}
+		}
+		byID[structure.ID] = entity.Structure{ID: structure.ID, Position: -1}
+		return applyStructurePositions(tx, byID, newSiblings)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move structure"})
+		return
+	}
+
+	structures, _ = loadCustomerStructures(config.DB, structure.CustomerID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Structure moved successfully",
+		"data":    buildStructureTree(structures),
+	})
+}
+
+// @Summary Reorder sibling structures
+// @Description Set the order of all children of a parent (or of the root nodes) for a customer
+// @Tags Structures
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param order body dto.ReorderStructuresRequest true "Parent (null for root) and sibling IDs in the desired order"
+// @Success 200 {array} dto.StructureTreeNode
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/structures/reorder [put]
+func ReorderStructures(c *gin.Context) {
+	customerID := c.Param("id")
+
+	var req dto.ReorderStructuresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	structures, err := loadCustomerStructures(config.DB, customer.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
+		return
+	}
+	byID := make(map[string]entity.Structure, len(structures))
+	for _, s := range structures {
+		byID[s.ID] = s
+	}
+
+	parentID := req.ParentID
+	if parentID != nil && *parentID == "" {
+		parentID = nil
+	}
+
+	// Daftar yang dikirim harus sama persis dengan anak dari parent tersebut
+	siblings := structureSiblingIDs(structures, parentID, "")
+	if len(siblings) != len(req.StructureIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "structure_ids must contain every sibling exactly once"})
+		return
+	}
+	seen := make(map[string]bool, len(req.StructureIDs))
+	for _, sid := range req.StructureIDs {
+		s, ok := byID[sid]
+		if !ok || seen[sid] || !sameStructureParent(s.ParentID, parentID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "structure_ids must contain every sibling exactly once"})
+			return
+		}
+		seen[sid] = true
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return applyStructurePositions(tx, byID, req.StructureIDs)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder structures"})
+		return
+	}
+
+	structures, _ = loadCustomerStructures(config.DB, customer.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Structures reordered successfully",
+		"data":    buildStructureTree(structures),
+	})
+}
+
 // @Summary Delete structure
-// @Description Delete an organizational structure by ID
+// @Description Delete an organizational structure by ID. When the node has children, policy decides what happens: reject (default), cascade (delete the whole subtree) or reparent (children move to the deleted node's parent).
 // @Tags Structures
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Structure ID"
+// @Param id path string true "Structure ID"
+// @Param policy query string false "Delete policy" Enums(reject, cascade, reparent)
 // @Success 200 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Router /api/structures/{id} [delete]
 func DeleteStructure(c *gin.Context) {
 	id := c.Param("id")
+	policy := c.DefaultQuery("policy", "reject")
 
-	result := config.DB.Delete(&entity.Structure{}, id)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete structure"})
+	if policy != "reject" && policy != "cascade" && policy != "reparent" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy (must be 'reject', 'cascade' or 'reparent')"})
 		return
 	}
 
-	if result.RowsAffected == 0 {
+	var structure entity.Structure
+	if err := config.DB.Where("id = ?", id).First(&structure).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Structure not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Structure deleted successfully"})
+	structures, err := loadCustomerStructures(config.DB, structure.CustomerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch structures"})
+		return
+	}
+	byID := make(map[string]entity.Structure, len(structures))
+	for _, s := range structures {
+		byID[s.ID] = s
+	}
+
+	children := structureSiblingIDs(structures, &structure.ID, "")
+	if len(children) > 0 && policy == "reject" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "Structure has child structures",
+			"hint":     "Use policy=cascade to delete the whole subtree or policy=reparent to move children to the parent",
+			"children": len(children),
+		})
+		return
+	}
+
+	var deleted int64
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		deleteIDs := []string{structure.ID}
+
+		switch policy {
+		case "cascade":
+			deleteIDs = structureSubtreeIDs(structures, structure.ID)
+		case "reparent":
+			// Anak naik satu level dan ditaruh di posisi node yang dihapus
+			for _, childID := range children {
+				if err := tx.Model(&entity.Structure{}).Where("id IN ?", structureSubtreeIDs(structures, childID)).
+					Update("level", gorm.Expr("level - 1")).Error; err != nil {
+					return err
+				}
+			}
+			if len(children) > 0 {
+				if err := tx.Model(&entity.Structure{}).Where("id IN ?", children).Update("parent_id", structure.ParentID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		result := tx.Where("id IN ?", deleteIDs).Delete(&entity.Structure{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		// Contact di unit yang dihapus pindah ke unit induk (atau tanpa unit jika root)
+		if err := tx.Model(&entity.Contact{}).Where("structure_id IN ?", deleteIDs).
+			Update("structure_id", structure.ParentID).Error; err != nil {
+			return err
+		}
+
+		var siblings []string
+		for _, sid := range structureSiblingIDs(structures, structure.ParentID, "") {
+			if sid == structure.ID {
+				if policy == "reparent" {
+					siblings = append(siblings, children...)
+				}
+				continue
+			}
+			siblings = append(siblings, sid)
+		}
+		return applyStructurePositions(tx, byID, siblings)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete structure"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Structure deleted successfully",
+		"policy":  policy,
+		"deleted": deleted,
+	})
+}
+
+// loadCustomerStructures mengambil semua structure milik customer dalam urutan tampil
+func loadCustomerStructures(db *gorm.DB, customerID string) ([]entity.Structure, error) {
+	var structures []entity.Structure
+	err := db.Where("customer_id = ?", customerID).Order("level ASC, position ASC, name ASC").Find(&structures).Error
+	return structures, err
+}
+
+func structureSiblingsQuery(db *gorm.DB, customerID string, parentID *string) *gorm.DB {
+	db = db.Where("customer_id = ?", customerID)
+	if parentID == nil {
+		return db.Where("parent_id IS NULL")
+	}
+	return db.Where("parent_id = ?", *parentID)
+}
+
+func sameStructureParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// structureSiblingIDs mengembalikan ID anak dari parentID (urut position), tanpa excludeID
+func structureSiblingIDs(structures []entity.Structure, parentID *string, excludeID string) []string {
+	siblings := []entity.Structure{}
+	for _, s := range structures {
+		if s.ID != excludeID && sameStructureParent(s.ParentID, parentID) {
+			siblings = append(siblings, s)
+		}
+	}
+	sort.SliceStable(siblings, func(i, j int) bool {
+		return siblings[i].Position < siblings[j].Position
+	})
+
+	ids := make([]string, 0, len(siblings))
+	for _, s := range siblings {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+// structureSubtreeIDs mengembalikan rootID beserta semua turunannya
+func structureSubtreeIDs(structures []entity.Structure, rootID string) []string {
+	childrenOf := make(map[string][]string)
+	for _, s := range structures {
+		if s.ParentID != nil {
+			childrenOf[*s.ParentID] = append(childrenOf[*s.ParentID], s.ID)
+		}
+	}
+
+	ids := []string{rootID}
+	visited := map[string]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, childID := range childrenOf[ids[i]] {
+			if !visited[childID] {
+				visited[childID] = true
+				ids = append(ids, childID)
+			}
+		}
+	}
+	return ids
+}
+
+// applyStructurePositions menyimpan position 0..n sesuai urutan ids, hanya untuk yang berubah
+func applyStructurePositions(tx *gorm.DB, byID map[string]entity.Structure, ids []string) error {
+	for position, sid := range ids {
+		if s, ok := byID[sid]; ok && s.Position == position {
+			continue
+		}
+		if err := tx.Model(&entity.Structure{}).Where("id = ?", sid).Update("position", position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildStructureTree menyusun daftar flat menjadi pohon bersarang
+func buildStructureTree(structures []entity.Structure) []dto.StructureTreeNode {
+	byID := make(map[string]entity.Structure, len(structures))
+	for _, s := range structures {
+		byID[s.ID] = s
+	}
+
+	var build func(parentID *string) []dto.StructureTreeNode
+	build = func(parentID *string) []dto.StructureTreeNode {
+		nodes := []dto.StructureTreeNode{}
+		for _, sid := range structureSiblingIDs(structures, parentID, "") {
+			s := byID[sid]
+			nodes = append(nodes, dto.StructureTreeNode{
+				ID:         s.ID,
+				CustomerID: s.CustomerID,
+				ParentID:   s.ParentID,
+				Name:       s.Name,
+				Level:      s.Level,
+				Position:   s.Position,
+				Address:    s.Address,
+				Active:     s.Active,
+				Children:   build(&s.ID),
+			})
+		}
+		return nodes
+	}
+
+	roots := build(nil)
+
+	// Node yang parent-nya tidak ditemukan (data lama) ditampilkan sebagai root
+	for _, s := range structures {
+		if s.ParentID != nil {
+			if _, ok := byID[*s.ParentID]; !ok {
+				roots = append(roots, dto.StructureTreeNode{
+					ID:         s.ID,
+					CustomerID: s.CustomerID,
+					ParentID:   s.ParentID,
+					Name:       s.Name,
+					Level:      s.Level,
+					Position:   s.Position,
+					Address:    s.Address,
+					Active:     s.Active,
+					Children:   build(&s.ID),
+				})
+			}
+		}
+	}
+	return roots
 }
 
 // @Summary Get customer with structures
@@ -269,8 +725,8 @@ func GetCustomerWithStructures(c *gin.Context) {
 
 	var customer entity.Customer
 	result := config.DB.Preload("AccountManager").Preload("Structures", func(db *gorm.DB) *gorm.DB {
-		return db.Order("level ASC, name ASC")
-	}).First(&customer, id)
+		return db.Order("level ASC, position ASC, name ASC")
+	}).Where("id = ?", id).First(&customer)
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
 		return
@@ -2,15 +2,50 @@ package handler
 
 import (
 	"customer-api/internal/config"
+	"customer-api/internal/dto"
 	"customer-api/internal/entity"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// validateTeamLead memastikan team_lead adalah user yang terdaftar
+func validateTeamLead(c *gin.Context, team *entity.Teams) bool {
+	var user entity.User
+	if err := config.DB.Where("id = ?", team.TeamLead).First(&user).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "failed",
+			"message": "Team lead harus user yang terdaftar",
+		})
+		return false
+	}
+	return true
+}
+
+// findTeam memuat tim dari parameter :id, mengirim 404 jika tidak ada
+func findTeam(c *gin.Context) (*entity.Teams, bool) {
+	var team entity.Teams
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&team).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "failed",
+			"message": "Team tidak ditemukan",
+			"data":    err.Error(),
+		})
+		return nil, false
+	}
+	return &team, true
+}
+
 func GetTeams(c *gin.Context) {
 	var teams []entity.Teams
-	if result := config.DB.Find(&teams); result.Error != nil {
+	db := config.DB.Preload("TeamLeadUser")
+	if active := c.Query("active"); active != "" {
+		db = db.Where("is_active = ?", active == "true")
+	}
+	if result := db.Order("name").Find(&teams); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal mendapatkan teams",
@@ -26,8 +61,47 @@ func GetTeams(c *gin.Context) {
 	})
 }
 
+// @Summary Get team
+// @Description Get a team with its lead, members, territories and number of customers
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} entity.Teams
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/teams/{id} [get]
+func GetTeam(c *gin.Context) {
+	var team entity.Teams
+	err := config.DB.Preload("TeamLeadUser").Preload("Members.User").Preload("Territories.Group").
+		Where("id = ?", c.Param("id")).First(&team).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "failed",
+			"message": "Team tidak ditemukan",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	var customerCount int64
+	config.DB.Model(&entity.Customer{}).Where("team_id = ?", team.ID).Count(&customerCount)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "success",
+		"message":        "Team berhasil ditemukan",
+		"data":           team,
+		"customer_count": customerCount,
+	})
+}
+
 // create
 func CreateTeam(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	var team entity.Teams
 	if err := c.ShouldBindJSON(&team); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -37,7 +111,11 @@ func CreateTeam(c *gin.Context) {
 		})
 		return
 	}
-	if result := config.DB.Create(&team); result.Error != nil {
+	if !validateTeamLead(c, &team) {
+		return
+	}
+	team.IsActive = true
+	if result := config.DB.Omit(clause.Associations).Create(&team); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal membuat team",
@@ -56,8 +134,16 @@ func CreateTeam(c *gin.Context) {
 
 // update
 func UpdateTeam(c *gin.Context) {
-	var team entity.Teams
-	if err := c.ShouldBindJSON(&team); err != nil {
+	if !requireAdmin(c) {
+		return
+	}
+
+	team, ok := findTeam(c)
+	if !ok {
+		return
+	}
+	id := team.ID
+	if err := c.ShouldBindJSON(team); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "failed",
 			"message": "Gagal memperbarui team",
@@ -65,7 +151,11 @@ func UpdateTeam(c *gin.Context) {
 		})
 		return
 	}
-	if result := config.DB.Save(&team); result.Error != nil {
+	team.ID = id
+	if !validateTeamLead(c, team) {
+		return
+	}
+	if result := config.DB.Omit(clause.Associations).Save(team); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal memperbarui team",
@@ -83,6 +173,10 @@ func UpdateTeam(c *gin.Context) {
 
 // delete
 func DeleteTeam(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	var team entity.Teams
 	if err := config.DB.Where("id = ?", c.Param("id")).First(&team).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -93,11 +187,23 @@ func DeleteTeam(c *gin.Context) {
 
 		return
 	}
-	if result := config.DB.Delete(&team); result.Error != nil {
+
+	// customer milik tim dilepas, territory ikut dihapus
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Customer{}).Where("team_id = ?", team.ID).
+			Updates(map[string]interface{}{"team_id": nil, "team_source": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", team.ID).Delete(&entity.Territory{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&team).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal menghapus team",
-			"data":    result.Error.Error(),
+			"data":    err.Error(),
 		})
 		return
 	}
@@ -111,30 +217,36 @@ func DeleteTeam(c *gin.Context) {
 
 // teams detail
 func GetTeamDetails(c *gin.Context) {
-	var team entity.Teams
-	if err := config.DB.Where("id = ?", c.Param("id")).First(&team).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+	var teamDetails []entity.TeamsDetail
+	db := config.DB.Preload("User")
+	if teamsID := c.Query("teams_id"); teamsID != "" {
+		db = db.Where("teams_id = ?", teamsID)
+	}
+	if err := db.Order("employee_name").Find(&teamDetails).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
-			"message": "Team tidak ditemukan",
+			"message": "Gagal mendapatkan team detail",
 			"data":    err.Error(),
 		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Team berhasil ditemukan",
-		"data":    team,
+		"message": "Team detail berhasil ditemukan",
+		"data":    teamDetails,
 	})
 }
 
 func GetTeamDetail(c *gin.Context) {
 	var teamDetail entity.TeamsDetail
-	if err := config.DB.Where("id = ?", c.Param("id")).First(&teamDetail).Error; err != nil {
+	if err := config.DB.Preload("User").Where("id = ?", c.Param("id")).First(&teamDetail).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"status":  "failed",
 			"message": "Team detail tidak ditemukan",
 			"data":    err.Error(),
 		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -146,6 +258,10 @@ func GetTeamDetail(c *gin.Context) {
 
 // create teams detail
 func CreateTeamDetail(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	var teamDetail entity.TeamsDetail
 	if err := c.ShouldBindJSON(&teamDetail); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -156,8 +272,12 @@ func CreateTeamDetail(c *gin.Context) {
 
 		return
 	}
+	if status, message := validateTeamMember(&teamDetail, ""); status != 0 {
+		c.JSON(status, gin.H{"status": "failed", "message": message})
+		return
+	}
 
-	if result := config.DB.Create(&teamDetail); result.Error != nil {
+	if result := config.DB.Omit(clause.Associations).Create(&teamDetail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal membuat team detail",
@@ -175,7 +295,20 @@ func CreateTeamDetail(c *gin.Context) {
 
 // update teams detail
 func UpdateTeamDetail(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	var teamDetail entity.TeamsDetail
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&teamDetail).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "failed",
+			"message": "Team detail tidak ditemukan",
+			"data":    err.Error(),
+		})
+		return
+	}
+	id := teamDetail.ID
 	if err := c.ShouldBindJSON(&teamDetail); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "failed",
@@ -185,8 +318,13 @@ func UpdateTeamDetail(c *gin.Context) {
 
 		return
 	}
+	teamDetail.ID = id
+	if status, message := validateTeamMember(&teamDetail, id); status != 0 {
+		c.JSON(status, gin.H{"status": "failed", "message": message})
+		return
+	}
 
-	if result := config.DB.Save(&teamDetail); result.Error != nil {
+	if result := config.DB.Omit(clause.Associations).Save(&teamDetail); result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "failed",
 			"message": "Gagal memperbarui team detail",
@@ -204,6 +342,10 @@ func UpdateTeamDetail(c *gin.Context) {
 
 // delete teams detail
 func DeleteTeamDetail(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
 	var teamDetail entity.TeamsDetail
 	if err := config.DB.Where("id = ?", c.Param("id")).First(&teamDetail).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -230,3 +372,336 @@ func DeleteTeamDetail(c *gin.Context) {
 		"data":    teamDetail,
 	})
 }
+
+// validateTeamMember memastikan tim ada dan user (jika diisi) terdaftar serta belum menjadi anggota tim yang sama
+func validateTeamMember(member *entity.TeamsDetail, memberID string) (int, string) {
+	var team entity.Teams
+	if err := config.DB.Where("id = ?", member.TeamsID).First(&team).Error; err != nil {
+		return http.StatusBadRequest, "Team tidak ditemukan"
+	}
+	if member.UserID == nil {
+		return 0, ""
+	}
+	if strings.TrimSpace(*member.UserID) == "" {
+		member.UserID = nil
+		return 0, ""
+	}
+
+	var user entity.User
+	if err := config.DB.Where("id = ?", *member.UserID).First(&user).Error; err != nil {
+		return http.StatusBadRequest, "User tidak ditemukan"
+	}
+	var existing entity.TeamsDetail
+	query := config.DB.Where("teams_id = ? AND user_id = ?", member.TeamsID, *member.UserID)
+	if memberID != "" {
+		query = query.Where("id <> ?", memberID)
+	}
+	if err := query.First(&existing).Error; err == nil {
+		return http.StatusConflict, "User sudah menjadi anggota team ini"
+	}
+	if member.EmployeeName == "" {
+		member.EmployeeName = user.Username
+	}
+	return 0, ""
+}
+
+// @Summary Get team members
+// @Description Get the members of a team with their linked users
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {array} entity.TeamsDetail
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/teams/{id}/members [get]
+func GetTeamMembers(c *gin.Context) {
+	team, ok := findTeam(c)
+	if !ok {
+		return
+	}
+
+	members := []entity.TeamsDetail{}
+	if err := config.DB.Preload("User").Where("teams_id = ?", team.ID).Order("employee_name").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal mendapatkan anggota team",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Anggota team berhasil ditemukan",
+		"data":    members,
+	})
+}
+
+// @Summary Add team member
+// @Description Add a user to a team. employee_name defaults to the username.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param member body dto.TeamMemberRequest true "Member"
+// @Success 201 {object} entity.TeamsDetail
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/teams/{id}/members [post]
+func AddTeamMember(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	team, ok := findTeam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "failed",
+			"message": "Gagal menambahkan anggota team",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	member := entity.TeamsDetail{
+		TeamsID:      team.ID,
+		UserID:       &req.UserID,
+		JobPosition:  req.JobPosition,
+		EmployeeName: strings.TrimSpace(req.EmployeeName),
+		PhoneNumber:  strings.TrimSpace(req.PhoneNumber),
+		IsActive:     true,
+	}
+	if status, message := validateTeamMember(&member, ""); status != 0 {
+		c.JSON(status, gin.H{"status": "failed", "message": message})
+		return
+	}
+
+	if err := config.DB.Omit(clause.Associations).Create(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal menambahkan anggota team",
+			"data":    err.Error(),
+		})
+		return
+	}
+	config.DB.Preload("User").Where("id = ?", member.ID).First(&member)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Anggota team berhasil ditambahkan",
+		"data":    member,
+	})
+}
+
+// @Summary Get team territories
+// @Description Get the territory rules of a team
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {array} entity.Territory
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/teams/{id}/territories [get]
+func GetTeamTerritories(c *gin.Context) {
+	team, ok := findTeam(c)
+	if !ok {
+		return
+	}
+
+	territories := []entity.Territory{}
+	if err := config.DB.Preload("Group").Where("team_id = ?", team.ID).Order("priority").Order("name").Find(&territories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal mendapatkan territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Territory berhasil ditemukan",
+		"data":    territories,
+	})
+}
+
+// @Summary Get team customers
+// @Description Get the customers owned by a team
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param source query string false "Filter by assignment source (manual, rule)"
+// @Success 200 {array} dto.CustomerListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/teams/{id}/customers [get]
+func GetTeamCustomers(c *gin.Context) {
+	team, ok := findTeam(c)
+	if !ok {
+		return
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	db := policy.Filter(config.DB.Preload("AccountManager").Where("team_id = ?", team.ID), "id")
+	if source := c.Query("source"); source != "" {
+		db = db.Where("team_source = ?", source)
+	}
+
+	var result []entity.Customer
+	if err := db.Order("name").Find(&result).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal mendapatkan customer team",
+			"data":    err.Error(),
+		})
+		return
+	}
+	customers := make([]dto.CustomerListResponse, 0, len(result))
+	for _, customer := range result {
+		customers = append(customers, toCustomerListResponse(customer))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Customer team berhasil ditemukan",
+		"data":    customers,
+	})
+}
+
+// @Summary Assign customers to team
+// @Description Manually assign customers to a team. Manual assignments are kept by auto-assign unless overwrite is used.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.AssignTeamCustomersRequest true "Customer IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/teams/{id}/customers [post]
+func AssignTeamCustomers(c *gin.Context) {
+	team, ok := findTeam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AssignTeamCustomersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "failed", "message": err.Error()})
+		return
+	}
+	if len(req.CustomerIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "failed", "message": "customer_ids wajib diisi"})
+		return
+	}
+
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	// customer di luar scope user dianggap tidak ditemukan
+	var found int64
+	policy.Filter(config.DB.Model(&entity.Customer{}).Where("id IN ?", req.CustomerIDs), "id").Count(&found)
+	if int(found) != len(req.CustomerIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "failed", "message": "Sebagian customer tidak ditemukan"})
+		return
+	}
+
+	result := config.DB.Model(&entity.Customer{}).Where("id IN ?", req.CustomerIDs).
+		Updates(map[string]interface{}{"team_id": team.ID, "team_source": teamSourceManual})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal meng-assign customer",
+			"data":    result.Error.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Customer berhasil di-assign ke team",
+		"data":    gin.H{"team_id": team.ID, "assigned": result.RowsAffected},
+	})
+}
+
+// @Summary Set customer team
+// @Description Manually set or clear the team that owns a customer. Clearing lets auto-assign pick a team again.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param request body dto.CustomerTeamRequest true "Team"
+// @Success 200 {object} dto.CustomerListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/team [put]
+func UpdateCustomerTeam(c *gin.Context) {
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "failed", "message": "Customer tidak ditemukan"})
+		return
+	}
+
+	var req dto.CustomerTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "failed", "message": err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{"team_id": nil, "team_source": ""}
+	if req.TeamID != nil && *req.TeamID != "" {
+		var team entity.Teams
+		if err := config.DB.Where("id = ?", *req.TeamID).First(&team).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "failed", "message": "Team tidak ditemukan"})
+			return
+		}
+		updates = map[string]interface{}{"team_id": team.ID, "team_source": teamSourceManual}
+	}
+
+	if err := config.DB.Model(&customer).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal memperbarui team customer",
+			"data":    err.Error(),
+		})
+		return
+	}
+	config.DB.Preload("AccountManager").Where("id = ?", customer.ID).First(&customer)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Team customer berhasil diperbarui",
+		"data":    toCustomerListResponse(customer),
+	})
+}
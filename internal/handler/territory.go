@@ -0,0 +1,496 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"customer-api/internal/region"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	teamSourceManual = "manual"
+	teamSourceRule   = "rule"
+)
+
+// territoryCustomer berisi data customer yang dipakai untuk mencocokkan territory
+type territoryCustomer struct {
+	Customer    entity.Customer
+	RegionCodes map[string]bool // kode provinsi, kabupaten dan kecamatan dari alamat aktif
+	GroupIDs    map[string]bool
+}
+
+// territorySpecificity: territory dengan dua kriteria lebih spesifik dari satu kriteria,
+// dan kode wilayah yang lebih dalam lebih spesifik dari kode induknya
+func territorySpecificity(t entity.Territory) int {
+	score := len(t.RegionCode)
+	if t.RegionCode != "" {
+		score += 100
+	}
+	if t.GroupID != nil {
+		score += 100
+	}
+	return score
+}
+
+// loadActiveTerritories mengambil territory aktif milik tim aktif, urut dari yang paling diutamakan
+func loadActiveTerritories(db *gorm.DB) ([]entity.Territory, error) {
+	var territories []entity.Territory
+	err := db.Joins("JOIN teams ON teams.id = territories.team_id AND teams.deleted_at IS NULL AND teams.is_active = ?", true).
+		Where("territories.active = ?", true).
+		Find(&territories).Error
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(territories, func(i, j int) bool {
+		if territories[i].Priority != territories[j].Priority {
+			return territories[i].Priority < territories[j].Priority
+		}
+		si, sj := territorySpecificity(territories[i]), territorySpecificity(territories[j])
+		if si != sj {
+			return si > sj
+		}
+		return territories[i].Name < territories[j].Name
+	})
+	return territories, nil
+}
+
+// matchTerritory mengembalikan territory pertama yang cocok dengan customer
+func matchTerritory(territories []entity.Territory, customer *territoryCustomer) *entity.Territory {
+	for i := range territories {
+		t := &territories[i]
+		if t.RegionCode != "" && !customer.RegionCodes[t.RegionCode] {
+			continue
+		}
+		if t.GroupID != nil && !customer.GroupIDs[*t.GroupID] {
+			continue
+		}
+		return t
+	}
+	return nil
+}
+
+// loadTerritoryCustomers memuat customer beserta kode wilayah alamat dan grupnya
+func loadTerritoryCustomers(db *gorm.DB, customers []entity.Customer) (map[string]*territoryCustomer, error) {
+	result := make(map[string]*territoryCustomer, len(customers))
+	ids := make([]string, 0, len(customers))
+	for _, customer := range customers {
+		result[customer.ID] = &territoryCustomer{
+			Customer:    customer,
+			RegionCodes: map[string]bool{},
+			GroupIDs:    map[string]bool{},
+		}
+		ids = append(ids, customer.ID)
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var addresses []entity.Address
+	if err := db.Select("customer_id, province_code, regency_code, district_code").
+		Where("customer_id IN ? AND active = ?", ids, true).Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		for _, code := range []string{address.ProvinceCode, address.RegencyCode, address.DistrictCode} {
+			if code != "" {
+				result[address.CustomerID].RegionCodes[code] = true
+			}
+		}
+	}
+
+	var memberships []struct {
+		CustomerID string
+		GroupID    string
+	}
+	if err := db.Table("customer_groups").Select("customer_id, group_id").
+		Where("customer_id IN ?", ids).Scan(&memberships).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range memberships {
+		result[m.CustomerID].GroupIDs[m.GroupID] = true
+	}
+	return result, nil
+}
+
+// autoAssignCustomerTeams menerapkan aturan territory ke customer. customerIDs nil berarti semua customer.
+// Customer yang di-assign manual dilewati kecuali overwrite; assignment dari aturan yang tidak lagi
+// cocok dengan territory manapun dilepas.
+func autoAssignCustomerTeams(db *gorm.DB, customerIDs []string, overwrite, dryRun bool) ([]dto.TeamAssignmentChange, int, error) {
+	territories, err := loadActiveTerritories(db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := db.Model(&entity.Customer{})
+	if customerIDs != nil {
+		query = query.Where("id IN ?", customerIDs)
+	}
+	if !overwrite {
+		query = query.Where("team_id IS NULL OR team_source IS NULL OR team_source <> ?", teamSourceManual)
+	}
+	var customers []entity.Customer
+	if err := query.Order("name").Find(&customers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	infos, err := loadTerritoryCustomers(db, customers)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	changes := []dto.TeamAssignmentChange{}
+	unmatched := 0
+	for _, customer := range customers {
+		territory := matchTerritory(territories, infos[customer.ID])
+		var target *string
+		if territory != nil {
+			target = &territory.TeamID
+		} else {
+			unmatched++
+			// customer tanpa tim atau yang di-assign manual dibiarkan
+			if customer.TeamSource != teamSourceRule {
+				continue
+			}
+		}
+		if samePtr(customer.TeamID, target) && (target == nil || customer.TeamSource == teamSourceRule) {
+			continue
+		}
+
+		change := dto.TeamAssignmentChange{
+			CustomerID:   customer.ID,
+			CustomerName: customer.Name,
+			FromTeamID:   customer.TeamID,
+			ToTeamID:     target,
+		}
+		source := ""
+		if territory != nil {
+			change.TerritoryID = territory.ID
+			change.TerritoryName = territory.Name
+			source = teamSourceRule
+		}
+		if !dryRun {
+			if err := db.Model(&entity.Customer{}).Where("id = ?", customer.ID).
+				Updates(map[string]interface{}{"team_id": target, "team_source": source}).Error; err != nil {
+				return nil, 0, err
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, unmatched, nil
+}
+
+// validateTerritoryRequest memeriksa tim, kode wilayah dan grup pada request territory
+func validateTerritoryRequest(req *dto.TerritoryRequest) (int, string) {
+	req.RegionCode = strings.TrimSpace(req.RegionCode)
+	if req.GroupID != nil && strings.TrimSpace(*req.GroupID) == "" {
+		req.GroupID = nil
+	}
+	if req.RegionCode == "" && req.GroupID == nil {
+		return http.StatusBadRequest, "region_code atau group_id wajib diisi"
+	}
+
+	var team entity.Teams
+	if err := config.DB.Where("id = ?", req.TeamID).First(&team).Error; err != nil {
+		return http.StatusBadRequest, "Team tidak ditemukan"
+	}
+	if req.RegionCode != "" {
+		r, ok := region.Find(req.RegionCode)
+		if !ok || r.Level == region.LevelVillage {
+			return http.StatusBadRequest, "region_code harus kode provinsi, kabupaten/kota atau kecamatan yang valid"
+		}
+	}
+	if req.GroupID != nil {
+		var group entity.Group
+		if err := config.DB.Where("id = ?", *req.GroupID).First(&group).Error; err != nil {
+			return http.StatusBadRequest, "Group tidak ditemukan"
+		}
+	}
+	return 0, ""
+}
+
+// @Summary Get territories
+// @Description Get territory rules, optionally for one team
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param team_id query string false "Filter by team ID"
+// @Success 200 {array} entity.Territory
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/territories [get]
+func GetTerritories(c *gin.Context) {
+	db := config.DB.Preload("Group")
+	if teamID := c.Query("team_id"); teamID != "" {
+		db = db.Where("team_id = ?", teamID)
+	}
+
+	var territories []entity.Territory
+	if err := db.Order("priority").Order("name").Find(&territories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal mendapatkan territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Territory berhasil ditemukan",
+		"data":    territories,
+	})
+}
+
+// @Summary Create territory
+// @Description Create a territory rule. Customers whose active address lies in region_code and/or who belong to group_id are auto-assigned to the team.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param territory body dto.TerritoryRequest true "Territory"
+// @Success 201 {object} entity.Territory
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/territories [post]
+func CreateTerritory(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.TerritoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "failed",
+			"message": "Gagal membuat territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+	if status, message := validateTerritoryRequest(&req); status != 0 {
+		c.JSON(status, gin.H{"status": "failed", "message": message})
+		return
+	}
+
+	territory := entity.Territory{
+		TeamID:     req.TeamID,
+		Name:       req.Name,
+		RegionCode: req.RegionCode,
+		GroupID:    req.GroupID,
+		Priority:   100,
+		Active:     true,
+	}
+	if req.Priority != nil {
+		territory.Priority = *req.Priority
+	}
+	if req.Active != nil {
+		territory.Active = *req.Active
+	}
+
+	if err := config.DB.Select("*").Create(&territory).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal membuat territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Territory berhasil dibuat",
+		"data":    territory,
+	})
+}
+
+// @Summary Update territory
+// @Description Update a territory rule
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Territory ID"
+// @Param territory body dto.TerritoryRequest true "Territory"
+// @Success 200 {object} entity.Territory
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/territories/{id} [put]
+func UpdateTerritory(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var territory entity.Territory
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&territory).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "failed", "message": "Territory tidak ditemukan"})
+		return
+	}
+
+	var req dto.TerritoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "failed",
+			"message": "Gagal memperbarui territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+	if status, message := validateTerritoryRequest(&req); status != 0 {
+		c.JSON(status, gin.H{"status": "failed", "message": message})
+		return
+	}
+
+	territory.TeamID = req.TeamID
+	territory.Name = req.Name
+	territory.RegionCode = req.RegionCode
+	territory.GroupID = req.GroupID
+	if req.Priority != nil {
+		territory.Priority = *req.Priority
+	}
+	if req.Active != nil {
+		territory.Active = *req.Active
+	}
+
+	if err := config.DB.Save(&territory).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal memperbarui territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Territory berhasil diperbarui",
+		"data":    territory,
+	})
+}
+
+// @Summary Delete territory
+// @Description Delete a territory rule. Existing assignments stay until auto-assign runs again.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Territory ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/territories/{id} [delete]
+func DeleteTerritory(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var territory entity.Territory
+	if err := config.DB.Where("id = ?", c.Param("id")).First(&territory).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "failed", "message": "Territory tidak ditemukan"})
+		return
+	}
+	if err := config.DB.Delete(&territory).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal menghapus territory",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Territory berhasil dihapus",
+		"data":    territory,
+	})
+}
+
+// @Summary Auto-assign customers to teams
+// @Description Apply territory rules to customers. Manually assigned customers are skipped unless overwrite is true. Use dry_run to preview.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TeamAutoAssignRequest false "Auto-assign options"
+// @Success 200 {object} dto.TeamAutoAssignResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/teams/auto-assign [post]
+func AutoAssignTeams(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.TeamAutoAssignRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "failed", "message": err.Error()})
+			return
+		}
+	}
+	var customerIDs []string
+	if len(req.CustomerIDs) > 0 {
+		customerIDs = req.CustomerIDs
+	}
+
+	result := dto.TeamAutoAssignResult{DryRun: req.DryRun}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		changes, unmatched, err := autoAssignCustomerTeams(tx, customerIDs, req.Overwrite, req.DryRun)
+		if err != nil {
+			return err
+		}
+		result.Changes = changes
+		result.Changed = len(changes)
+		result.Unmatched = unmatched
+
+		query := tx.Model(&entity.Customer{})
+		if customerIDs != nil {
+			query = query.Where("id IN ?", customerIDs)
+		}
+		if !req.Overwrite {
+			query = query.Where("team_id IS NULL OR team_source IS NULL OR team_source <> ?", teamSourceManual)
+		}
+		var processed int64
+		if err := query.Count(&processed).Error; err != nil {
+			return err
+		}
+		result.Processed = int(processed)
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "failed",
+			"message": "Gagal menjalankan auto-assign",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Auto-assign selesai",
+		"data":    result,
+	})
+}
+
+// reassignTeamByAddress menjalankan ulang aturan territory setelah alamat atau group customer berubah
+func reassignTeamByAddress(customerID string) {
+	if _, _, err := autoAssignCustomerTeams(config.DB, []string{customerID}, false, false); err != nil {
+		log.Printf("failed to auto-assign team for customer %s: %v", customerID, err)
+	}
+}
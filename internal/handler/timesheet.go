@@ -0,0 +1,821 @@
+package handler
+
+import (
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	timeEntryDraft     = "draft"
+	timeEntrySubmitted = "submitted"
+	timeEntryApproved  = "approved"
+	timeEntryRejected  = "rejected"
+
+	maxHoursPerDay = 24
+)
+
+// timesheetReportGroups memetakan group_by laporan ke kolom ID dan nama
+var timesheetReportGroups = map[string][2]string{
+	"user":     {"te.user_id", "u.username"},
+	"project":  {"te.project_id", "p.name"},
+	"customer": {"p.customer_id", "c.name"},
+}
+
+// weekStartOf mengembalikan hari Senin dari minggu tanggal t
+func weekStartOf(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// projectRoleOf mengembalikan peran user di project: owner, peran anggota, atau "" jika bukan anggota
+func projectRoleOf(db *gorm.DB, project entity.Project, userID string) string {
+	if project.OwnerID != nil && *project.OwnerID == userID {
+		return "owner"
+	}
+	var member entity.ProjectMember
+	if err := db.Where("project_id = ? AND user_id = ?", project.ID, userID).First(&member).Error; err != nil {
+		return ""
+	}
+	return member.Role
+}
+
+// visibleProject mengambil project yang customer-nya terlihat oleh user
+func visibleProject(policy *access.Context, projectID string) (entity.Project, error) {
+	var project entity.Project
+	if err := policy.Filter(config.DB, "customer_id").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return project, errors.New("project not found")
+	}
+	return project, nil
+}
+
+// visibleProjectIDs adalah subquery ID project milik customer yang terlihat oleh user
+func visibleProjectIDs(policy *access.Context) *gorm.DB {
+	return policy.Filter(config.DB.Model(&entity.Project{}).Select("id"), "customer_id")
+}
+
+// checkTimeEntryLinks memastikan aktivitas/event yang dirujuk terkait dengan project
+func checkTimeEntryLinks(db *gorm.DB, project entity.Project, activityID, eventID *string) error {
+	if activityID != nil {
+		var activity entity.Activity
+		if err := db.Where("id = ?", *activityID).First(&activity).Error; err != nil {
+			return errors.New("activity not found")
+		}
+		if (activity.ProjectID != nil && *activity.ProjectID != project.ID) || activity.CustomerID != project.CustomerID {
+			return errors.New("activity does not belong to this project")
+		}
+	}
+	if eventID != nil {
+		var event entity.Event
+		if err := db.Where("id = ?", *eventID).First(&event).Error; err != nil {
+			return errors.New("event not found")
+		}
+		if event.ProjectID != project.ID {
+			return errors.New("event does not belong to this project")
+		}
+	}
+	return nil
+}
+
+// checkDailyHours memastikan total jam user pada satu hari tidak melebihi 24 jam
+func checkDailyHours(db *gorm.DB, userID string, date time.Time, hours float64, excludeID string) error {
+	var logged float64
+	query := db.Model(&entity.TimeEntry{}).Select("COALESCE(SUM(hours), 0)").Where("user_id = ? AND date = ?", userID, date)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Scan(&logged).Error; err != nil {
+		return err
+	}
+	if logged+hours > maxHoursPerDay {
+		return errors.New("total hours on " + date.Format("2006-01-02") + " would exceed 24")
+	}
+	return nil
+}
+
+// weekApproved bernilai true jika timesheet user di project untuk minggu tanggal tersebut sudah disetujui,
+// sehingga entry baru di minggu itu tidak akan pernah bisa diajukan atau ditagihkan
+func weekApproved(db *gorm.DB, userID, projectID string, date time.Time) (bool, error) {
+	var count int64
+	err := db.Model(&entity.Timesheet{}).
+		Where("user_id = ? AND project_id = ? AND week_start = ? AND status = ?", userID, projectID, weekStartOf(date), timeEntryApproved).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// optionalID mengubah ID opsional dari request; string kosong berarti dilepas
+func optionalID(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+// findOwnTimeEntry mengambil time entry milik user yang login yang masih boleh diubah
+func findOwnTimeEntry(c *gin.Context, userID string) (entity.TimeEntry, bool) {
+	var entry entity.TimeEntry
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+		return entry, false
+	}
+	if entry.Status != timeEntryDraft && entry.Status != timeEntryRejected {
+		c.JSON(http.StatusConflict, gin.H{"error": "Time entry is " + entry.Status + " and can no longer be changed"})
+		return entry, false
+	}
+	return entry, true
+}
+
+// @Summary Get time entries
+// @Description List time entries on projects of visible customers
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Filter by user"
+// @Param project_id query string false "Filter by project"
+// @Param status query string false "draft, submitted, approved or rejected"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {array} entity.TimeEntry
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/time-entries [get]
+func GetTimeEntries(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	db := config.DB.Preload("User").Preload("Project")
+	if policy.Restricted() {
+		db = db.Where("project_id IN (?)", visibleProjectIDs(policy))
+	}
+	for _, param := range []string{"user_id", "project_id", "status"} {
+		if value := c.Query(param); value != "" {
+			db = db.Where(param+" = ?", value)
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := parseDocumentDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return
+		}
+		db = db.Where("date >= ?", *from)
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := parseDocumentDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+			return
+		}
+		db = db.Where("date <= ?", *to)
+	}
+
+	entries := []entity.TimeEntry{}
+	if err := db.Order("date DESC, created_at DESC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Time entries fetched successfully",
+		"data":    entries,
+	})
+}
+
+// @Summary Log time
+// @Description Log hours of the current user on a project they are a member of. New entries are drafts until the week is submitted.
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entry body dto.CreateTimeEntryRequest true "Time entry"
+// @Success 201 {object} entity.TimeEntry
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/time-entries [post]
+func CreateTimeEntry(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	project, err := visibleProject(policy, req.ProjectID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if role := projectRoleOf(config.DB, project, userID); role == "" || role == "viewer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only project members can log time on this project"})
+		return
+	}
+	date, err := parseDocumentDate(req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date: " + err.Error()})
+		return
+	}
+	if approved, err := weekApproved(config.DB, userID, project.ID, *date); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheet"})
+		return
+	} else if approved {
+		c.JSON(http.StatusConflict, gin.H{"error": "The timesheet of this week is already approved"})
+		return
+	}
+
+	entry := entity.TimeEntry{
+		UserID:     userID,
+		ProjectID:  project.ID,
+		ActivityID: optionalID(req.ActivityID),
+		EventID:    optionalID(req.EventID),
+		Date:       *date,
+		Hours:      req.Hours,
+		Billable:   true,
+		Note:       req.Note,
+		Status:     timeEntryDraft,
+	}
+	if req.Billable != nil {
+		entry.Billable = *req.Billable
+	}
+	if err := checkTimeEntryLinks(config.DB, project, entry.ActivityID, entry.EventID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkDailyHours(config.DB, userID, entry.Date, entry.Hours, ""); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Omit(clause.Associations).Create(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create time entry"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Time entry created successfully",
+		"data":    entry,
+	})
+}
+
+// @Summary Get time entry
+// @Description Get a time entry
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Time entry ID"
+// @Success 200 {object} entity.TimeEntry
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/time-entries/{id} [get]
+func GetTimeEntry(c *gin.Context) {
+	var entry entity.TimeEntry
+	if err := config.DB.Preload("User").Preload("Project").Where("id = ?", c.Param("id")).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Time entry fetched successfully",
+		"data":    entry,
+	})
+}
+
+// @Summary Update time entry
+// @Description Update an own draft or rejected time entry
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Time entry ID"
+// @Param entry body dto.UpdateTimeEntryRequest true "Time entry"
+// @Success 200 {object} entity.TimeEntry
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/time-entries/{id} [put]
+func UpdateTimeEntry(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	entry, ok := findOwnTimeEntry(c, userID)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ActivityID != nil {
+		entry.ActivityID = optionalID(req.ActivityID)
+	}
+	if req.EventID != nil {
+		entry.EventID = optionalID(req.EventID)
+	}
+	if req.Date != nil {
+		date, err := parseDocumentDate(*req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date: " + err.Error()})
+			return
+		}
+		if date.Format("2006-01-02") != entry.Date.Format("2006-01-02") {
+			// entry pindah tanggal, jadi tidak lagi terikat ke timesheet lamanya
+			entry.TimesheetID = nil
+		}
+		entry.Date = *date
+	}
+	if req.Hours != nil {
+		entry.Hours = *req.Hours
+	}
+	if req.Billable != nil {
+		entry.Billable = *req.Billable
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+
+	var project entity.Project
+	if err := config.DB.Where("id = ?", entry.ProjectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Project not found"})
+		return
+	}
+	if err := checkTimeEntryLinks(config.DB, project, entry.ActivityID, entry.EventID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkDailyHours(config.DB, userID, entry.Date, entry.Hours, entry.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if approved, err := weekApproved(config.DB, userID, entry.ProjectID, entry.Date); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheet"})
+		return
+	} else if approved {
+		c.JSON(http.StatusConflict, gin.H{"error": "The timesheet of this week is already approved"})
+		return
+	}
+
+	if err := config.DB.Omit(clause.Associations).Save(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update time entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Time entry updated successfully",
+		"data":    entry,
+	})
+}
+
+// @Summary Delete time entry
+// @Description Delete an own draft or rejected time entry
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Time entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/time-entries/{id} [delete]
+func DeleteTimeEntry(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	entry, ok := findOwnTimeEntry(c, userID)
+	if !ok {
+		return
+	}
+	if err := config.DB.Delete(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete time entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Time entry deleted successfully"})
+}
+
+// @Summary Submit weekly timesheet
+// @Description Submit the current user's draft and rejected time entries of one week (Monday-Sunday) on a project for approval by the project owner
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param timesheet body dto.SubmitTimesheetRequest true "Project and week"
+// @Success 200 {object} entity.Timesheet
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/timesheets [post]
+func SubmitTimesheet(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SubmitTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	project, err := visibleProject(policy, req.ProjectID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := parseDocumentDate(req.Week)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "week: " + err.Error()})
+		return
+	}
+	weekStart := weekStartOf(*date)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	var timesheet entity.Timesheet
+	err = config.DB.Where("user_id = ? AND project_id = ? AND week_start = ?", userID, project.ID, weekStart).First(&timesheet).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheet"})
+		return
+	}
+	if timesheet.Status == timeEntryApproved {
+		c.JSON(http.StatusConflict, gin.H{"error": "The timesheet of this week is already approved"})
+		return
+	}
+
+	var entryIDs []string
+	if err := config.DB.Model(&entity.TimeEntry{}).
+		Where("user_id = ? AND project_id = ? AND date >= ? AND date < ?", userID, project.ID, weekStart, weekEnd).
+		Where("status IN ?", []string{timeEntryDraft, timeEntryRejected}).
+		Pluck("id", &entryIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+	if len(entryIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No draft time entries in this week"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		timesheet.UserID = userID
+		timesheet.ProjectID = project.ID
+		timesheet.WeekStart = weekStart
+		timesheet.Status = timeEntrySubmitted
+		timesheet.SubmittedAt = time.Now()
+		timesheet.ReviewedBy = nil
+		timesheet.ReviewedAt = nil
+		timesheet.ReviewNote = ""
+		if err := tx.Omit(clause.Associations).Save(&timesheet).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.TimeEntry{}).Where("id IN ?", entryIDs).
+			Updates(map[string]interface{}{"status": timeEntrySubmitted, "timesheet_id": timesheet.ID}).Error; err != nil {
+			return err
+		}
+
+		// total dihitung ulang dari semua entry minggu ini yang masuk timesheet
+		var totals struct {
+			Total    float64
+			Billable float64
+		}
+		if err := tx.Model(&entity.TimeEntry{}).
+			Select("COALESCE(SUM(hours), 0) AS total, COALESCE(SUM(hours) FILTER (WHERE billable), 0) AS billable").
+			Where("timesheet_id = ? AND date >= ? AND date < ?", timesheet.ID, weekStart, weekEnd).
+			Scan(&totals).Error; err != nil {
+			return err
+		}
+		timesheet.TotalHours = totals.Total
+		timesheet.BillableHours = totals.Billable
+		return tx.Omit(clause.Associations).Save(&timesheet).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit timesheet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Timesheet submitted successfully",
+		"data":    timesheet,
+	})
+}
+
+// @Summary Get timesheets
+// @Description List weekly timesheets. With to_review=true only submitted timesheets of projects owned by the current user are returned.
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Filter by user"
+// @Param project_id query string false "Filter by project"
+// @Param status query string false "submitted, approved or rejected"
+// @Param to_review query bool false "Only timesheets waiting for my approval"
+// @Success 200 {array} entity.Timesheet
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/timesheets [get]
+func GetTimesheets(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	db := config.DB.Preload("User").Preload("Project")
+	if policy.Restricted() {
+		db = db.Where("project_id IN (?)", visibleProjectIDs(policy))
+	}
+	for _, param := range []string{"user_id", "project_id", "status"} {
+		if value := c.Query(param); value != "" {
+			db = db.Where(param+" = ?", value)
+		}
+	}
+	if c.Query("to_review") == "true" {
+		userID, exists := currentUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+		db = db.Where("status = ? AND project_id IN (SELECT id FROM projects WHERE owner_id = ?)", timeEntrySubmitted, userID)
+	}
+
+	timesheets := []entity.Timesheet{}
+	if err := db.Order("week_start DESC, submitted_at DESC").Find(&timesheets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timesheets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Timesheets fetched successfully",
+		"data":    timesheets,
+	})
+}
+
+// @Summary Get timesheet
+// @Description Get a weekly timesheet with its time entries
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Timesheet ID"
+// @Success 200 {object} entity.Timesheet
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/timesheets/{id} [get]
+func GetTimesheet(c *gin.Context) {
+	var timesheet entity.Timesheet
+	err := config.DB.Preload("User").Preload("Project").
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("date, created_at") }).
+		Where("id = ?", c.Param("id")).First(&timesheet).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Timesheet not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Timesheet fetched successfully",
+		"data":    timesheet,
+	})
+}
+
+// reviewTimesheet menyetujui atau menolak timesheet yang sudah diajukan; hanya owner project atau admin
+func reviewTimesheet(c *gin.Context, status string) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	// catatan opsional saat approve, body boleh kosong
+	var req dto.ReviewTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if status == timeEntryRejected && req.Note == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A note is required when rejecting a timesheet"})
+		return
+	}
+
+	var timesheet entity.Timesheet
+	if err := config.DB.Preload("Project").Where("id = ?", c.Param("id")).First(&timesheet).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Timesheet not found"})
+		return
+	}
+	if timesheet.Project == nil || (!policy.IsAdmin && (timesheet.Project.OwnerID == nil || *timesheet.Project.OwnerID != userID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can review this timesheet"})
+		return
+	}
+	if timesheet.UserID == userID && !policy.IsAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot review your own timesheet"})
+		return
+	}
+	if timesheet.Status != timeEntrySubmitted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Timesheet is already " + timesheet.Status})
+		return
+	}
+
+	now := time.Now()
+	timesheet.Status = status
+	timesheet.ReviewedBy = &userID
+	timesheet.ReviewedAt = &now
+	timesheet.ReviewNote = req.Note
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&timesheet).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.TimeEntry{}).Where("timesheet_id = ? AND status = ?", timesheet.ID, timeEntrySubmitted).
+			Update("status", status).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review timesheet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Timesheet " + status + " successfully",
+		"data":    timesheet,
+	})
+}
+
+// @Summary Approve timesheet
+// @Description Approve a submitted weekly timesheet. Only the project owner (or an admin) can approve, and never their own timesheet.
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Timesheet ID"
+// @Param review body dto.ReviewTimesheetRequest false "Note"
+// @Success 200 {object} entity.Timesheet
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/timesheets/{id}/approve [post]
+func ApproveTimesheet(c *gin.Context) {
+	reviewTimesheet(c, timeEntryApproved)
+}
+
+// @Summary Reject timesheet
+// @Description Reject a submitted weekly timesheet with a note. Its entries become editable again and can be resubmitted.
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Timesheet ID"
+// @Param review body dto.ReviewTimesheetRequest true "Note"
+// @Success 200 {object} entity.Timesheet
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/timesheets/{id}/reject [post]
+func RejectTimesheet(c *gin.Context) {
+	reviewTimesheet(c, timeEntryRejected)
+}
+
+// @Summary Get timesheet report
+// @Description Total, billable, approved and invoiced hours per user, project or customer in a period
+// @Tags Timesheets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group_by query string false "user, project or customer (default: project)"
+// @Param from query string false "From date (YYYY-MM-DD), defaults to the first day of the current month"
+// @Param to query string false "To date (YYYY-MM-DD), defaults to today"
+// @Param user_id query string false "Filter by user"
+// @Param project_id query string false "Filter by project"
+// @Param customer_id query string false "Filter by customer"
+// @Success 200 {object} dto.TimesheetReportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/time-entries/report [get]
+func GetTimesheetReport(c *gin.Context) {
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", "project")
+	columns, valid := timesheetReportGroups[groupBy]
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be user, project or customer"})
+		return
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseDocumentDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return
+		}
+		from = *parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseDocumentDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+			return
+		}
+		to = *parsed
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+
+	db := config.DB.Table("time_entries AS te").
+		Joins("JOIN projects p ON p.id = te.project_id").
+		Joins("JOIN users u ON u.id = te.user_id").
+		Joins("LEFT JOIN customers c ON c.id = p.customer_id").
+		Where("te.deleted_at IS NULL AND te.date >= ? AND te.date <= ?", from, to)
+	db = policy.Filter(db, "p.customer_id")
+	if value := c.Query("user_id"); value != "" {
+		db = db.Where("te.user_id = ?", value)
+	}
+	if value := c.Query("project_id"); value != "" {
+		db = db.Where("te.project_id = ?", value)
+	}
+	if value := c.Query("customer_id"); value != "" {
+		db = db.Where("p.customer_id = ?", value)
+	}
+
+	rows := []dto.TimesheetReportRow{}
+	err := db.Select(columns[0] + " AS id, COALESCE(" + columns[1] + ", '') AS name, COUNT(*) AS entries," +
+		" COALESCE(SUM(te.hours), 0) AS total_hours," +
+		" COALESCE(SUM(te.hours) FILTER (WHERE te.billable), 0) AS billable_hours," +
+		" COALESCE(SUM(te.hours) FILTER (WHERE te.status = 'approved'), 0) AS approved_hours," +
+		" COALESCE(SUM(te.hours) FILTER (WHERE te.status = 'approved' AND te.billable), 0) AS approved_billable_hours," +
+		" COALESCE(SUM(te.hours) FILTER (WHERE te.invoice_line_id IS NOT NULL), 0) AS invoiced_hours").
+		Group(columns[0] + ", " + columns[1]).Order("total_hours DESC").Scan(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute timesheet report"})
+		return
+	}
+
+	response := dto.TimesheetReportResponse{
+		GroupBy: groupBy,
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		Rows:    rows,
+	}
+	for _, row := range rows {
+		response.Total.Entries += row.Entries
+		response.Total.TotalHours += row.TotalHours
+		response.Total.BillableHours += row.BillableHours
+		response.Total.ApprovedHours += row.ApprovedHours
+		response.Total.ApprovedBillableHours += row.ApprovedBillableHours
+		response.Total.InvoicedHours += row.InvoicedHours
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Timesheet report fetched successfully",
+		"data":    response,
+	})
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"customer-api/internal/config"
+	"customer-api/internal/dto"
+	"customer-api/internal/entity"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// billableHours adalah jam billable satu konsultan yang akan menjadi satu baris invoice
+type billableHours struct {
+	userID   string
+	username string
+	hours    float64
+	from, to time.Time
+	entryIDs []string
+}
+
+// errTimeAlreadyInvoiced menandakan sebagian jam sudah di-invoice oleh request lain
+var errTimeAlreadyInvoiced = errors.New("time entries already invoiced")
+
+// roundAmount membulatkan nilai ke dua desimal
+func roundAmount(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// @Summary Invoice approved time
+// @Description Convert the approved, billable and not yet invoiced hours of a project into invoice lines, one line per consultant. The lines are added to an existing invoice of the same customer (invoice_id) or to a new invoice. Only the project owner or an admin can invoice time.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param invoice body dto.InvoiceTimeRequest true "Target invoice and rate"
+// @Success 200 {object} dto.InvoiceResponse
+// @Success 201 {object} dto.InvoiceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/projects/{id}/invoice-time [post]
+func InvoiceProjectTime(c *gin.Context) {
+	userID, exists := currentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	policy, ok := accessScope(c)
+	if !ok {
+		return
+	}
+	project, ok := findProject(c)
+	if !ok {
+		return
+	}
+	if !policy.IsAdmin && (project.OwnerID == nil || *project.OwnerID != userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can invoice project time"})
+		return
+	}
+
+	var req dto.InvoiceTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if project.CustomerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Project is not linked to a customer"})
+		return
+	}
+	rate := project.HourlyRate
+	if req.HourlyRate != nil {
+		rate = *req.HourlyRate
+	}
+	if rate <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hourly_rate is required because the project has no hourly rate"})
+		return
+	}
+
+	query := config.DB.Preload("User").
+		Where("project_id = ? AND status = ? AND billable = ? AND invoice_line_id IS NULL", project.ID, timeEntryApproved, true)
+	if req.Until != nil && *req.Until != "" {
+		until, err := parseDocumentDate(*req.Until)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until: " + err.Error()})
+			return
+		}
+		query = query.Where("date <= ?", *until)
+	}
+	var entries []entity.TimeEntry
+	if err := query.Order("date").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No approved billable hours to invoice"})
+		return
+	}
+
+	// satu baris invoice per konsultan
+	groups := map[string]*billableHours{}
+	for _, entry := range entries {
+		group := groups[entry.UserID]
+		if group == nil {
+			group = &billableHours{userID: entry.UserID, from: entry.Date}
+			if entry.User != nil {
+				group.username = entry.User.Username
+			}
+			groups[entry.UserID] = group
+		}
+		group.hours += entry.Hours
+		group.to = entry.Date
+		group.entryIDs = append(group.entryIDs, entry.ID)
+	}
+	lines := make([]*billableHours, 0, len(groups))
+	for _, group := range groups {
+		lines = append(lines, group)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].username < lines[j].username })
+
+	// invoice tujuan: invoice customer yang sama, atau invoice baru
+	var invoice entity.Invoice
+	created := req.InvoiceID == nil || *req.InvoiceID == ""
+	if !created {
+		if err := config.DB.Where("id = ?", *req.InvoiceID).First(&invoice).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice not found"})
+			return
+		}
+		if invoice.CustomerID != project.CustomerID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice belongs to another customer"})
+			return
+		}
+		if invoice.ProjectID != "" && invoice.ProjectID != project.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice belongs to another project"})
+			return
+		}
+	} else {
+		if req.InvoiceNumber == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invoice_number is required when creating a new invoice"})
+			return
+		}
+		var existing int64
+		config.DB.Model(&entity.Invoice{}).Where("invoice_number = ?", req.InvoiceNumber).Count(&existing)
+		if existing > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Invoice number already exists"})
+			return
+		}
+		now := time.Now()
+		issued := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		if req.IssuedDate != nil && *req.IssuedDate != "" {
+			parsed, err := parseDocumentDate(*req.IssuedDate)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "issued_date: " + err.Error()})
+				return
+			}
+			issued = *parsed
+		}
+		due := issued.AddDate(0, 0, 30)
+		if req.DueDate != nil && *req.DueDate != "" {
+			parsed, err := parseDocumentDate(*req.DueDate)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "due_date: " + err.Error()})
+				return
+			}
+			due = *parsed
+		}
+		invoice = entity.Invoice{
+			CustomerID:    project.CustomerID,
+			ProjectID:     project.ID,
+			InvoiceNumber: req.InvoiceNumber,
+			IssuedDate:    issued,
+			DueDate:       due,
+		}
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if created {
+			if err := tx.Omit(clause.Associations).Create(&invoice).Error; err != nil {
+				return err
+			}
+		}
+
+		var total float64
+		for _, group := range lines {
+			userID := group.userID
+			line := entity.InvoiceLine{
+				InvoiceID: invoice.ID,
+				ProjectID: &project.ID,
+				UserID:    &userID,
+				Description: "Consulting hours " + project.Name + " - " + group.username +
+					" (" + group.from.Format("2006-01-02") + " - " + group.to.Format("2006-01-02") + ")",
+				Quantity:  roundAmount(group.hours),
+				UnitPrice: rate,
+				Amount:    roundAmount(group.hours * rate),
+			}
+			if err := tx.Create(&line).Error; err != nil {
+				return err
+			}
+			// hanya jam yang belum di-invoice; request paralel tidak boleh menagih jam yang sama
+			updated := tx.Model(&entity.TimeEntry{}).Where("id IN ? AND invoice_line_id IS NULL", group.entryIDs).
+				Update("invoice_line_id", line.ID)
+			if updated.Error != nil {
+				return updated.Error
+			}
+			if updated.RowsAffected != int64(len(group.entryIDs)) {
+				return errTimeAlreadyInvoiced
+			}
+			total += line.Amount
+		}
+
+		return tx.Model(&invoice).Update("amount", gorm.Expr("ROUND((amount + ?)::numeric, 2)", total)).Error
+	})
+	if errors.Is(err, errTimeAlreadyInvoiced) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Some hours were invoiced by another request, please try again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to invoice project time"})
+		return
+	}
+	refreshHealthScore(invoice.CustomerID, "invoice")
+
+	// Load customer and lines for response
+	config.DB.Preload("Customer").Preload("Lines").Where("id = ?", invoice.ID).First(&invoice)
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"message": "Project time invoiced successfully",
+		"data":    convertToInvoiceResponse(invoice),
+	})
+}
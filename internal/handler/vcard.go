@@ -0,0 +1,540 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"customer-api/internal/config"
+	"customer-api/internal/entity"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const vcardMaxImportSize = 2 << 20 // 2 MB
+
+// vcardCard adalah satu kartu hasil parsing, hanya field yang dipetakan ke entity.Contact
+type vcardCard struct {
+	Name        string
+	JobPosition string
+	Position    string
+	Department  string
+	Emails      []string
+	Phone       string
+	Mobile      string
+	Birthdate   *time.Time
+}
+
+type vcardProperty struct {
+	Name   string
+	Params map[string][]string
+	Value  string
+}
+
+// parseVCards membaca satu atau lebih vCard (versi 2.1, 3.0 dan 4.0)
+func parseVCards(r io.Reader) ([]vcardCard, error) {
+	lines, err := unfoldVCardLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []vcardCard
+	var current *vcardCard
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, ok := parseVCardLine(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid vCard line %d", i+1)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			if strings.EqualFold(prop.Value, "VCARD") {
+				current = &vcardCard{}
+			}
+			continue
+		case "END":
+			if strings.EqualFold(prop.Value, "VCARD") && current != nil {
+				cards = append(cards, *current)
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch prop.Name {
+		case "FN":
+			current.Name = unescapeVCardValue(prop.Value)
+		case "N":
+			// FN lebih diutamakan, N hanya dipakai jika FN kosong
+			if current.Name == "" {
+				parts := splitVCardValue(prop.Value, ';')
+				var names []string
+				for _, idx := range []int{3, 1, 2, 0, 4} { // prefix, given, additional, family, suffix
+					if idx < len(parts) && parts[idx] != "" {
+						names = append(names, parts[idx])
+					}
+				}
+				current.Name = strings.Join(names, " ")
+			}
+		case "TITLE":
+			current.JobPosition = unescapeVCardValue(prop.Value)
+		case "ROLE":
+			current.Position = unescapeVCardValue(prop.Value)
+		case "ORG":
+			// ORG:Nama Perusahaan;Departemen
+			parts := splitVCardValue(prop.Value, ';')
+			if len(parts) > 1 {
+				current.Department = strings.Join(parts[1:], " / ")
+			}
+		case "EMAIL":
+			if email := strings.TrimSpace(unescapeVCardValue(prop.Value)); email != "" {
+				current.Emails = append(current.Emails, email)
+			}
+		case "TEL":
+			number := strings.TrimSpace(strings.TrimPrefix(unescapeVCardValue(prop.Value), "tel:"))
+			if number == "" {
+				continue
+			}
+			if vcardHasType(prop, "CELL") {
+				if current.Mobile == "" {
+					current.Mobile = number
+				}
+			} else if current.Phone == "" {
+				current.Phone = number
+			}
+		case "BDAY":
+			if birthdate, ok := parseVCardDate(prop.Value); ok {
+				current.Birthdate = &birthdate
+			}
+		}
+	}
+
+	return cards, nil
+}
+
+// unfoldVCardLines menggabungkan baris lanjutan (diawali spasi/tab) sesuai RFC 6350
+func unfoldVCardLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), vcardMaxImportSize)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseVCardLine(line string) (vcardProperty, bool) {
+	// cari ':' pertama di luar tanda kutip
+	inQuote := false
+	sep := -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuote = !inQuote
+		} else if ch == ':' && !inQuote {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return vcardProperty{}, false
+	}
+
+	head := strings.Split(line[:sep], ";")
+	name := strings.ToUpper(head[0])
+	// buang prefix group, contoh: item1.EMAIL
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+
+	prop := vcardProperty{Name: name, Params: map[string][]string{}, Value: line[sep+1:]}
+	for _, param := range head[1:] {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			// gaya vCard 2.1: TEL;CELL:...
+			prop.Params["TYPE"] = append(prop.Params["TYPE"], strings.ToUpper(key))
+			continue
+		}
+		for _, v := range strings.Split(strings.Trim(value, `"`), ",") {
+			prop.Params[strings.ToUpper(key)] = append(prop.Params[strings.ToUpper(key)], strings.ToUpper(v))
+		}
+	}
+	return prop, true
+}
+
+func vcardHasType(prop vcardProperty, t string) bool {
+	for _, v := range prop.Params["TYPE"] {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVCardDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "20060102", time.RFC3339, "2006-01-02T15:04:05Z"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitVCardValue memecah value dengan separator yang tidak di-escape
+func splitVCardValue(value string, sep byte) []string {
+	var parts []string
+	var buf strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			buf.WriteByte(value[i])
+			buf.WriteByte(value[i+1])
+			i++
+			continue
+		}
+		if value[i] == sep {
+			parts = append(parts, unescapeVCardValue(buf.String()))
+			buf.Reset()
+			continue
+		}
+		buf.WriteByte(value[i])
+	}
+	return append(parts, unescapeVCardValue(buf.String()))
+}
+
+func unescapeVCardValue(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+func escapeVCardValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+	return replacer.Replace(value)
+}
+
+// writeVCardLine menulis satu baris dan melipatnya per 75 byte sesuai RFC 6350
+func writeVCardLine(buf *bytes.Buffer, line string) {
+	for len(line) > 75 {
+		cut := 75
+		// jangan memotong di tengah karakter UTF-8
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	buf.WriteString(line + "\r\n")
+}
+
+// formatVCard menghasilkan vCard 3.0 untuk satu contact
+func formatVCard(buf *bytes.Buffer, contact entity.Contact, customerName string) {
+	writeVCardLine(buf, "BEGIN:VCARD")
+	writeVCardLine(buf, "VERSION:3.0")
+	writeVCardLine(buf, "UID:"+contact.ID)
+	writeVCardLine(buf, "FN:"+escapeVCardValue(contact.Name))
+
+	// N:Family;Given;;; - kata terakhir dianggap nama keluarga
+	given, family := contact.Name, ""
+	if idx := strings.LastIndex(contact.Name, " "); idx > 0 {
+		given, family = contact.Name[:idx], contact.Name[idx+1:]
+	}
+	writeVCardLine(buf, "N:"+escapeVCardValue(family)+";"+escapeVCardValue(given)+";;;")
+
+	if customerName != "" || contact.Department != "" {
+		org := escapeVCardValue(customerName)
+		if contact.Department != "" {
+			org += ";" + escapeVCardValue(contact.Department)
+		}
+		writeVCardLine(buf, "ORG:"+org)
+	}
+	if contact.JobPosition != "" {
+		writeVCardLine(buf, "TITLE:"+escapeVCardValue(contact.JobPosition))
+	}
+	if contact.Position != "" {
+		writeVCardLine(buf, "ROLE:"+escapeVCardValue(contact.Position))
+	}
+	if contact.Email != "" {
+		writeVCardLine(buf, "EMAIL;TYPE=INTERNET,WORK:"+escapeVCardValue(contact.Email))
+	}
+	if contact.Phone != "" {
+		writeVCardLine(buf, "TEL;TYPE=WORK,VOICE:"+escapeVCardValue(contact.Phone))
+	}
+	if contact.Mobile != "" {
+		writeVCardLine(buf, "TEL;TYPE=CELL:"+escapeVCardValue(contact.Mobile))
+	}
+	if contact.Birthdate != nil {
+		writeVCardLine(buf, "BDAY:"+contact.Birthdate.Format("2006-01-02"))
+	}
+	writeVCardLine(buf, "REV:"+contact.UpdatedAt.UTC().Format("20060102T150405Z"))
+	writeVCardLine(buf, "END:VCARD")
+}
+
+// normalizePhone menyamakan format nomor untuk pencocokan (+62 812-xxx == 0812xxx)
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, ch := range phone {
+		if ch >= '0' && ch <= '9' {
+			digits.WriteRune(ch)
+		}
+	}
+	normalized := digits.String()
+	if strings.HasPrefix(normalized, "62") {
+		normalized = "0" + normalized[2:]
+	}
+	return normalized
+}
+
+// @Summary Export customer contacts as vCard
+// @Description Download all contacts of a customer as a single .vcf file (vCard 3.0)
+// @Tags Contacts
+// @Produce text/vcard
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Success 200 {file} file
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/contacts.vcf [get]
+func ExportCustomerContactsVCard(c *gin.Context) {
+	customerID := c.Param("id")
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	var contacts []entity.Contact
+	if err := config.DB.Where("customer_id = ?", customer.ID).Order("main DESC, name ASC").Find(&contacts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contacts"})
+		return
+	}
+
+	var buf bytes.Buffer
+	for _, contact := range contacts {
+		formatVCard(&buf, contact, customer.Name)
+	}
+
+	sendFile(c, buf.Bytes(), customer.Code+"_contacts.vcf", "text/vcard; charset=utf-8")
+}
+
+// @Summary Export contact as vCard
+// @Description Download a single contact as a .vcf file (vCard 3.0)
+// @Tags Contacts
+// @Produce text/vcard
+// @Security BearerAuth
+// @Param id path string true "Contact ID"
+// @Success 200 {file} file
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/contacts/{id}/vcard [get]
+func ExportContactVCard(c *gin.Context) {
+	id := c.Param("id")
+
+	var contact entity.Contact
+	if err := config.DB.Preload("Customer").Where("id = ?", id).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	var buf bytes.Buffer
+	formatVCard(&buf, contact, contact.Customer.Name)
+
+	filename := strings.ReplaceAll(strings.TrimSpace(contact.Name), " ", "_")
+	if filename == "" {
+		filename = contact.ID
+	}
+	sendFile(c, buf.Bytes(), filename+".vcf", "text/vcard; charset=utf-8")
+}
+
+// @Summary Import contacts from vCard
+// @Description Upload a .vcf file (field "file") or a raw text/vcard body. Each card creates a new contact for the customer or updates an existing one matched by email or phone number.
+// @Tags Contacts
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Customer ID"
+// @Param file formData file false "vCard file"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/customers/{id}/contacts/vcard [post]
+func ImportCustomerContactsVCard(c *gin.Context) {
+	customerID := c.Param("id")
+
+	var customer entity.Customer
+	if err := config.DB.Where("id = ?", customerID).First(&customer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
+
+	var src io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+			return
+		}
+		defer f.Close()
+		src = f
+	} else {
+		src = c.Request.Body
+	}
+
+	data, err := io.ReadAll(io.LimitReader(src, vcardMaxImportSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read vCard data"})
+		return
+	}
+	if len(data) > vcardMaxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "vCard file must not be larger than 2 MB"})
+		return
+	}
+
+	cards, err := parseVCards(bytes.NewReader(data))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(cards) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No vCard found in upload"})
+		return
+	}
+
+	var existing []entity.Contact
+	if err := config.DB.Where("customer_id = ?", customer.ID).Find(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contacts"})
+		return
+	}
+
+	// index contact yang sudah ada berdasarkan email dan nomor telepon
+	byEmail := make(map[string]*entity.Contact)
+	byPhone := make(map[string]*entity.Contact)
+	index := func(contact *entity.Contact) {
+		if contact.Email != "" {
+			byEmail[strings.ToLower(contact.Email)] = contact
+		}
+		for _, phone := range []string{contact.Phone, contact.Mobile} {
+			if normalized := normalizePhone(phone); normalized != "" {
+				byPhone[normalized] = contact
+			}
+		}
+	}
+	for i := range existing {
+		index(&existing[i])
+	}
+
+	created, updated := 0, 0
+	skipped := []gin.H{}
+	results := []entity.Contact{}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for i, card := range cards {
+			var match *entity.Contact
+			for _, email := range card.Emails {
+				if contact, ok := byEmail[strings.ToLower(email)]; ok {
+					match = contact
+					break
+				}
+			}
+			if match == nil {
+				for _, phone := range []string{card.Mobile, card.Phone} {
+					if contact, ok := byPhone[normalizePhone(phone)]; ok && normalizePhone(phone) != "" {
+						match = contact
+						break
+					}
+				}
+			}
+
+			if match == nil {
+				if card.Name == "" {
+					skipped = append(skipped, gin.H{"index": i, "reason": "card has no name"})
+					continue
+				}
+				contact := entity.Contact{
+					CustomerID:  customer.ID,
+					Name:        card.Name,
+					JobPosition: card.JobPosition,
+					Position:    card.Position,
+					Department:  card.Department,
+					Phone:       card.Phone,
+					Mobile:      card.Mobile,
+					Birthdate:   card.Birthdate,
+					Active:      true,
+				}
+				if len(card.Emails) > 0 {
+					contact.Email = card.Emails[0]
+				}
+				if err := tx.Create(&contact).Error; err != nil {
+					return err
+				}
+				created++
+				results = append(results, contact)
+				stored := contact
+				index(&stored)
+				continue
+			}
+
+			// update hanya field yang terisi di vCard
+			if card.Name != "" {
+				match.Name = card.Name
+			}
+			if card.JobPosition != "" {
+				match.JobPosition = card.JobPosition
+			}
+			if card.Position != "" {
+				match.Position = card.Position
+			}
+			if card.Department != "" {
+				match.Department = card.Department
+			}
+			if match.Email == "" && len(card.Emails) > 0 {
+				match.Email = card.Emails[0]
+			}
+			if card.Phone != "" {
+				match.Phone = card.Phone
+			}
+			if card.Mobile != "" {
+				match.Mobile = card.Mobile
+			}
+			if card.Birthdate != nil {
+				match.Birthdate = card.Birthdate
+			}
+			if err := tx.Omit("Customer").Save(match).Error; err != nil {
+				return err
+			}
+			updated++
+			results = append(results, *match)
+			index(match)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import contacts: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "vCard imported successfully",
+		"created":  created,
+		"updated":  updated,
+		"skipped":  skipped,
+		"contacts": results,
+	})
+}
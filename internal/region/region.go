@@ -0,0 +1,436 @@
+// Package region menyediakan data referensi wilayah administratif Indonesia
+// (provinsi, kabupaten/kota, kecamatan, desa/kelurahan) yang dimuat dari CSV.
+package region
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed data/*.csv
+var bundled embed.FS
+
+// Level wilayah
+const (
+	LevelProvince = "province"
+	LevelRegency  = "regency"
+	LevelDistrict = "district"
+	LevelVillage  = "village"
+)
+
+// Region adalah satu wilayah pada level manapun
+type Region struct {
+	Code       string `json:"code" example:"31.71.02"`
+	ParentCode string `json:"parent_code,omitempty" example:"31.71"`
+	Name       string `json:"name" example:"SETIABUDI"`
+	Level      string `json:"level" example:"district"`
+	PostalCode string `json:"postal_code,omitempty" example:"12910"`
+}
+
+// Path berisi wilayah dari provinsi sampai level paling dalam yang diketahui
+type Path struct {
+	Province *Region `json:"province,omitempty"`
+	Regency  *Region `json:"regency,omitempty"`
+	District *Region `json:"district,omitempty"`
+	Village  *Region `json:"village,omitempty"`
+}
+
+type store struct {
+	byCode         map[string]*Region
+	children       map[string][]*Region // parent code -> anak; "" untuk provinsi
+	byPostalCode   map[string][]*Region // kode pos -> kelurahan
+	postalComplete map[string]bool      // kode wilayah -> semua kelurahannya punya kode pos
+}
+
+var (
+	loadOnce sync.Once
+	data     *store
+)
+
+var postalCodePattern = regexp.MustCompile(`^[1-9][0-9]{4}$`)
+
+// codePatterns adalah format kode Kemendagri per level
+var codePatterns = map[string]*regexp.Regexp{
+	LevelProvince: regexp.MustCompile(`^[0-9]{2}$`),
+	LevelRegency:  regexp.MustCompile(`^[0-9]{2}\.[0-9]{2}$`),
+	LevelDistrict: regexp.MustCompile(`^[0-9]{2}\.[0-9]{2}\.[0-9]{2}$`),
+	LevelVillage:  regexp.MustCompile(`^[0-9]{2}\.[0-9]{2}\.[0-9]{2}\.[0-9]{4}$`),
+}
+
+// levelOf menentukan level wilayah dari jumlah segmen kode (11, 11.01, 11.01.01, 11.01.01.2001)
+func levelOf(code string) string {
+	switch strings.Count(code, ".") {
+	case 0:
+		return LevelProvince
+	case 1:
+		return LevelRegency
+	case 2:
+		return LevelDistrict
+	case 3:
+		return LevelVillage
+	}
+	return ""
+}
+
+// parentOf mengembalikan kode induk dari sebuah kode wilayah
+func parentOf(code string) string {
+	if i := strings.LastIndex(code, "."); i >= 0 {
+		return code[:i]
+	}
+	return ""
+}
+
+// openFile membuka file dari REGION_DATA_DIR jika ada, selain itu dari data bawaan
+func openFile(name string) (io.ReadCloser, error) {
+	if dir := os.Getenv("REGION_DATA_DIR"); dir != "" {
+		f, err := os.Open(dir + string(os.PathSeparator) + name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return bundled.Open("data/" + name)
+}
+
+func readCSV(name string, fn func(row map[string]string)) error {
+	f, err := openFile(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = strings.TrimSpace(record[i])
+			}
+		}
+		fn(row)
+	}
+}
+
+func load() *store {
+	loadOnce.Do(func() {
+		s := &store{
+			byCode:         map[string]*Region{},
+			children:       map[string][]*Region{},
+			byPostalCode:   map[string][]*Region{},
+			postalComplete: map[string]bool{},
+		}
+		files := []struct {
+			name, parentCol string
+		}{
+			{"provinces.csv", ""},
+			{"regencies.csv", "province_code"},
+			{"districts.csv", "regency_code"},
+			{"villages.csv", "district_code"},
+		}
+		for _, file := range files {
+			err := readCSV(file.name, func(row map[string]string) {
+				code := row["code"]
+				if code == "" || row["name"] == "" {
+					return
+				}
+				r := &Region{
+					Code:       code,
+					ParentCode: row[file.parentCol],
+					Name:       row["name"],
+					Level:      levelOf(code),
+					PostalCode: row["postal_code"],
+				}
+				if r.ParentCode == "" {
+					r.ParentCode = parentOf(code)
+				}
+				s.byCode[code] = r
+				s.children[r.ParentCode] = append(s.children[r.ParentCode], r)
+				if r.Level == LevelVillage && r.PostalCode != "" {
+					s.byPostalCode[r.PostalCode] = append(s.byPostalCode[r.PostalCode], r)
+				}
+			})
+			if err != nil {
+				log.Printf("failed to load region data: %v", err)
+			}
+		}
+		for _, list := range s.children {
+			sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+		}
+		for _, province := range s.children[""] {
+			s.markPostalComplete(province)
+		}
+		data = s
+	})
+	return data
+}
+
+// Find mengembalikan wilayah berdasarkan kode
+func Find(code string) (*Region, bool) {
+	r, ok := load().byCode[strings.TrimSpace(code)]
+	return r, ok
+}
+
+// Children mengembalikan wilayah anak dari parentCode ("" untuk daftar provinsi)
+func Children(parentCode string) []Region {
+	list := load().children[strings.TrimSpace(parentCode)]
+	result := make([]Region, 0, len(list))
+	for _, r := range list {
+		result = append(result, *r)
+	}
+	return result
+}
+
+// PathOf mengembalikan wilayah dari provinsi sampai kode yang diminta
+func PathOf(code string) (Path, bool) {
+	r, ok := Find(code)
+	if !ok {
+		return Path{}, false
+	}
+	var path Path
+	for current := r; current != nil; {
+		r := *current
+		switch current.Level {
+		case LevelProvince:
+			path.Province = &r
+		case LevelRegency:
+			path.Regency = &r
+		case LevelDistrict:
+			path.District = &r
+		case LevelVillage:
+			path.Village = &r
+		}
+		parent, ok := Find(current.ParentCode)
+		if !ok || current.ParentCode == "" {
+			break
+		}
+		current = parent
+	}
+	return path, true
+}
+
+// Search mencari wilayah yang namanya mengandung query, untuk autocomplete.
+// level dan parentCode (awalan kode) bersifat opsional.
+func Search(query, level, parentCode string, limit int) []Region {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	s := load()
+
+	type match struct {
+		region *Region
+		rank   int
+	}
+	matches := []match{}
+	for _, r := range s.byCode {
+		if level != "" && r.Level != level {
+			continue
+		}
+		if parentCode != "" && !strings.HasPrefix(r.Code, parentCode+".") {
+			continue
+		}
+		name := strings.ToUpper(r.Name)
+		rank := -1
+		switch {
+		case query == "":
+			rank = 2
+		case name == query:
+			rank = 0
+		case strings.HasPrefix(name, query) || strings.Contains(name, " "+query):
+			rank = 1
+		case strings.Contains(name, query):
+			rank = 2
+		}
+		if rank >= 0 {
+			matches = append(matches, match{r, rank})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].region.Code < matches[j].region.Code
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]Region, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, *m.region)
+	}
+	return result
+}
+
+// Codes adalah kode wilayah sebuah alamat; boleh hanya diisi sebagian
+type Codes struct {
+	ProvinceCode string
+	RegencyCode  string
+	DistrictCode string
+	VillageCode  string
+	PostalCode   string
+}
+
+// Resolve memvalidasi kode wilayah alamat: setiap kode harus berformat benar, berada di provinsi
+// yang dikenal, konsisten dengan induknya, dan kode pos (jika diisi) harus cocok dengan wilayahnya.
+// Kode induk yang kosong dilengkapi dari kode yang lebih dalam. Kode yang tidak ada di data
+// (data bawaan tidak lengkap) tetap diterima; Path hanya berisi wilayah yang dikenal.
+func Resolve(codes Codes) (Codes, Path, error) {
+	codes.ProvinceCode = strings.TrimSpace(codes.ProvinceCode)
+	codes.RegencyCode = strings.TrimSpace(codes.RegencyCode)
+	codes.DistrictCode = strings.TrimSpace(codes.DistrictCode)
+	codes.VillageCode = strings.TrimSpace(codes.VillageCode)
+	codes.PostalCode = strings.TrimSpace(codes.PostalCode)
+
+	if codes.PostalCode != "" && !postalCodePattern.MatchString(codes.PostalCode) {
+		return codes, Path{}, fmt.Errorf("postal code must be 5 digits")
+	}
+
+	levels := []struct {
+		code  *string
+		level string
+	}{
+		{&codes.VillageCode, LevelVillage},
+		{&codes.DistrictCode, LevelDistrict},
+		{&codes.RegencyCode, LevelRegency},
+		{&codes.ProvinceCode, LevelProvince},
+	}
+
+	// cari kode paling dalam yang diisi
+	deepest := ""
+	for _, l := range levels {
+		if *l.code == "" {
+			continue
+		}
+		if !codePatterns[l.level].MatchString(*l.code) {
+			return codes, Path{}, fmt.Errorf("invalid %s code '%s'", l.level, *l.code)
+		}
+		if deepest == "" {
+			deepest = *l.code
+		}
+	}
+	if deepest == "" {
+		return codes, Path{}, nil
+	}
+	// data provinsi selalu lengkap; level di bawahnya boleh tidak ada di data
+	if _, ok := Find(deepest[:2]); !ok {
+		return codes, Path{}, fmt.Errorf("unknown province code '%s'", deepest[:2])
+	}
+
+	// kode Kemendagri hierarkis: kode induk adalah awalan kode anaknya
+	var path Path
+	for _, l := range levels {
+		want := deepest
+		for levelOf(want) != l.level && want != "" {
+			want = parentOf(want)
+		}
+		if want == "" {
+			continue
+		}
+		if *l.code != "" && *l.code != want {
+			return codes, Path{}, fmt.Errorf("%s code '%s' does not belong to '%s'", l.level, *l.code, deepest)
+		}
+		*l.code = want
+		if r, ok := Find(want); ok {
+			region := *r
+			switch l.level {
+			case LevelProvince:
+				path.Province = &region
+			case LevelRegency:
+				path.Regency = &region
+			case LevelDistrict:
+				path.District = &region
+			case LevelVillage:
+				path.Village = &region
+			}
+		}
+	}
+
+	// kode pos tidak bisa divalidasi jika wilayah terdalam tidak ada di data
+	if _, known := Find(deepest); known && codes.PostalCode != "" {
+		if err := checkPostalCode(path, codes.PostalCode); err != nil {
+			return codes, Path{}, err
+		}
+	}
+	return codes, path, nil
+}
+
+// checkPostalCode mencocokkan kode pos dengan kelurahan, atau dengan kumpulan kode pos
+// kelurahan di bawah wilayah terdalam jika kelurahan tidak diisi
+func checkPostalCode(path Path, postalCode string) error {
+	if path.Village != nil && path.Village.PostalCode != "" {
+		if path.Village.PostalCode != postalCode {
+			return fmt.Errorf("postal code %s does not match %s (expected %s)", postalCode, path.Village.Name, path.Village.PostalCode)
+		}
+		return nil
+	}
+
+	var area *Region
+	for _, r := range []*Region{path.District, path.Regency, path.Province} {
+		if r != nil {
+			area = r
+			break
+		}
+	}
+	if area == nil {
+		return nil
+	}
+
+	// data bawaan bisa tidak lengkap; validasi hanya jika semua kelurahan di wilayah ini punya kode pos
+	s := load()
+	if !s.postalComplete[area.Code] {
+		return nil
+	}
+	for _, village := range s.byPostalCode[postalCode] {
+		if strings.HasPrefix(village.Code, area.Code+".") {
+			return nil
+		}
+	}
+	return fmt.Errorf("postal code %s is not used in %s", postalCode, area.Name)
+}
+
+// markPostalComplete mencatat apakah wilayah punya turunan sampai level kelurahan
+// dan semua kelurahannya punya kode pos
+func (s *store) markPostalComplete(r *Region) bool {
+	complete := r.PostalCode != ""
+	if r.Level != LevelVillage {
+		children := s.children[r.Code]
+		complete = len(children) > 0
+		for _, child := range children {
+			if !s.markPostalComplete(child) {
+				complete = false
+			}
+		}
+	}
+	s.postalComplete[r.Code] = complete
+	return complete
+}
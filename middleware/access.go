@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"customer-api/internal/access"
+	"customer-api/internal/config"
+	"customer-api/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustomerAccessMiddleware menerapkan access policy pada route customer dan turunannya:
+// role read-only tidak boleh menulis, dan record milik customer di luar scope user
+// diperlakukan seperti tidak ada. Daftar/export/search difilter di handler masing-masing.
+func CustomerAccessMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, err := access.FromContext(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve access policy"})
+			c.Abort()
+			return
+		}
+
+		write := c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead
+		if write && policy.ReadOnly && !policy.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Role anda hanya memiliki akses baca"})
+			c.Abort()
+			return
+		}
+		if !policy.Restricted() {
+			c.Next()
+			return
+		}
+
+		customerIDs := []string{}
+		if resource := access.ResourceForRoute(c.FullPath()); resource != "" {
+			id, found := access.CustomerOf(config.DB, resource, c.Param("id"))
+			if !found {
+				// record tidak ada: biarkan handler mengembalikan 404
+				c.Next()
+				return
+			}
+			customerIDs = append(customerIDs, id)
+		}
+		if write {
+			// body juga diperiksa pada PUT agar record tidak dipindah ke customer lain
+			customerIDs = append(customerIDs, bodyCustomerIDs(c)...)
+		}
+		if len(customerIDs) == 0 {
+			c.Next()
+			return
+		}
+
+		var customers []entity.Customer
+		if err := config.DB.Select("id, account_manager_id, team_id").Where("id IN ?", customerIDs).Find(&customers).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve access policy"})
+			c.Abort()
+			return
+		}
+		// customer yang tidak ada dibiarkan ke handler yang mengembalikan 404/400
+		for _, customer := range customers {
+			if visible, _ := policy.CanSee(customer); !visible {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// bodyCustomerIDs membaca semua customer ID dari body JSON tanpa mengonsumsi body untuk
+// handler: field customer_id, *_customer_id dan array customer_ids di level manapun
+func bodyCustomerIDs(c *gin.Context) []string {
+	if c.Request.Body == nil || c.ContentType() != "application/json" {
+		return nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	var payload interface{}
+	if json.Unmarshal(body, &payload) != nil {
+		return nil
+	}
+	ids := []string{}
+	collectCustomerIDs(payload, &ids)
+	return ids
+}
+
+// collectCustomerIDs menelusuri payload JSON dan mengumpulkan nilai field customer ID
+func collectCustomerIDs(value interface{}, ids *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if key == "customer_id" || strings.HasSuffix(key, "_customer_id") || key == "customer_ids" {
+				appendCustomerIDs(field, ids)
+				continue
+			}
+			collectCustomerIDs(field, ids)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectCustomerIDs(item, ids)
+		}
+	}
+}
+
+// appendCustomerIDs menambahkan string atau array string ke daftar ID
+func appendCustomerIDs(value interface{}, ids *[]string) {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			*ids = append(*ids, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			appendCustomerIDs(item, ids)
+		}
+	}
+}
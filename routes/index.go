@@ -19,26 +19,43 @@ func RegisterRoutes(r *gin.Engine) {
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware())
 
+	// Customer dan data turunannya dibatasi oleh access policy role user
+	customerScoped := protected.Group("")
+	customerScoped.Use(middleware.CustomerAccessMiddleware())
+
 	// Register all modules
 	route.RegisterRoleRoutes(protected)
 	route.RegisterAccountManagerRoutes(protected)
-	route.RegisterCustomerRoutes(protected)
-	route.RegisterAddressRoutes(protected)
-	route.RegisterSosmedRoutes(protected)
-	route.RegisterContactRoutes(protected)
-	route.RegisterStructureRoutes(protected)
+	route.RegisterAccountManagerCustomerRoutes(customerScoped)
+	route.RegisterCustomerRoutes(customerScoped)
+	route.RegisterAddressRoutes(customerScoped)
+	route.RegisterSosmedRoutes(customerScoped)
+	route.RegisterContactRoutes(customerScoped)
+	route.RegisterStructureRoutes(customerScoped)
 	route.RegisterGroupRoutes(protected)
-	route.RegisterOtherRoutes(protected)
-	route.RegisterActivityRoutes(protected)
+	route.RegisterOtherRoutes(customerScoped)
+	route.RegisterActivityRoutes(customerScoped)
 	route.RegisterInvoiceRoutes(protected)
 	route.RegisterPaymentRoutes(protected)
 	route.RegisterStatusRoutes(protected)
-	route.RegisterEventsRoutes(protected)
-	route.RegisterProjectRoutes(protected)
+	route.RegisterEventsRoutes(customerScoped)
+	route.RegisterProjectRoutes(customerScoped)
 	route.RegisterActivityTypeRoutes(protected)
 	route.RegisterStagesRoutes(protected)
 	route.RegisterWorkflowsRoutes(protected)
 	route.RegisterGroupConfig(protected)
+	route.RegisterOtherConfig(protected)
 	route.RegisterAssessmentRoutes(protected)
+	route.RegisterDocumentRoutes(customerScoped)
+	route.RegisterReminderRoutes(customerScoped)
+	route.RegisterRelationshipRoutes(customerScoped)
+	route.RegisterHealthRoutes(customerScoped)
+	route.RegisterOpportunityRoutes(customerScoped)
+	route.RegisterTimesheetRoutes(customerScoped)
+	route.RegisterRegionRoutes(protected)
+	route.RegisterMeRoutes(protected)
+	route.RegisterTeamsRoutes(protected)
+	route.RegisterTeamCustomerRoutes(customerScoped)
+	route.RegisterAccessRoutes(protected)
 
 }
@@ -0,0 +1,14 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAccessRoutes(r *gin.RouterGroup) {
+	r.GET("/access-policies", handler.GetAccessPolicies)
+	r.PUT("/access-policies/:role_id", handler.SetAccessPolicy)
+	r.DELETE("/access-policies/:role_id", handler.DeleteAccessPolicy)
+	r.GET("/access/explain", handler.ExplainAccess)
+}
@@ -13,4 +13,15 @@ func RegisterAccountManagerRoutes(r *gin.RouterGroup) {
 	r.GET("/account-managers/:id", handler.GetAccountManager)
 	r.PUT("/account-managers/:id", handler.UpdateAccountManager)
 	r.DELETE("/account-managers/:id", handler.DeleteAccountManager)
+
+	// Portofolio & reassign
+	r.GET("/account-managers/portfolio", handler.GetAccountManagerPortfolios)
+	r.GET("/account-managers/:id/portfolio", handler.GetAccountManagerPortfolio)
+}
+
+// RegisterAccountManagerCustomerRoutes berisi route account manager yang membaca atau
+// mengubah customer, didaftarkan pada group yang dibatasi access policy
+func RegisterAccountManagerCustomerRoutes(r *gin.RouterGroup) {
+	r.POST("/account-managers/:id/reassign", handler.ReassignAccountManagerCustomers)
+	r.GET("/customers/:id/ownership-history", handler.GetCustomerOwnershipHistory)
 }
@@ -19,4 +19,7 @@ func RegisterActivityRoutes(r *gin.RouterGroup) {
 
 	// Check-in
 	r.POST("/activities/:id/checkin", handler.CheckinActivity)
+
+	// Route planning
+	r.POST("/activities/route-plan", handler.PlanVisitRoute)
 }
@@ -8,8 +8,13 @@ import (
 
 func RegisterAddressRoutes(r *gin.RouterGroup) {
 	r.GET("/customers/:id/addresses", handler.GetCustomerAddresses)
+	r.POST("/customers/:id/addresses", handler.CreateAddress)
 	r.GET("/addresses/:id", handler.GetAddress)
 	r.PUT("/addresses/:id", handler.UpdateAddress)
 	r.DELETE("/addresses/:id", handler.DeleteAddress)
 	r.POST("/addresses", handler.CreateAddress)
+	// Koordinat
+	r.PUT("/addresses/:id/coordinates", handler.UpdateAddressCoordinates)
+	r.POST("/addresses/coordinates/import", handler.ImportAddressCoordinates)
+	r.GET("/customers/nearby", handler.GetNearbyCustomers)
 }
@@ -12,4 +12,12 @@ func RegisterContactRoutes(r *gin.RouterGroup) {
 	r.PUT("/contacts/:id", handler.UpdateContact)
 	r.DELETE("/contacts/:id", handler.DeleteContact)
 	r.POST("/contacts", handler.CreateContact)
+	// vCard
+	r.GET("/customers/:id/contacts.vcf", handler.ExportCustomerContactsVCard)
+	r.POST("/customers/:id/contacts/vcard", handler.ImportCustomerContactsVCard)
+	r.GET("/contacts/:id/vcard", handler.ExportContactVCard)
+	// Org chart & reporting lines
+	r.PUT("/contacts/:id/placement", handler.UpdateContactPlacement)
+	r.GET("/contacts/:id/reporting-chain", handler.GetContactReportingChain)
+	r.GET("/customers/:id/org-chart", handler.GetCustomerOrgChart)
 }
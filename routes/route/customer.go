@@ -20,6 +20,7 @@ func RegisterCustomerRoutes(r *gin.RouterGroup) {
 	r.GET("/customers/:id/history", handler.GetHistoryCustomerByUserID)
 
 	r.GET("/customers/:id", handler.GetCustomer)
+	r.GET("/customers/:id/hierarchy", handler.GetCustomerHierarchy) // pohon induk & anak perusahaan
 	r.GET("/customers/:id/with-addresses", handler.GetCustomerWithAddresses)
 	r.GET("/customers/:id/with-sosmeds", handler.GetCustomerWithSosmeds)
 	r.GET("/customers/:id/with-contacts", handler.GetCustomerWithContacts)
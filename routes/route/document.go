@@ -0,0 +1,20 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDocumentRoutes(r *gin.RouterGroup) {
+	r.GET("/customers/:id/documents", handler.GetCustomerDocuments)
+	r.POST("/customers/:id/documents", handler.UploadCustomerDocument)
+	r.GET("/documents/expiring", handler.GetExpiringDocuments)
+	r.GET("/documents/:id", handler.GetDocument)
+	r.GET("/documents/:id/download", handler.DownloadDocument)
+	r.PUT("/documents/:id", handler.UpdateDocument)
+	r.DELETE("/documents/:id", handler.DeleteDocument)
+	// version history
+	r.GET("/documents/:id/versions", handler.GetDocumentVersions)
+	r.POST("/documents/:id/versions", handler.ReplaceDocument)
+}
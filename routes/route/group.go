@@ -8,14 +8,23 @@ import (
 
 func RegisterGroupRoutes(r *gin.RouterGroup) {
 	r.GET("/groups", handler.GetGroups)
+	r.GET("/groups/tree", handler.GetGroupTree)
 	r.GET("/groups/:id", handler.GetGroup)
 	r.PUT("/groups/:id", handler.UpdateGroup)
 	r.DELETE("/groups/:id", handler.DeleteGroup)
 	r.GET("/groups/:id/customers", handler.GetGroupCustomers)
+	r.GET("/groups/:id/subtree", handler.GetGroupSubtree)
+	r.GET("/groups/:id/subtree/customers", handler.GetGroupSubtreeCustomers)
 
 	// Nested resource
 	r.PUT("/groups/:id/customers/:customer_id", handler.AssignCustomerToGroup)
 	r.DELETE("/groups/:id/customers/:customer_id", handler.RemoveCustomerFromGroup)
 
 	r.POST("/groups", handler.CreateGroup)
+
+	// Jenis group
+	r.GET("/group-types", handler.GetGroupTypes)
+	r.POST("/group-types", handler.CreateGroupType)
+	r.PUT("/group-types/:id", handler.UpdateGroupType)
+	r.DELETE("/group-types/:id", handler.DeleteGroupType)
 }
@@ -9,9 +9,11 @@ import (
 func RegisterGroupConfig(r *gin.RouterGroup) {
 	r.POST("/group-configs", handler.CreateConfigGroup)
 	r.GET("/group-configs", handler.GetConfigGroups)
+	r.GET("/group-configs/segments", handler.GetSegmentDashboard) // Segmentasi semua config aktif untuk dashboard
 	r.GET("/group-configs/:id", handler.GetConfigGroup)
 	r.PUT("/group-configs/:id", handler.UpdateConfigGroup)
 	r.DELETE("/group-configs/:id", handler.DeleteConfigGroup)
+	r.GET("/group-configs/:id/segments", handler.GetGroupConfigSegments) // Jumlah customer & AverageCost per bucket
 	// detail group-configs
 	r.GET("/group-configs/:id/details", handler.GetConfigGroupDetails)                  // Amb
 	r.POST("/group-configs/:id/details", handler.CreateConfigGroupDetail)               // Buat detail group-config baru
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterHealthRoutes(r *gin.RouterGroup) {
+	r.GET("/customers/:id/health", handler.GetCustomerHealth)                  // skor, breakdown & riwayat
+	r.POST("/customers/:id/health/recompute", handler.RecomputeCustomerHealth) // hitung ulang sekarang
+	r.GET("/customers/:id/assessments", handler.GetCustomerAssessments)
+	r.POST("/customers/:id/assessments", handler.CreateCustomerAssessment)
+
+	// konfigurasi sinyal (admin)
+	r.GET("/health-signals", handler.GetHealthSignals)
+	r.PUT("/health-signals/:id", handler.UpdateHealthSignal)
+	r.POST("/health-scores/recompute", handler.RecomputeAllHealthScores)
+}
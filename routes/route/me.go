@@ -0,0 +1,14 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterMeRoutes(r *gin.RouterGroup) {
+	r.GET("/me", handler.GetMe)
+	r.GET("/me/customers", handler.GetMyCustomers)
+	r.GET("/me/activities", handler.GetMyActivities)
+	r.GET("/me/events", handler.GetMyEvents)
+}
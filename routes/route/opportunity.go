@@ -0,0 +1,21 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOpportunityRoutes(r *gin.RouterGroup) {
+	r.GET("/opportunities", handler.GetOpportunities)
+	r.GET("/opportunities/kanban", handler.GetOpportunityKanban)
+	r.GET("/opportunities/forecast", handler.GetOpportunityForecast)
+	r.POST("/opportunities", handler.CreateOpportunity)
+	r.GET("/opportunities/:id", handler.GetOpportunity)
+	r.PUT("/opportunities/:id", handler.UpdateOpportunity)
+	r.DELETE("/opportunities/:id", handler.DeleteOpportunity)
+
+	// perpindahan stage dan penutupan (won/lost) tercatat di riwayat stage
+	r.POST("/opportunities/:id/stage", handler.MoveOpportunityStage)
+	r.POST("/opportunities/:id/close", handler.CloseOpportunity)
+}
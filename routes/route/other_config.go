@@ -7,16 +7,16 @@ import (
 )
 
 func RegisterOtherConfig(r *gin.RouterGroup) {
-	r.POST("/other-configs", handler.CreateConfigGroup)
-	r.GET("/other-configs", handler.GetConfigGroups)
-	r.GET("/other-configs/:id", handler.GetConfigGroup)
-	r.PUT("/other-configs/:id", handler.UpdateConfigGroup)
-	r.DELETE("/other-configs/:id", handler.DeleteConfigGroup)
-	// detail group-configs
-	r.GET("/other-configs/:id/details", handler.GetConfigGroupDetails)    // Amb
-	r.POST("/other-configs/:id/details", handler.CreateConfigGroupDetail) // Buat detail group-config baru
-	r.GET("/other-configs/:id/details/:detail_id", handler.GetConfigGroupDetail)
-	r.PUT("/other-configs/:id/details/:detail_id", handler.UpdateConfigGroupDetail) // Update detail group-config
-	r.DELETE("/other-configs/:id/details/:detail_id", handler.DeleteConfigGroupDetail)
+	r.POST("/other-configs", handler.CreateConfigOther)
+	r.GET("/other-configs", handler.GetConfigOther)
+	r.GET("/other-configs/:id", handler.GetConfigOtherDetail)
+	r.PUT("/other-configs/:id", handler.UpdateConfigOther)
+	r.DELETE("/other-configs/:id", handler.DeleteConfigOther)
+	// pilihan enum custom field
+	r.GET("/other-configs/:id/details", handler.GetConfigOtherDetails)
+	r.POST("/other-configs/:id/details", handler.CreateConfigOtherDetail)
+	r.GET("/other-configs/:id/details/:detail_id", handler.GetConfigOthersDetail)
+	r.PUT("/other-configs/:id/details/:detail_id", handler.UpdateConfigOtherDetail)
+	r.DELETE("/other-configs/:id/details/:detail_id", handler.DeleteConfigOtherDetail)
 
 }
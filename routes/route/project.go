@@ -12,4 +12,23 @@ func RegisterProjectRoutes(r *gin.RouterGroup) {
 	r.GET("/projects/:id", handler.ReadOneProject)
 	r.PUT("/projects/:id", handler.UpdateProject)
 	r.DELETE("/projects/:id", handler.DeleteProject)
+
+	// anggota dan milestone project
+	r.GET("/projects/:id/members", handler.GetProjectMembers)
+	r.POST("/projects/:id/members", handler.AddProjectMember)
+	r.PUT("/projects/:id/members/:member_id", handler.UpdateProjectMember)
+	r.DELETE("/projects/:id/members/:member_id", handler.RemoveProjectMember)
+	r.GET("/projects/:id/milestones", handler.GetProjectMilestones)
+	r.POST("/projects/:id/milestones", handler.CreateProjectMilestone)
+	r.PUT("/projects/:id/milestones/:milestone_id", handler.UpdateProjectMilestone)
+	r.DELETE("/projects/:id/milestones/:milestone_id", handler.DeleteProjectMilestone)
+
+	// data terkait project dan budget vs invoice
+	r.GET("/projects/:id/events", handler.GetProjectEvents)
+	r.GET("/projects/:id/activities", handler.GetProjectActivities)
+	r.GET("/projects/:id/invoices", handler.GetProjectInvoices)
+	r.GET("/projects/:id/budget", handler.GetProjectBudget)
+
+	// jam billable yang sudah disetujui menjadi baris invoice
+	r.POST("/projects/:id/invoice-time", handler.InvoiceProjectTime)
 }
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRegionRoutes(r *gin.RouterGroup) {
+	r.GET("/regions/provinces", handler.GetProvinces)
+	r.GET("/regions/provinces/:code/regencies", handler.GetRegencies)
+	r.GET("/regions/regencies/:code/districts", handler.GetDistricts)
+	r.GET("/regions/districts/:code/villages", handler.GetVillages)
+	r.GET("/regions/lookup/:code", handler.GetRegion)
+	r.GET("/regions/search", handler.SearchRegions)
+}
@@ -0,0 +1,20 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRelationshipRoutes(r *gin.RouterGroup) {
+	r.GET("/relationships", handler.GetRelationships)
+	r.GET("/relationships/types", handler.GetRelationshipTypes)
+	r.POST("/relationships", handler.CreateRelationship)
+	r.GET("/relationships/:id", handler.GetRelationship)
+	r.PUT("/relationships/:id", handler.UpdateRelationship)
+	r.DELETE("/relationships/:id", handler.DeleteRelationship)
+
+	// relasi dilihat dari sisi customer (termasuk reverse lookup) dan graph N-hop
+	r.GET("/customers/:id/relationships", handler.GetCustomerRelationships)
+	r.GET("/customers/:id/relationship-graph", handler.GetCustomerRelationshipGraph)
+}
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReminderRoutes(r *gin.RouterGroup) {
+	r.GET("/reminders/birthdays", handler.GetUpcomingBirthdays)
+	r.GET("/reminders/upcoming", handler.GetUpcomingReminders)
+	r.GET("/reminders/digest", handler.GetReminderDigest)
+	r.POST("/reminders/digest/send", handler.SendReminderDigest)
+	// Tanggal relasi custom per contact
+	r.GET("/contacts/:id/dates", handler.GetContactDates)
+	r.POST("/contacts/:id/dates", handler.CreateContactDate)
+	r.PUT("/contact-dates/:id", handler.UpdateContactDate)
+	r.DELETE("/contact-dates/:id", handler.DeleteContactDate)
+}
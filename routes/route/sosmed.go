@@ -8,7 +8,17 @@ import (
 
 func RegisterSosmedRoutes(r *gin.RouterGroup) {
 	r.GET("/customers/:id/sosmeds", handler.GetCustomerSosmeds)
+	r.POST("/customers/:id/sosmeds", handler.CreateSosmed)
+	r.GET("/social-platforms", handler.GetSocialPlatforms)
 	r.GET("/sosmeds/:id", handler.GetSosmed)
 	r.PUT("/sosmeds/:id", handler.UpdateSosmed)
 	r.DELETE("/sosmeds/:id", handler.DeleteSosmed)
+	// Follower snapshots & growth
+	r.GET("/sosmeds/:id/snapshots", handler.GetSosmedSnapshots)
+	r.POST("/sosmeds/:id/snapshots", handler.CreateSosmedSnapshot)
+	r.DELETE("/sosmed-snapshots/:id", handler.DeleteSosmedSnapshot)
+	r.POST("/sosmed-snapshots/import", handler.ImportSosmedSnapshots)
+	r.GET("/sosmeds/:id/growth", handler.GetSosmedGrowth)
+	r.GET("/customers/:id/sosmeds/growth", handler.GetCustomerSosmedGrowth)
+	r.GET("/sosmed-growth/top", handler.GetTopSosmedGrowers)
 }
@@ -8,9 +8,13 @@ import (
 
 func RegisterStructureRoutes(r *gin.RouterGroup) {
 	r.GET("/customers/:id/structures", handler.GetCustomerStructures)
+	r.POST("/customers/:id/structures", handler.CreateStructure)
 	r.GET("/customers/:id/structures/by-level", handler.GetStructuresByLevel)
+	r.GET("/customers/:id/structures/tree", handler.GetCustomerStructureTree)
+	r.PUT("/customers/:id/structures/reorder", handler.ReorderStructures)
 	r.GET("/structures/:id", handler.GetStructure)
 	r.PUT("/structures/:id", handler.UpdateStructure)
 	r.DELETE("/structures/:id", handler.DeleteStructure)
+	r.POST("/structures/:id/move", handler.MoveStructure)
 	r.POST("/structures", handler.CreateStructure)
 }
@@ -9,14 +9,34 @@ import (
 func RegisterTeamsRoutes(r *gin.RouterGroup) {
 	r.POST("/teams", handler.CreateTeam)
 	r.GET("/teams", handler.GetTeams)
-	r.GET("/teams/:id", handler.GetTeams)
+	r.POST("/teams/auto-assign", handler.AutoAssignTeams)
+	r.GET("/teams/:id", handler.GetTeam)
 	r.PUT("/teams/:id", handler.UpdateTeam)
 	r.DELETE("/teams/:id", handler.DeleteTeam)
 
+	// anggota, territory dan customer milik team
+	r.GET("/teams/:id/members", handler.GetTeamMembers)
+	r.POST("/teams/:id/members", handler.AddTeamMember)
+	r.GET("/teams/:id/territories", handler.GetTeamTerritories)
+
 	// detail teams
 	r.POST("/teams/detail", handler.CreateTeamDetail)
 	r.GET("/teams/detail", handler.GetTeamDetails)
 	r.GET("/teams/detail/:id", handler.GetTeamDetail)
 	r.PUT("/teams/detail/:id", handler.UpdateTeamDetail)
 	r.DELETE("/teams/detail/:id", handler.DeleteTeamDetail)
+
+	// territory
+	r.GET("/territories", handler.GetTerritories)
+	r.POST("/territories", handler.CreateTerritory)
+	r.PUT("/territories/:id", handler.UpdateTerritory)
+	r.DELETE("/territories/:id", handler.DeleteTerritory)
+}
+
+// RegisterTeamCustomerRoutes berisi route team yang membaca atau mengubah customer,
+// didaftarkan pada group yang dibatasi access policy
+func RegisterTeamCustomerRoutes(r *gin.RouterGroup) {
+	r.GET("/teams/:id/customers", handler.GetTeamCustomers)
+	r.POST("/teams/:id/customers", handler.AssignTeamCustomers)
+	r.PUT("/customers/:id/team", handler.UpdateCustomerTeam)
 }
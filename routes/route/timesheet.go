@@ -0,0 +1,23 @@
+package route
+
+import (
+	"customer-api/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTimesheetRoutes(r *gin.RouterGroup) {
+	r.GET("/time-entries", handler.GetTimeEntries)
+	r.GET("/time-entries/report", handler.GetTimesheetReport)
+	r.POST("/time-entries", handler.CreateTimeEntry)
+	r.GET("/time-entries/:id", handler.GetTimeEntry)
+	r.PUT("/time-entries/:id", handler.UpdateTimeEntry)
+	r.DELETE("/time-entries/:id", handler.DeleteTimeEntry)
+
+	// pengajuan mingguan, disetujui/ditolak oleh owner project
+	r.GET("/timesheets", handler.GetTimesheets)
+	r.POST("/timesheets", handler.SubmitTimesheet)
+	r.GET("/timesheets/:id", handler.GetTimesheet)
+	r.POST("/timesheets/:id/approve", handler.ApproveTimesheet)
+	r.POST("/timesheets/:id/reject", handler.RejectTimesheet)
+}